@@ -0,0 +1,137 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tknika/terraform-provider-isardvdi/internal/constants"
+)
+
+// tokenRefreshMargin es la antelación con la que se renueva el token antes de que caduque
+const tokenRefreshMargin = 60 * time.Second
+
+// formCredentials guarda las credenciales del login por formulario para poder renovar la sesión
+type formCredentials struct {
+	categoryID string
+	username   string
+	password   string
+}
+
+// loginPath devuelve el endpoint de login por formulario para la categoría configurada
+func (f *formCredentials) loginPath() string {
+	return fmt.Sprintf("%s?provider=form&category_id=%s", constants.LoginPath, url.QueryEscape(f.categoryID))
+}
+
+// formBody construye el cuerpo multipart del login por formulario
+func (f *formCredentials) formBody() ([]byte, string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	_ = writer.WriteField("username", f.username)
+	_ = writer.WriteField("password", f.password)
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return body.Bytes(), writer.FormDataContentType(), nil
+}
+
+// canReauthenticate indica si el cliente puede repetir el login por su cuenta
+func (c *Client) canReauthenticate() bool {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	return c.credentials != nil
+}
+
+// currentToken devuelve el token vigente, renovándolo antes si está a punto de caducar
+func (c *Client) currentToken(ctx context.Context) (string, error) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if c.credentials != nil && !c.tokenExpiry.IsZero() && time.Until(c.tokenExpiry) < tokenRefreshMargin {
+		if err := c.login(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	return c.Token, nil
+}
+
+// reauthenticate repite el login tras un 401. Si otra operación ya ha renovado
+// el token mientras se esperaba el mutex, reutiliza el nuevo sin volver a llamar al login.
+func (c *Client) reauthenticate(ctx context.Context, staleToken string) (string, error) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if c.Token != staleToken {
+		return c.Token, nil
+	}
+
+	if err := c.login(ctx); err != nil {
+		return "", err
+	}
+
+	return c.Token, nil
+}
+
+// login ejecuta el login por formulario con las credenciales guardadas.
+// Debe llamarse con authMu bloqueado.
+func (c *Client) login(ctx context.Context) error {
+	body, contentType, err := c.credentials.formBody()
+	if err != nil {
+		return err
+	}
+
+	respBody, err := c.send(ctx, &apiRequest{
+		method:      http.MethodPost,
+		path:        c.credentials.loginPath(),
+		body:        body,
+		contentType: contentType,
+		accept:      "text/plain",
+		noAuth:      true,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := c.parseAuthResponse(respBody); err != nil {
+		return err
+	}
+
+	c.tokenExpiry, _ = jwtExpiry(c.Token)
+	return nil
+}
+
+// jwtExpiry extrae la fecha de caducidad (claim "exp") de un JWT sin verificar la firma
+func jwtExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var claims struct {
+		Exp json.Number `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == "" {
+		return time.Time{}, false
+	}
+
+	exp, err := claims.Exp.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(exp), 0), true
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Category representa una categoría (organización) de Isard VDI
+type Category struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Frontend      bool   `json:"frontend"`
+	CustomURLName string `json:"custom_url_name,omitempty"`
+}
+
+// GetCategories obtiene la lista de categorías
+func (c *Client) GetCategories(ctx context.Context) ([]Category, error) {
+	var categories []Category
+	if err := c.do(ctx, http.MethodGet, "/api/v3/admin/categories", nil, &categories); err != nil {
+		return nil, fmt.Errorf("error obteniendo categorías: %w", err)
+	}
+
+	return categories, nil
+}
+
+// GetCategory obtiene una categoría específica por ID
+func (c *Client) GetCategory(ctx context.Context, categoryID string) (*Category, error) {
+	var category Category
+	if err := c.do(ctx, http.MethodGet, "/api/v3/admin/category/"+categoryID, nil, &category); err != nil {
+		return nil, fmt.Errorf("error obteniendo categoría: %w", err)
+	}
+
+	return &category, nil
+}
+
+// CreateCategory crea una categoría (requiere rol de administrador) y devuelve su ID.
+// customURLName puede estar vacío para no publicar una URL de login propia.
+func (c *Client) CreateCategory(ctx context.Context, name, description string, frontend bool, customURLName string) (string, error) {
+	payload := map[string]interface{}{
+		"name":            name,
+		"description":     description,
+		"frontend":        frontend,
+		"custom_url_name": customURLName,
+	}
+
+	var response struct {
+		ID string `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/v3/admin/category", payload, &response); err != nil {
+		return "", fmt.Errorf("error creando categoría: %w", err)
+	}
+	if response.ID == "" {
+		return "", fmt.Errorf("error creando categoría: la API no devolvió el ID")
+	}
+
+	return response.ID, nil
+}
+
+// UpdateCategory modifica una categoría existente. updateData solo debe contener los campos
+// que cambian (name, description, frontend, custom_url_name).
+func (c *Client) UpdateCategory(ctx context.Context, categoryID string, updateData map[string]interface{}) error {
+	if err := c.do(ctx, http.MethodPut, "/api/v3/admin/category/"+categoryID, updateData, nil); err != nil {
+		return fmt.Errorf("error actualizando categoría: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteCategory elimina una categoría. La API elimina también sus grupos, usuarios y recursos.
+func (c *Client) DeleteCategory(ctx context.Context, categoryID string) error {
+	err := c.do(ctx, http.MethodDelete, "/api/v3/admin/category/"+categoryID, nil, nil)
+
+	// Considerar éxito el 404 (ya no existe)
+	if err != nil && !IsNotFound(err) {
+		return fmt.Errorf("error eliminando categoría: %w", err)
+	}
+
+	return nil
+}
@@ -1,44 +1,98 @@
 package client
 
 import (
-	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
-	"io"
-	"mime/multipart"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
-
-	"github.com/tknika/terraform-provider-isardvdi/internal/constants"
 )
 
+// DefaultRequestTimeout es el tiempo máximo por defecto de cada petición HTTP
+const DefaultRequestTimeout = 60 * time.Second
+
 // Client holds the connection information
 type Client struct {
 	HTTPClient *http.Client
-	HostURL    string
-	Token      string
+	// BaseURL es la URL base de la instalación (esquema, host, puerto y prefijo opcional).
+	// Los endpoints de la API se resuelven relativos a ella.
+	BaseURL *url.URL
+	Token   string
+
+	// Política de reintentos ante errores transitorios de la API
+	MaxRetries   int
+	RetryWaitMin time.Duration
+	RetryWaitMax time.Duration
+
+	// Estado de la sesión con login por formulario. authMu serializa los
+	// logins para que las operaciones en paralelo no saturen el endpoint.
+	authMu      sync.Mutex
+	credentials *formCredentials
+	tokenExpiry time.Time
 }
 
-// NewClient creates a new client
-func NewClient(host, token string, sslVerification bool) *Client {
+// NewClient creates a new client. host puede ser un hostname ("isard.example.com",
+// se asume HTTPS) o una URL base completa ("http://localhost:8080/isard").
+func NewClient(host, token string, sslVerification bool) (*Client, error) {
+	baseURL, err := ParseBaseURL(host)
+	if err != nil {
+		return nil, err
+	}
+
 	// Configurar transporte HTTP con opción de verificación SSL configurable
 	tr := &http.Transport{
 		TLSClientConfig: &tls.Config{InsecureSkipVerify: !sslVerification},
 	}
-	
+
 	return &Client{
 		HTTPClient: &http.Client{
-			Timeout:   60 * time.Second,
+			Timeout:   DefaultRequestTimeout,
 			Transport: tr,
 		},
-		HostURL: host,
-		Token:   token,
+		BaseURL:      baseURL,
+		Token:        token,
+		MaxRetries:   DefaultMaxRetries,
+		RetryWaitMin: DefaultRetryWaitMin,
+		RetryWaitMax: DefaultRetryWaitMax,
+	}, nil
+}
+
+// ParseBaseURL interpreta el endpoint configurado. Un host sin esquema se trata como HTTPS.
+func ParseBaseURL(endpoint string) (*url.URL, error) {
+	endpoint = strings.TrimSpace(endpoint)
+	if endpoint == "" {
+		return nil, fmt.Errorf("el endpoint no puede estar vacío")
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
 	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("endpoint inválido %q: %w", endpoint, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("endpoint inválido %q: el esquema debe ser http o https", endpoint)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("endpoint inválido %q: falta el host", endpoint)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("endpoint inválido %q: no puede contener query ni fragmento", endpoint)
+	}
+
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = ""
+
+	return u, nil
 }
 
 // SignIn performs the authentication flow
-func (c *Client) SignIn(authMethod, categoryID, username, password string) error {
+func (c *Client) SignIn(ctx context.Context, authMethod, categoryID, username, password string) error {
 	if authMethod == "token" {
 		// Cuando usamos token, simplemente lo usamos directamente sin hacer llamadas adicionales
 		// El token ya está almacenado en c.Token desde NewClient
@@ -46,40 +100,23 @@ func (c *Client) SignIn(authMethod, categoryID, username, password string) error
 	}
 
 	if authMethod == "form" {
-		// Construir URL con query params
-		reqURL := fmt.Sprintf("https://%s%s?provider=form&category_id=%s", c.HostURL, constants.LoginPath, categoryID)
-
-		// Multipart form data
-		body := &bytes.Buffer{}
-		writer := multipart.NewWriter(body)
-		_ = writer.WriteField("username", username)
-		_ = writer.WriteField("password", password)
-		err := writer.Close()
-		if err != nil {
-			return err
+		// Guardamos las credenciales para poder renovar la sesión cuando caduque el token
+		c.authMu.Lock()
+		defer c.authMu.Unlock()
+
+		c.credentials = &formCredentials{
+			categoryID: categoryID,
+			username:   username,
+			password:   password,
 		}
-
-		req, err := http.NewRequest("POST", reqURL, body)
-		if err != nil {
-			return err
-		}
-		req.Header.Set("Content-Type", writer.FormDataContentType())
-		req.Header.Set("Accept", "text/plain")
-
-		println(req.URL.String())
-
-		return c.executeAuthRequest(req)
+		return c.login(ctx)
 	}
 
 	return nil
 }
 
-func (c *Client) executeAuthRequest(req *http.Request) error {
-	body, err := c.doRequest(req)
-	if err != nil {
-		return err
-	}
-
+// parseAuthResponse extrae el token de la respuesta del login
+func (c *Client) parseAuthResponse(body []byte) error {
 	// Intentamos parsear la respuesta para encontrar el token temporal (JSON)
 	var authResp map[string]interface{}
 	if err := json.Unmarshal(body, &authResp); err == nil {
@@ -114,23 +151,3 @@ func (c *Client) executeAuthRequest(req *http.Request) error {
 
 	return fmt.Errorf("no se encontró el token en la respuesta de login. Respuesta cruda: %s", string(body))
 }
-
-// doRequest helper for executing requests
-func (c *Client) doRequest(req *http.Request) ([]byte, error) {
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("status: %d, body: %s", res.StatusCode, body)
-	}
-
-	return body, nil
-}
@@ -1,10 +1,8 @@
 package client
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
 	"net/http"
 	"time"
 )
@@ -39,10 +37,32 @@ type DeploymentInfo struct {
 	VisibleDesktops int                    `json:"visibleDesktops"`
 	StartedDesktops int                    `json:"startedDesktops"`
 	CreatingDesktops int                   `json:"creatingDesktops"`
+	Desktops        []DeploymentDesktop    `json:"desktops"`
+}
+
+// DeploymentDesktop representa un desktop de un deployment tal y como lo devuelve
+// GET /deployment/{id}: incluye el propietario, el estado y la IP del invitado
+type DeploymentDesktop struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	User         string   `json:"user"`
+	Username     string   `json:"userName"`
+	CategoryName string   `json:"categoryName"`
+	GroupName    string   `json:"groupName"`
+	Status       string   `json:"status"`
+	IP           string   `json:"ip"`
+	Viewers      []string `json:"viewers"`
+	Visible      bool     `json:"visible"`
+}
+
+// ViewerAvailable indica si el propietario puede conectarse ya al desktop: debe estar
+// arrancado y tener al menos un viewer habilitado
+func (d DeploymentDesktop) ViewerAvailable() bool {
+	return IsStartedStatus(d.Status) && len(d.Viewers) > 0
 }
 
 // CreateDeployment crea un nuevo deployment
-func (c *Client) CreateDeployment(
+func (c *Client) CreateDeployment(ctx context.Context, 
 	name string,
 	description string,
 	templateID string,
@@ -58,10 +78,8 @@ func (c *Client) CreateDeployment(
 	isos []string,
 	floppies []string,
 ) (string, error) {
-	reqURL := fmt.Sprintf("https://%s/api/v3/deployments", c.HostURL)
-
 	// Obtener información del template para construir payload completo
-	template, err := c.GetTemplateInfo(templateID)
+	template, err := c.GetTemplateInfo(ctx, templateID)
 	if err != nil {
 		return "", fmt.Errorf("error obteniendo información del template: %w", err)
 	}
@@ -101,8 +119,8 @@ func (c *Client) CreateDeployment(
 		hardware["disks"] = disks
 	}
 	
-	// ISOs y Floppies: usar valores especificados o del template
-	if len(isos) > 0 {
+	// ISOs y Floppies: usar valores especificados (una lista vacía quita los del template) o del template
+	if isos != nil {
 		isoList := make([]map[string]interface{}, len(isos))
 		for i, isoID := range isos {
 			isoList[i] = map[string]interface{}{"id": isoID}
@@ -112,7 +130,7 @@ func (c *Client) CreateDeployment(
 		hardware["isos"] = templateISOs
 	}
 	
-	if len(floppies) > 0 {
+	if floppies != nil {
 		floppyList := make([]map[string]interface{}, len(floppies))
 		for i, floppyID := range floppies {
 			floppyList[i] = map[string]interface{}{"id": floppyID}
@@ -140,17 +158,18 @@ func (c *Client) CreateDeployment(
 		hardware["vcpus"] = 2
 	}
 	
+	// La memoria se envía en GB con decimales (1.5 GB); truncarla a entero haría que
+	// el valor leído no coincidiera con el configurado
 	if memory != nil {
-		hardware["memory"] = int(*memory)
+		hardware["memory"] = *memory
 	} else if templateMemory, ok := templateHardware["memory"].(float64); ok {
-		// Convertir de KiB a GB
-		hardware["memory"] = int(templateMemory / 1024 / 1024)
+		hardware["memory"] = kibToGB(templateMemory)
 	} else {
 		hardware["memory"] = 2
 	}
 	
-	// interfaces: usar valores especificados o del template
-	if len(interfaces) > 0 {
+	// interfaces: usar valores especificados (una lista vacía deja los desktops sin red) o del template
+	if interfaces != nil {
 		hardware["interfaces"] = interfaces
 	} else {
 		hardware["interfaces"] = []string{"default", "wireguard"}
@@ -188,336 +207,160 @@ func (c *Client) CreateDeployment(
 		payload["image"] = map[string]interface{}{"type": "user"}
 	}
 
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return "", fmt.Errorf("error codificando JSON: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", reqURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", fmt.Errorf("error creando la petición POST: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Content-Type", "application/json")
-
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("error ejecutando POST: %w", err)
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return "", fmt.Errorf("error leyendo respuesta: %w", err)
-	}
-
-	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
-		return "", fmt.Errorf("error creando deployment (status %d): %s", res.StatusCode, string(body))
-	}
-
-	// Parsear la respuesta para obtener el ID
 	var response map[string]interface{}
-	if err := json.Unmarshal(body, &response); err != nil {
-		return "", fmt.Errorf("error parseando respuesta JSON: %w", err)
+	if err := c.do(ctx, http.MethodPost, "/api/v3/deployments", payload, &response); err != nil {
+		return "", fmt.Errorf("error creando deployment: %w", err)
 	}
 
 	deploymentID, ok := response["id"].(string)
 	if !ok {
-		return "", fmt.Errorf("no se encontró el ID en la respuesta: %s", string(body))
+		return "", fmt.Errorf("no se encontró el ID en la respuesta: %v", response)
 	}
 
 	return deploymentID, nil
 }
 
 // GetDeployment obtiene la información de un deployment
-func (c *Client) GetDeployment(deploymentID string) (*DeploymentInfo, error) {
-	reqURL := fmt.Sprintf("https://%s/api/v3/deployment/%s", c.HostURL, deploymentID)
-
-	req, err := http.NewRequest("GET", reqURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creando la petición GET: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Content-Type", "application/json")
-
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error ejecutando GET: %w", err)
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
-	}
-
-	if res.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("deployment not found")
-	}
-
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("error obteniendo deployment (status %d): %s", res.StatusCode, string(body))
-	}
-
-	// Parsear la respuesta
+func (c *Client) GetDeployment(ctx context.Context, deploymentID string) (*DeploymentInfo, error) {
 	var deployment DeploymentInfo
-	if err := json.Unmarshal(body, &deployment); err != nil {
-		return nil, fmt.Errorf("error parseando respuesta JSON: %w", err)
+	if err := c.do(ctx, http.MethodGet, "/api/v3/deployment/"+deploymentID, nil, &deployment); err != nil {
+		return nil, fmt.Errorf("error obteniendo deployment: %w", err)
 	}
 
 	return &deployment, nil
 }
 
 // GetDeploymentInfo obtiene información detallada de un deployment para edición
-func (c *Client) GetDeploymentInfo(deploymentID string) (map[string]interface{}, error) {
-	reqURL := fmt.Sprintf("https://%s/api/v3/deployment/info/%s", c.HostURL, deploymentID)
-
-	req, err := http.NewRequest("GET", reqURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creando la petición GET: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Content-Type", "application/json")
-
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error ejecutando GET: %w", err)
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
-	}
-
-	if res.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("deployment not found")
-	}
-
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("error obteniendo deployment info (status %d): %s", res.StatusCode, string(body))
-	}
-
-	// Parsear la respuesta
+func (c *Client) GetDeploymentInfo(ctx context.Context, deploymentID string) (map[string]interface{}, error) {
 	var deploymentInfo map[string]interface{}
-	if err := json.Unmarshal(body, &deploymentInfo); err != nil {
-		return nil, fmt.Errorf("error parseando respuesta JSON: %w", err)
+	if err := c.do(ctx, http.MethodGet, "/api/v3/deployment/info/"+deploymentID, nil, &deploymentInfo); err != nil {
+		return nil, fmt.Errorf("error obteniendo deployment info: %w", err)
 	}
 
 	return deploymentInfo, nil
 }
 
 // UpdateDeployment actualiza un deployment existente
-func (c *Client) UpdateDeployment(deploymentID string, updateData map[string]interface{}) error {
-	reqURL := fmt.Sprintf("https://%s/api/v3/deployment/%s", c.HostURL, deploymentID)
-
-	jsonData, err := json.Marshal(updateData)
-	if err != nil {
-		return fmt.Errorf("error codificando JSON: %w", err)
-	}
-
-	req, err := http.NewRequest("PUT", reqURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("error creando la petición PUT: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Content-Type", "application/json")
-
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("error ejecutando PUT: %w", err)
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return fmt.Errorf("error leyendo respuesta: %w", err)
-	}
-
-	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("error actualizando deployment (status %d): %s", res.StatusCode, string(body))
+func (c *Client) UpdateDeployment(ctx context.Context, deploymentID string, updateData map[string]interface{}) error {
+	if err := c.do(ctx, http.MethodPut, "/api/v3/deployment/"+deploymentID, updateData, nil); err != nil {
+		return fmt.Errorf("error actualizando deployment: %w", err)
 	}
 
 	return nil
 }
 
-// DeleteDeployment elimina un deployment
-func (c *Client) DeleteDeployment(deploymentID string, permanent bool) error {
+// DeleteDeployment elimina un deployment. Si la API exige detener antes los desktops
+// (428), los detiene y espera como máximo maxWait a que se detengan.
+func (c *Client) DeleteDeployment(ctx context.Context, deploymentID string, permanent bool, maxWait time.Duration) error {
 	permanentStr := "false"
 	if permanent {
 		permanentStr = "true"
 	}
-	reqURL := fmt.Sprintf("https://%s/api/v3/deployments/%s/%s", c.HostURL, deploymentID, permanentStr)
-
-	req, err := http.NewRequest("DELETE", reqURL, nil)
-	if err != nil {
-		return fmt.Errorf("error creando la petición DELETE: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Content-Type", "application/json")
-
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("error ejecutando DELETE: %w", err)
-	}
-	defer res.Body.Close()
+	path := fmt.Sprintf("/api/v3/deployments/%s/%s", deploymentID, permanentStr)
 
-	// Leer el body para obtener información de error si es necesario
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return fmt.Errorf("error leyendo respuesta: %w", err)
-	}
+	err := c.do(ctx, http.MethodDelete, path, nil, nil)
 
-	// Considerar éxito los códigos 200, 204 (No Content) y 404 (ya no existe)
-	if res.StatusCode == http.StatusOK || res.StatusCode == http.StatusNoContent || res.StatusCode == http.StatusNotFound {
+	// Considerar éxito el 404 (ya no existe)
+	if err == nil || IsNotFound(err) {
 		return nil
 	}
 
 	// Si es error 428 (Precondition Required), las VMs deben detenerse primero
-	if res.StatusCode == 428 {
+	if IsPreconditionRequired(err) {
 		// Intentar detener las VMs del deployment
-		stopErr := c.StopDeployment(deploymentID)
+		stopErr := c.StopDeployment(ctx, deploymentID)
 		if stopErr != nil {
 			// Si falla al detener, retornar el error original
-			return fmt.Errorf("error eliminando deployment (status %d): %s (intento detener VMs falló: %v)", res.StatusCode, string(body), stopErr)
-		}
-		
-		// Esperar a que las VMs se detengan (máximo 60 segundos)
-		// Las VMs recién creadas pueden tardar más en detenerse
-		waitErr := c.WaitForDeploymentStopped(deploymentID, 60)
-		if waitErr != nil {
-			// Si timeout esperando, aún intentar eliminar una vez más
-			// Dar 10 segundos adicionales por si todavía se están deteniendo
-			time.Sleep(10 * time.Second)
-		}
-		
-		// Reintentar la eliminación
-		req2, err := http.NewRequest("DELETE", reqURL, nil)
-		if err != nil {
-			return fmt.Errorf("error creando la petición DELETE (reintento): %w", err)
-		}
-
-		req2.Header.Set("Authorization", "Bearer "+c.Token)
-		req2.Header.Set("Content-Type", "application/json")
-
-		res2, err := c.HTTPClient.Do(req2)
-		if err != nil {
-			return fmt.Errorf("error ejecutando DELETE (reintento): %w", err)
+			return fmt.Errorf("error eliminando deployment: %w (intento detener VMs falló: %v)", err, stopErr)
 		}
-		defer res2.Body.Close()
 
-		body2, err := io.ReadAll(res2.Body)
-		if err != nil {
-			return fmt.Errorf("error leyendo respuesta (reintento): %w", err)
+		// Esperar a que las VMs se detengan. Si se agota maxWait, aún se intenta
+		// eliminar una vez más por si ya se han detenido.
+		if waitErr := c.WaitForDeploymentStopped(ctx, deploymentID, maxWait); waitErr != nil && ctx.Err() != nil {
+			return fmt.Errorf("error eliminando deployment: %w", waitErr)
 		}
 
-		// Verificar si el reintento fue exitoso
-		if res2.StatusCode == http.StatusOK || res2.StatusCode == http.StatusNoContent || res2.StatusCode == http.StatusNotFound {
+		// Reintentar la eliminación
+		err = c.do(ctx, http.MethodDelete, path, nil, nil)
+		if err == nil || IsNotFound(err) {
 			return nil
 		}
 
-		return fmt.Errorf("error eliminando deployment (reintento, status %d): %s", res2.StatusCode, string(body2))
+		return fmt.Errorf("error eliminando deployment (reintento): %w", err)
 	}
 
-	return fmt.Errorf("error eliminando deployment (status %d): %s", res.StatusCode, string(body))
+	return fmt.Errorf("error eliminando deployment: %w", err)
 }
 
 // StartDeployment inicia todos los desktops de un deployment
-func (c *Client) StartDeployment(deploymentID string) error {
-	reqURL := fmt.Sprintf("https://%s/api/v3/deployments/start/%s", c.HostURL, deploymentID)
-
-	req, err := http.NewRequest("PUT", reqURL, nil)
-	if err != nil {
-		return fmt.Errorf("error creando la petición PUT: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Content-Type", "application/json")
-
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("error ejecutando PUT: %w", err)
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return fmt.Errorf("error leyendo respuesta: %w", err)
-	}
-
-	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("error iniciando deployment (status %d): %s", res.StatusCode, string(body))
+func (c *Client) StartDeployment(ctx context.Context, deploymentID string) error {
+	if err := c.do(ctx, http.MethodPut, "/api/v3/deployments/start/"+deploymentID, nil, nil); err != nil {
+		return fmt.Errorf("error iniciando deployment: %w", err)
 	}
 
 	return nil
 }
 
 // StopDeployment detiene todos los desktops de un deployment
-func (c *Client) StopDeployment(deploymentID string) error {
-	reqURL := fmt.Sprintf("https://%s/api/v3/deployments/stop/%s", c.HostURL, deploymentID)
-
-	req, err := http.NewRequest("PUT", reqURL, nil)
-	if err != nil {
-		return fmt.Errorf("error creando la petición PUT: %w", err)
+func (c *Client) StopDeployment(ctx context.Context, deploymentID string) error {
+	if err := c.do(ctx, http.MethodPut, "/api/v3/deployments/stop/"+deploymentID, nil, nil); err != nil {
+		return fmt.Errorf("error deteniendo deployment: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Content-Type", "application/json")
-
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("error ejecutando PUT: %w", err)
-	}
-	defer res.Body.Close()
+	return nil
+}
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return fmt.Errorf("error leyendo respuesta: %w", err)
-	}
+// WaitForDeploymentStopped espera a que todas las VMs del deployment se detengan
+func (c *Client) WaitForDeploymentStopped(ctx context.Context, deploymentID string, maxWait time.Duration) error {
+	return c.waitForDeployment(ctx, deploymentID, maxWait, "que se detengan las VMs del deployment", func(info *DeploymentInfo) bool {
+		return info.StartedDesktops == 0 && info.CreatingDesktops == 0
+	})
+}
 
-	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("error deteniendo deployment (status %d): %s", res.StatusCode, string(body))
-	}
+// WaitForDeploymentCreated espera a que el deployment termine de crear todos sus desktops
+func (c *Client) WaitForDeploymentCreated(ctx context.Context, deploymentID string, maxWait time.Duration) error {
+	return c.waitForDeployment(ctx, deploymentID, maxWait, "que se creen los desktops del deployment", func(info *DeploymentInfo) bool {
+		return info.CreatingDesktops == 0
+	})
+}
 
-	return nil
+// WaitForDeploymentStarted espera a que todos los desktops del deployment estén creados y arrancados
+func (c *Client) WaitForDeploymentStarted(ctx context.Context, deploymentID string, maxWait time.Duration) error {
+	return c.waitForDeployment(ctx, deploymentID, maxWait, "que arranquen las VMs del deployment", func(info *DeploymentInfo) bool {
+		return info.CreatingDesktops == 0 && info.StartedDesktops >= info.TotalDesktops
+	})
 }
 
-// WaitForDeploymentStopped espera a que todas las VMs del deployment se detengan
-func (c *Client) WaitForDeploymentStopped(deploymentID string, maxWaitSeconds int) error {
-	// Verificar inmediatamente si ya está detenido (antes de esperar)
-	deploymentInfo, err := c.GetDeployment(deploymentID)
+// waitForDeployment consulta los contadores del deployment hasta que done devuelve true,
+// se agota maxWait o se cancela el contexto
+func (c *Client) waitForDeployment(ctx context.Context, deploymentID string, maxWait time.Duration, what string, done func(info *DeploymentInfo) bool) error {
+	// Verificar inmediatamente los contadores (antes de esperar)
+	deploymentInfo, err := c.GetDeployment(ctx, deploymentID)
 	if err != nil {
 		return fmt.Errorf("error obteniendo información del deployment: %w", err)
 	}
-	
-	// Si todas las VMs ya están detenidas, retornar inmediatamente
-	if deploymentInfo.StartedDesktops == 0 && deploymentInfo.CreatingDesktops == 0 {
+	if done(deploymentInfo) {
 		return nil
 	}
-	
-	// Si no están detenidas, esperar con polling
+
+	// Si no se cumple la condición, esperar con polling
 	ticker := time.NewTicker(2 * time.Second)
 	defer ticker.Stop()
-	
-	timeout := time.After(time.Duration(maxWaitSeconds) * time.Second)
-	
+
+	timeout := time.After(maxWait)
+
 	for {
 		select {
+		case <-ctx.Done():
+			return fmt.Errorf("espera cancelada: %w", ctx.Err())
 		case <-timeout:
-			return fmt.Errorf("timeout esperando a que se detengan las VMs del deployment después de %d segundos", maxWaitSeconds)
+			return fmt.Errorf("timeout esperando a %s después de %s", what, maxWait)
 		case <-ticker.C:
-			deploymentInfo, err := c.GetDeployment(deploymentID)
+			deploymentInfo, err := c.GetDeployment(ctx, deploymentID)
 			if err != nil {
 				return fmt.Errorf("error obteniendo información del deployment: %w", err)
 			}
-			
-			// Verificar si todas las VMs están detenidas
-			if deploymentInfo.StartedDesktops == 0 && deploymentInfo.CreatingDesktops == 0 {
+			if done(deploymentInfo) {
 				return nil
 			}
 		}
@@ -525,35 +368,11 @@ func (c *Client) WaitForDeploymentStopped(deploymentID string, maxWaitSeconds in
 }
 
 // GetTemplateInfo obtiene información del template necesaria para crear deployments
-func (c *Client) GetTemplateInfo(templateID string) (map[string]interface{}, error) {
+func (c *Client) GetTemplateInfo(ctx context.Context, templateID string) (map[string]interface{}, error) {
 	// Usar el endpoint de templates normal
-	reqURL := fmt.Sprintf("https://%s/api/v3/template/%s", c.HostURL, templateID)
-
-	req, err := http.NewRequest("GET", reqURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creando petición GET: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error ejecutando GET: %w", err)
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
-	}
-
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("error obteniendo template (status %d): %s", res.StatusCode, string(body))
-	}
-
 	var template map[string]interface{}
-	if err := json.Unmarshal(body, &template); err != nil {
-		return nil, fmt.Errorf("error parseando respuesta: %w", err)
+	if err := c.do(ctx, http.MethodGet, "/api/v3/template/"+templateID, nil, &template); err != nil {
+		return nil, fmt.Errorf("error obteniendo template: %w", err)
 	}
 
 	return template, nil
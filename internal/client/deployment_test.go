@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/tknika/terraform-provider-isardvdi/internal/testserver"
+)
+
+func TestDeleteDeploymentStopsStartedDesktops(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+
+	id, err := c.CreateDeployment(ctx, "arrancado", "", testserver.DefaultTemplateID, "desktop", false, testAllowed, nil, nil, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateDeployment: %v", err)
+	}
+	if err := c.StartDeployment(ctx, id); err != nil {
+		t.Fatalf("StartDeployment: %v", err)
+	}
+	if err := c.WaitForDeploymentStarted(ctx, id, 10*time.Second); err != nil {
+		t.Fatalf("WaitForDeploymentStarted: %v", err)
+	}
+
+	// La API responde 428 mientras haya desktops arrancados: el cliente los detiene y reintenta
+	if err := c.DeleteDeployment(ctx, id, true, 10*time.Second); err != nil {
+		t.Fatalf("DeleteDeployment: %v", err)
+	}
+	if _, ok := srv.Deployment(id); ok {
+		t.Error("el deployment sigue existiendo")
+	}
+	if _, ok := srv.LastRequest(http.MethodPut, "/api/v3/deployments/stop/"+id); !ok {
+		t.Error("no se han detenido los desktops antes de eliminar")
+	}
+	if got := countRequests(srv, http.MethodDelete, "/api/v3/deployments/"+id+"/true"); got != 2 {
+		t.Errorf("eliminaciones = %d, se esperaban 2 (428 y reintento)", got)
+	}
+}
+
+func TestDeleteDeploymentNotFound(t *testing.T) {
+	c, _ := newTestClient(t)
+
+	if err := c.DeleteDeployment(context.Background(), "no-existe", true, time.Second); err != nil {
+		t.Errorf("DeleteDeployment de un deployment inexistente: %v", err)
+	}
+}
+
+func TestCreateDeploymentEmptyMedia(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+
+	srv.AddTemplate(map[string]interface{}{
+		"id":      "_template_con_medios",
+		"name":    "Con medios",
+		"enabled": true,
+		"hardware": map[string]interface{}{
+			"vcpus":      2,
+			"memory":     2 * 1024 * 1024,
+			"videos":     []interface{}{"default"},
+			"interfaces": []interface{}{"default"},
+			"isos":       []interface{}{map[string]interface{}{"id": "iso-ubuntu"}},
+			"floppies":   []interface{}{map[string]interface{}{"id": "floppy-1"}},
+		},
+	})
+
+	// Una lista vacía quita los medios del template; nil los conserva
+	if _, err := c.CreateDeployment(ctx, "sin-medios", "", "_template_con_medios", "desktop", false, testAllowed, nil, nil, nil, nil, nil, nil, []string{}, []string{}); err != nil {
+		t.Fatalf("CreateDeployment: %v", err)
+	}
+	req, _ := srv.LastRequest(http.MethodPost, "/api/v3/deployments")
+	hardware, _ := req.JSON()["hardware"].(map[string]interface{})
+	if isos, ok := hardware["isos"].([]interface{}); !ok || len(isos) != 0 {
+		t.Errorf("hardware.isos enviado = %v, se esperaba una lista vacía", hardware["isos"])
+	}
+	if floppies, ok := hardware["floppies"].([]interface{}); !ok || len(floppies) != 0 {
+		t.Errorf("hardware.floppies enviado = %v, se esperaba una lista vacía", hardware["floppies"])
+	}
+
+	if _, err := c.CreateDeployment(ctx, "con-medios", "", "_template_con_medios", "desktop", false, testAllowed, nil, nil, nil, nil, nil, nil, nil, nil); err != nil {
+		t.Fatalf("CreateDeployment: %v", err)
+	}
+	req, _ = srv.LastRequest(http.MethodPost, "/api/v3/deployments")
+	hardware, _ = req.JSON()["hardware"].(map[string]interface{})
+	if isos, _ := hardware["isos"].([]interface{}); len(isos) != 1 {
+		t.Errorf("hardware.isos enviado = %v, se esperaban las del template", hardware["isos"])
+	}
+}
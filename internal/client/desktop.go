@@ -1,22 +1,33 @@
 package client
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
 	"net/http"
+	"slices"
 	"time"
 )
 
 // Desktop representa la estructura de un desktop en la API
 type Desktop struct {
-	ID          string  `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	TemplateID  string  `json:"template_id"`
-	VCPUs       int64   `json:"vcpus,omitempty"`
-	Memory      float64 `json:"memory,omitempty"`
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	TemplateID  string   `json:"template_id"`
+	Status      string   `json:"status,omitempty"`
+	VCPUs       int64    `json:"vcpus,omitempty"`
+	Memory      float64  `json:"memory,omitempty"` // GB
+	Interfaces  []string `json:"interfaces,omitempty"`
+	ISOs        []string `json:"isos,omitempty"`
+	Floppies    []string `json:"floppies,omitempty"`
+	BootOrder   []string `json:"boot_order,omitempty"`
+	Viewers     []string `json:"viewers,omitempty"`
+	User        string   `json:"user,omitempty"`
+	Username    string   `json:"username,omitempty"`
+	Category    string   `json:"category,omitempty"`
+	Group       string   `json:"group,omitempty"`
+	Tag         string   `json:"tag,omitempty"` // deployment al que pertenece el desktop
+	IPs         []string `json:"ips,omitempty"`
 }
 
 // HardwareSpec especifica el hardware personalizado para un desktop
@@ -32,10 +43,9 @@ type HardwareSpec struct {
 	Floppies   []map[string]interface{} `json:"floppies,omitempty"`
 }
 
-// CreatePersistentDesktop crea un nuevo persistent desktop
-func (c *Client) CreatePersistentDesktop(name, description, templateID string, vcpus *int64, memory *float64, interfaces []string, isos []string, floppies []string) (string, error) {
-	reqURL := fmt.Sprintf("https://%s/api/v3/persistent_desktop", c.HostURL)
-
+// CreatePersistentDesktop crea un nuevo persistent desktop. Las listas nil usan las del
+// template; una lista vacía deja el desktop sin interfaces, ISOs o floppies.
+func (c *Client) CreatePersistentDesktop(ctx context.Context, name, description, templateID string, vcpus *int64, memory *float64, interfaces []string, isos []string, floppies []string) (string, error) {
 	// Construir el payload
 	payload := map[string]interface{}{
 		"name":        name,
@@ -47,7 +57,7 @@ func (c *Client) CreatePersistentDesktop(name, description, templateID string, v
 	}
 
 	// Agregar hardware personalizado si se especifica
-	if vcpus != nil || memory != nil || len(interfaces) > 0 || len(isos) > 0 || len(floppies) > 0 {
+	if vcpus != nil || memory != nil || interfaces != nil || isos != nil || floppies != nil {
 		hardware := make(map[string]interface{})
 		if vcpus != nil {
 			hardware["vcpus"] = *vcpus
@@ -55,17 +65,17 @@ func (c *Client) CreatePersistentDesktop(name, description, templateID string, v
 		if memory != nil {
 			hardware["memory"] = *memory
 		}
-		if len(interfaces) > 0 {
+		if interfaces != nil {
 			hardware["interfaces"] = interfaces
 		}
-		if len(isos) > 0 {
+		if isos != nil {
 			isoList := make([]map[string]interface{}, len(isos))
 			for i, isoID := range isos {
 				isoList[i] = map[string]interface{}{"id": isoID}
 			}
 			hardware["isos"] = isoList
 		}
-		if len(floppies) > 0 {
+		if floppies != nil {
 			floppyList := make([]map[string]interface{}, len(floppies))
 			for i, floppyID := range floppies {
 				floppyList[i] = map[string]interface{}{"id": floppyID}
@@ -75,85 +85,47 @@ func (c *Client) CreatePersistentDesktop(name, description, templateID string, v
 		payload["hardware"] = hardware
 	}
 
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return "", fmt.Errorf("error codificando JSON: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", reqURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", fmt.Errorf("error creando la petición POST: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Content-Type", "application/json")
-
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("error ejecutando POST: %w", err)
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return "", fmt.Errorf("error leyendo respuesta: %w", err)
-	}
-
-	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
-		return "", fmt.Errorf("error creando desktop (status %d): %s", res.StatusCode, string(body))
-	}
-
-	// Parsear la respuesta para obtener el ID
 	var response map[string]interface{}
-	if err := json.Unmarshal(body, &response); err != nil {
-		return "", fmt.Errorf("error parseando respuesta JSON: %w", err)
+	if err := c.do(ctx, http.MethodPost, "/api/v3/persistent_desktop", payload, &response); err != nil {
+		return "", fmt.Errorf("error creando desktop: %w", err)
 	}
 
 	desktopID, ok := response["id"].(string)
 	if !ok {
-		return "", fmt.Errorf("no se encontró el ID en la respuesta: %s", string(body))
+		return "", fmt.Errorf("no se encontró el ID en la respuesta: %v", response)
 	}
 
 	return desktopID, nil
 }
 
 // GetDesktop obtiene la información de un desktop
-func (c *Client) GetDesktop(desktopID string) (*Desktop, error) {
-	reqURL := fmt.Sprintf("https://%s/api/v3/domain/info/%s", c.HostURL, desktopID)
-
-	req, err := http.NewRequest("GET", reqURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creando la petición GET: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Content-Type", "application/json")
-
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error ejecutando GET: %w", err)
+func (c *Client) GetDesktop(ctx context.Context, desktopID string) (*Desktop, error) {
+	var response map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, "/api/v3/domain/info/"+desktopID, nil, &response); err != nil {
+		return nil, fmt.Errorf("error obteniendo desktop: %w", err)
 	}
-	defer res.Body.Close()
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
-	}
+	return parseDesktop(desktopID, response), nil
+}
 
-	if res.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("desktop not found")
+// ListDesktops obtiene todos los desktops visibles para el usuario (requiere admin o manager)
+func (c *Client) ListDesktops(ctx context.Context) ([]Desktop, error) {
+	var response []map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, "/api/v3/admin/domains?kind=desktop", nil, &response); err != nil {
+		return nil, fmt.Errorf("error listando desktops: %w", err)
 	}
 
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("error obteniendo desktop (status %d): %s", res.StatusCode, string(body))
+	desktops := make([]Desktop, 0, len(response))
+	for _, raw := range response {
+		id, _ := raw["id"].(string)
+		desktops = append(desktops, *parseDesktop(id, raw))
 	}
 
-	// Parsear la respuesta
-	var response map[string]interface{}
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, fmt.Errorf("error parseando respuesta JSON: %w", err)
-	}
+	return desktops, nil
+}
 
+// parseDesktop construye un Desktop a partir de un dominio devuelto por la API
+func parseDesktop(desktopID string, response map[string]interface{}) *Desktop {
 	desktop := &Desktop{
 		ID: desktopID,
 	}
@@ -170,115 +142,122 @@ func (c *Client) GetDesktop(desktopID string) (*Desktop, error) {
 		}
 	}
 	
-	// Leer el hardware
-	if hardware, ok := response["hardware"].(map[string]interface{}); ok {
-		if vcpus, ok := hardware["vcpus"].(float64); ok {
-			desktop.VCPUs = int64(vcpus)
-		}
-		if memory, ok := hardware["memory"].(float64); ok {
-			desktop.Memory = memory
-		}
-	}
-
-	return desktop, nil
+	if status, ok := response["status"].(string); ok {
+		desktop.Status = status
+	}
+
+	// Propietario y deployment (tag) del desktop
+	desktop.User, _ = response["user"].(string)
+	desktop.Username, _ = response["username"].(string)
+	desktop.Category, _ = response["category"].(string)
+	desktop.Group, _ = response["group"].(string)
+	desktop.Tag, _ = response["tag"].(string)
+
+	// Leer el hardware real del desktop (la memoria llega en KiB y se normaliza a GB)
+	if raw, ok := response["hardware"].(map[string]interface{}); ok {
+		hw := parseHardware(raw)
+		desktop.VCPUs = hw.VCPUs
+		desktop.Memory = hw.Memory
+		desktop.Interfaces = hw.Interfaces
+		desktop.ISOs = hw.ISOs
+		desktop.Floppies = hw.Floppies
+		desktop.BootOrder = hw.BootOrder
+	}
+	desktop.Viewers = parseViewers(response["guest_properties"])
+	desktop.IPs = parseIPs(response)
+
+	return desktop
 }
 
-// DeleteDesktop deletes a desktop by its ID
-func (c *Client) DeleteDesktop(desktopID string) error {
-	reqURL := fmt.Sprintf("https://%s/api/v3/desktop/%s/true", c.HostURL, desktopID)
-
-	req, err := http.NewRequest("DELETE", reqURL, nil)
-	if err != nil {
-		return fmt.Errorf("error creando la petición DELETE: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Content-Type", "application/json")
-
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("error ejecutando DELETE: %w", err)
+// parseIPs extrae las IPs conocidas del desktop: la IP del invitado que informa el viewer
+// (viewer.guest_ip) y las IPs asignadas a las interfaces del hardware, sin duplicados
+func parseIPs(response map[string]interface{}) []string {
+	ips := []string{}
+	add := func(ip string) {
+		if ip != "" && !slices.Contains(ips, ip) {
+			ips = append(ips, ip)
+		}
 	}
-	defer res.Body.Close()
 
-	// Leer el body para obtener información de error si es necesario
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return fmt.Errorf("error leyendo respuesta: %w", err)
+	if viewer, ok := response["viewer"].(map[string]interface{}); ok {
+		if ip, ok := viewer["guest_ip"].(string); ok {
+			add(ip)
+		}
 	}
-
-	// Considerar éxito los códigos 200, 204 (No Content) y 404 (ya no existe)
-	if res.StatusCode == http.StatusOK || res.StatusCode == http.StatusNoContent || res.StatusCode == http.StatusNotFound {
-		return nil
+	if hardware, ok := response["hardware"].(map[string]interface{}); ok {
+		interfaces, _ := hardware["interfaces"].([]interface{})
+		for _, item := range interfaces {
+			if iface, ok := item.(map[string]interface{}); ok {
+				if ip, ok := iface["ip"].(string); ok {
+					add(ip)
+				}
+			}
+		}
 	}
 
-	return fmt.Errorf("error eliminando desktop (status %d): %s", res.StatusCode, string(body))
+	return ips
 }
 
-// StopDesktop detiene un desktop
-func (c *Client) StopDesktop(desktopID string) error {
-	reqURL := fmt.Sprintf("https://%s/api/v3/desktop/stop/%s", c.HostURL, desktopID)
-
-	req, err := http.NewRequest("GET", reqURL, nil)
-	if err != nil {
-		return fmt.Errorf("error creando la petición GET: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Content-Type", "application/json")
+// DeleteDesktop deletes a desktop by its ID
+func (c *Client) DeleteDesktop(ctx context.Context, desktopID string) error {
+	err := c.do(ctx, http.MethodDelete, fmt.Sprintf("/api/v3/desktop/%s/true", desktopID), nil, nil)
 
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("error ejecutando GET: %w", err)
+	// Considerar éxito el 404 (ya no existe)
+	if err != nil && !IsNotFound(err) {
+		return fmt.Errorf("error eliminando desktop: %w", err)
 	}
-	defer res.Body.Close()
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return fmt.Errorf("error leyendo respuesta: %w", err)
-	}
+	return nil
+}
 
-	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("error deteniendo desktop (status %d): %s", res.StatusCode, string(body))
+// StopDesktop detiene un desktop
+func (c *Client) StopDesktop(ctx context.Context, desktopID string) error {
+	if err := c.do(ctx, http.MethodGet, "/api/v3/desktop/stop/"+desktopID, nil, nil); err != nil {
+		return fmt.Errorf("error deteniendo desktop: %w", err)
 	}
 
 	return nil
 }
 
-// GetDesktopStatus obtiene el estado actual de un desktop
-func (c *Client) GetDesktopStatus(desktopID string) (string, error) {
-	reqURL := fmt.Sprintf("https://%s/api/v3/domain/info/%s", c.HostURL, desktopID)
-
-	req, err := http.NewRequest("GET", reqURL, nil)
-	if err != nil {
-		return "", fmt.Errorf("error creando la petición GET: %w", err)
+// UpdateDesktop modifica un desktop existente. updateData solo debe contener los campos
+// que cambian (name, description, guest_properties, hardware); la memoria va en GB.
+func (c *Client) UpdateDesktop(ctx context.Context, desktopID string, updateData map[string]interface{}) error {
+	if err := c.do(ctx, http.MethodPut, "/api/v3/domain/"+desktopID, updateData, nil); err != nil {
+		return fmt.Errorf("error actualizando desktop: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Content-Type", "application/json")
+	return nil
+}
 
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("error ejecutando GET: %w", err)
+// StartDesktop arranca un desktop
+func (c *Client) StartDesktop(ctx context.Context, desktopID string) error {
+	if err := c.do(ctx, http.MethodGet, "/api/v3/desktop/start/"+desktopID, nil, nil); err != nil {
+		return fmt.Errorf("error arrancando desktop: %w", err)
 	}
-	defer res.Body.Close()
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return "", fmt.Errorf("error leyendo respuesta: %w", err)
-	}
+	return nil
+}
 
-	if res.StatusCode == http.StatusNotFound {
-		return "not_found", nil
+// IsStoppedStatus indica si el estado de un desktop corresponde a una máquina detenida
+func IsStoppedStatus(status string) bool {
+	switch status {
+	case "Stopped", "stopped", "Shutdown", "shutdown", "Failed", "failed":
+		return true
 	}
+	return false
+}
 
-	if res.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("error obteniendo estado del desktop (status %d): %s", res.StatusCode, string(body))
-	}
+// IsStartedStatus indica si el estado de un desktop corresponde a una máquina en marcha
+func IsStartedStatus(status string) bool {
+	return status == "Started" || status == "started"
+}
 
+// GetDesktopStatus obtiene el estado actual de un desktop. Si el desktop no existe
+// devuelve un error para el que IsNotFound es true.
+func (c *Client) GetDesktopStatus(ctx context.Context, desktopID string) (string, error) {
 	var response map[string]interface{}
-	if err := json.Unmarshal(body, &response); err != nil {
-		return "", fmt.Errorf("error parseando respuesta JSON: %w", err)
+	if err := c.do(ctx, http.MethodGet, "/api/v3/domain/info/"+desktopID, nil, &response); err != nil {
+		return "", fmt.Errorf("error obteniendo estado del desktop: %w", err)
 	}
 
 	if status, ok := response["status"].(string); ok {
@@ -289,77 +268,72 @@ func (c *Client) GetDesktopStatus(desktopID string) (string, error) {
 }
 
 // WaitForDesktopStopped espera a que un desktop se detenga completamente
-func (c *Client) WaitForDesktopStopped(desktopID string, maxWaitSeconds int) error {
-	// Verificar inmediatamente si ya está detenido (antes de esperar)
-	status, err := c.GetDesktopStatus(desktopID)
+func (c *Client) WaitForDesktopStopped(ctx context.Context, desktopID string, maxWait time.Duration) error {
+	return c.waitForDesktopStatus(ctx, desktopID, maxWait, "que se detenga el desktop", func(status string) (bool, error) {
+		return IsStoppedStatus(status), nil
+	})
+}
+
+// WaitForDesktopStarted espera a que un desktop esté arrancado. Falla si el desktop
+// pasa a estado Failed o deja de existir durante la espera.
+func (c *Client) WaitForDesktopStarted(ctx context.Context, desktopID string, maxWait time.Duration) error {
+	return c.waitForDesktopStatus(ctx, desktopID, maxWait, "que arranque el desktop", func(status string) (bool, error) {
+		switch {
+		case IsStartedStatus(status):
+			return true, nil
+		case status == "Failed" || status == "failed":
+			return false, fmt.Errorf("el desktop ha pasado a estado %s al arrancar", status)
+		}
+		return false, nil
+	})
+}
+
+// waitForDesktopStatus consulta el estado del desktop hasta que done devuelve true,
+// devuelve un error, se agota maxWait o se cancela el contexto. Si el desktop deja de
+// existir devuelve el error 404 de la API (IsNotFound).
+func (c *Client) waitForDesktopStatus(ctx context.Context, desktopID string, maxWait time.Duration, what string, done func(status string) (bool, error)) error {
+	// Verificar inmediatamente el estado (antes de esperar)
+	status, err := c.GetDesktopStatus(ctx, desktopID)
 	if err != nil {
 		return fmt.Errorf("error obteniendo estado del desktop: %w", err)
 	}
-	
-	// Estados que indican que el desktop está detenido
-	if status == "Stopped" || status == "stopped" || status == "Shutdown" || status == "shutdown" || status == "Failed" || status == "failed" {
-		return nil
+	if ok, err := done(status); ok || err != nil {
+		return err
 	}
-	
-	// Si no está detenido, esperar con polling
+
+	// Si no ha alcanzado el estado, esperar con polling
 	ticker := time.NewTicker(2 * time.Second)
 	defer ticker.Stop()
-	
-	timeout := time.After(time.Duration(maxWaitSeconds) * time.Second)
-	
+
+	timeout := time.After(maxWait)
+
 	for {
 		select {
+		case <-ctx.Done():
+			return fmt.Errorf("espera cancelada: %w", ctx.Err())
 		case <-timeout:
-			return fmt.Errorf("timeout esperando a que se detenga el desktop después de %d segundos", maxWaitSeconds)
+			return fmt.Errorf("timeout esperando a %s después de %s", what, maxWait)
 		case <-ticker.C:
-			status, err := c.GetDesktopStatus(desktopID)
+			status, err := c.GetDesktopStatus(ctx, desktopID)
 			if err != nil {
 				return fmt.Errorf("error obteniendo estado del desktop: %w", err)
 			}
-			
-			// Estados que indican que el desktop está detenido
-			if status == "Stopped" || status == "stopped" || status == "Shutdown" || status == "shutdown" || status == "Failed" || status == "failed" {
-				return nil
+			if ok, err := done(status); ok || err != nil {
+				return err
 			}
 		}
 	}
 }
 
 // ForceStopDesktop fuerza la parada de un desktop usando el endpoint admin
-func (c *Client) ForceStopDesktop(desktopID string) error {
-	reqURL := fmt.Sprintf("https://%s/api/v3/admin/multiple_actions", c.HostURL)
-
+func (c *Client) ForceStopDesktop(ctx context.Context, desktopID string) error {
 	payload := map[string]interface{}{
 		"ids":    []string{desktopID},
 		"action": "stopping",
 	}
 
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("error codificando JSON: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", reqURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("error creando la petición POST: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Content-Type", "application/json")
-
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("error ejecutando POST: %w", err)
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return fmt.Errorf("error leyendo respuesta: %w", err)
-	}
-
-	if res.StatusCode < 200 || res.StatusCode >= 300 {
-		return fmt.Errorf("error forzando parada del desktop (status %d): %s", res.StatusCode, string(body))
+	if err := c.do(ctx, http.MethodPost, "/api/v3/admin/multiple_actions", payload, nil); err != nil {
+		return fmt.Errorf("error forzando parada del desktop: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tknika/terraform-provider-isardvdi/internal/testserver"
+)
+
+func TestGetDesktopStatusNotFound(t *testing.T) {
+	c, _ := newTestClient(t)
+
+	status, err := c.GetDesktopStatus(context.Background(), "no-existe")
+	if !IsNotFound(err) {
+		t.Fatalf("GetDesktopStatus = %q, %v; se esperaba un 404", status, err)
+	}
+}
+
+func TestWaitForDesktopDeleted(t *testing.T) {
+	c, _ := newTestClient(t)
+	ctx := context.Background()
+
+	id, err := c.CreatePersistentDesktop(ctx, "borrado", "", testserver.DefaultTemplateID, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreatePersistentDesktop: %v", err)
+	}
+	if err := c.DeleteDesktop(ctx, id); err != nil {
+		t.Fatalf("DeleteDesktop: %v", err)
+	}
+
+	// Un desktop eliminado no se sigue consultando hasta agotar el tiempo de espera
+	start := time.Now()
+	if err := c.WaitForDesktopStarted(ctx, id, time.Minute); !IsNotFound(err) {
+		t.Errorf("WaitForDesktopStarted = %v, se esperaba un 404", err)
+	}
+	if err := c.WaitForDesktopStopped(ctx, id, time.Minute); !IsNotFound(err) {
+		t.Errorf("WaitForDesktopStopped = %v, se esperaba un 404", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("las esperas han durado %s", elapsed)
+	}
+}
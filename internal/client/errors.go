@@ -0,0 +1,137 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError representa una respuesta de error de la API de Isard VDI
+type APIError struct {
+	StatusCode      int
+	Method          string
+	Endpoint        string
+	RequestID       string
+	ErrorCode       string
+	Message         string
+	DescriptionCode string
+	Body            string
+}
+
+// Error implementa la interfaz error
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "status %d", e.StatusCode)
+	if e.Method != "" || e.Endpoint != "" {
+		fmt.Fprintf(&b, " (%s %s)", e.Method, e.Endpoint)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " [request_id: %s]", e.RequestID)
+	}
+
+	msg := e.Message
+	if msg == "" {
+		msg = e.Body
+	}
+	if msg != "" {
+		b.WriteString(": ")
+		b.WriteString(msg)
+	}
+	if e.DescriptionCode != "" && e.Message != "" {
+		fmt.Fprintf(&b, " (%s)", e.DescriptionCode)
+	}
+
+	return b.String()
+}
+
+// newAPIError construye un APIError a partir de la petición y la respuesta HTTP
+func newAPIError(req *http.Request, res *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: res.StatusCode,
+		Body:       strings.TrimSpace(string(body)),
+	}
+
+	if req != nil {
+		apiErr.Method = req.Method
+		apiErr.Endpoint = req.URL.Path
+	}
+
+	for _, header := range []string{"X-Request-Id", "X-Request-ID", "Request-Id"} {
+		if id := res.Header.Get(header); id != "" {
+			apiErr.RequestID = id
+			break
+		}
+	}
+
+	// La API devuelve errores con la forma
+	// {"error": "not_found", "msg": "...", "description_code": "...", "request_id": "..."}
+	var errBody map[string]interface{}
+	if err := json.Unmarshal(body, &errBody); err == nil {
+		if code, ok := errBody["error"].(string); ok {
+			apiErr.ErrorCode = code
+		}
+		if msg, ok := errBody["msg"].(string); ok {
+			apiErr.Message = msg
+		} else if desc, ok := errBody["description"].(string); ok {
+			apiErr.Message = desc
+		}
+		if descCode, ok := errBody["description_code"].(string); ok {
+			apiErr.DescriptionCode = descCode
+		}
+		if apiErr.RequestID == "" {
+			if id, ok := errBody["request_id"].(string); ok {
+				apiErr.RequestID = id
+			}
+		}
+	}
+
+	return apiErr
+}
+
+// notFoundError construye un APIError 404 para recursos que se buscan en un listado
+func notFoundError(method, endpoint, msg string) *APIError {
+	return &APIError{
+		StatusCode: http.StatusNotFound,
+		Method:     method,
+		Endpoint:   endpoint,
+		ErrorCode:  "not_found",
+		Message:    msg,
+	}
+}
+
+// StatusCode devuelve el código HTTP de un error de la API, o 0 si no lo es
+func StatusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// IsNotFound indica si el error corresponde a un recurso inexistente (404)
+func IsNotFound(err error) bool {
+	return StatusCode(err) == http.StatusNotFound
+}
+
+// IsConflict indica si el error corresponde a un conflicto (409)
+func IsConflict(err error) bool {
+	return StatusCode(err) == http.StatusConflict
+}
+
+// IsForbidden indica si el error corresponde a falta de permisos (403)
+func IsForbidden(err error) bool {
+	return StatusCode(err) == http.StatusForbidden
+}
+
+// IsUnauthorized indica si el error corresponde a credenciales inválidas o caducadas (401)
+func IsUnauthorized(err error) bool {
+	return StatusCode(err) == http.StatusUnauthorized
+}
+
+// IsPreconditionRequired indica si la API exige una acción previa (428),
+// por ejemplo detener los desktops antes de eliminar un deployment
+func IsPreconditionRequired(err error) bool {
+	return StatusCode(err) == http.StatusPreconditionRequired
+}
@@ -1,9 +1,8 @@
 package client
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
 	"net/http"
 )
 
@@ -18,35 +17,119 @@ type Group struct {
 }
 
 // GetGroups obtiene la lista de grupos
-func (c *Client) GetGroups() ([]Group, error) {
-	reqURL := fmt.Sprintf("https://%s/api/v3/admin/groups", c.HostURL)
+func (c *Client) GetGroups(ctx context.Context) ([]Group, error) {
+	var groups []Group
+	if err := c.do(ctx, http.MethodGet, "/api/v3/admin/groups", nil, &groups); err != nil {
+		return nil, fmt.Errorf("error obteniendo grupos: %w", err)
+	}
+
+	return groups, nil
+}
 
-	req, err := http.NewRequest("GET", reqURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creando petición GET: %w", err)
+// EnrollmentCodes devuelve los códigos de auto-registro activos del grupo por rol
+// (manager, advanced, user). La API guarda false para los roles sin código.
+func (g *Group) EnrollmentCodes() map[string]string {
+	codes := map[string]string{}
+	for role, value := range g.Enrollment {
+		if code, ok := value.(string); ok && code != "" {
+			codes[role] = code
+		}
+	}
+	return codes
+}
+
+// GetGroup obtiene un grupo específico por ID
+func (c *Client) GetGroup(ctx context.Context, groupID string) (*Group, error) {
+	var group Group
+	if err := c.do(ctx, http.MethodGet, "/api/v3/admin/group/"+groupID, nil, &group); err != nil {
+		return nil, fmt.Errorf("error obteniendo grupo: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.Token)
+	return &group, nil
+}
 
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error ejecutando GET: %w", err)
+// CreateGroup crea un grupo dentro de una categoría y devuelve su ID
+func (c *Client) CreateGroup(ctx context.Context, name, description, parentCategory string, linkedGroups []string) (string, error) {
+	if linkedGroups == nil {
+		linkedGroups = []string{}
 	}
-	defer res.Body.Close()
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
+	payload := map[string]interface{}{
+		"name":            name,
+		"description":     description,
+		"parent_category": parentCategory,
+		"linked_groups":   linkedGroups,
 	}
 
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("error obteniendo grupos (status %d): %s", res.StatusCode, string(body))
+	var response struct {
+		ID string `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/v3/admin/group", payload, &response); err != nil {
+		return "", fmt.Errorf("error creando grupo: %w", err)
+	}
+	if response.ID == "" {
+		return "", fmt.Errorf("error creando grupo: la API no devolvió el ID")
 	}
 
-	var groups []Group
-	if err := json.Unmarshal(body, &groups); err != nil {
-		return nil, fmt.Errorf("error parseando respuesta: %w", err)
+	return response.ID, nil
+}
+
+// UpdateGroup modifica un grupo existente. updateData solo debe contener los campos
+// que cambian (name, description, linked_groups).
+func (c *Client) UpdateGroup(ctx context.Context, groupID string, updateData map[string]interface{}) error {
+	if err := c.do(ctx, http.MethodPut, "/api/v3/admin/group/"+groupID, updateData, nil); err != nil {
+		return fmt.Errorf("error actualizando grupo: %w", err)
 	}
 
-	return groups, nil
+	return nil
+}
+
+// DeleteGroup elimina un grupo. La API elimina también sus usuarios y los recursos de estos.
+func (c *Client) DeleteGroup(ctx context.Context, groupID string) error {
+	err := c.do(ctx, http.MethodDelete, "/api/v3/admin/group/"+groupID, nil, nil)
+
+	// Considerar éxito el 404 (ya no existe)
+	if err != nil && !IsNotFound(err) {
+		return fmt.Errorf("error eliminando grupo: %w", err)
+	}
+
+	return nil
+}
+
+// EnrollmentRoles son los roles para los que un grupo puede tener código de auto-registro
+var EnrollmentRoles = []string{"manager", "advanced", "user"}
+
+// ResetGroupEnrollment genera un código de auto-registro nuevo para un rol del grupo,
+// invalidando el anterior, y lo devuelve
+func (c *Client) ResetGroupEnrollment(ctx context.Context, groupID, role string) (string, error) {
+	payload := map[string]interface{}{
+		"role":   role,
+		"action": "reset",
+	}
+
+	var response struct {
+		Code string `json:"code"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/v3/admin/group/"+groupID+"/enrollment", payload, &response); err != nil {
+		return "", fmt.Errorf("error generando código de auto-registro: %w", err)
+	}
+	if response.Code == "" {
+		return "", fmt.Errorf("error generando código de auto-registro: la API no devolvió el código")
+	}
+
+	return response.Code, nil
+}
+
+// DisableGroupEnrollment elimina el código de auto-registro de un rol del grupo
+func (c *Client) DisableGroupEnrollment(ctx context.Context, groupID, role string) error {
+	payload := map[string]interface{}{
+		"role":   role,
+		"action": "disable",
+	}
+
+	if err := c.do(ctx, http.MethodPost, "/api/v3/admin/group/"+groupID+"/enrollment", payload, nil); err != nil {
+		return fmt.Errorf("error desactivando código de auto-registro: %w", err)
+	}
+
+	return nil
 }
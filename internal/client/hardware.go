@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// kibPerGB es el número de KiB en un GB. domain/info, deployment/info y template/{id}
+// devuelven la memoria en KiB, mientras que los endpoints de edición trabajan en GB.
+const kibPerGB = 1024 * 1024
+
+// Hardware representa el hardware real de un desktop o de los desktops de un deployment
+type Hardware struct {
+	VCPUs      int64
+	Memory     float64 // GB
+	Interfaces []string
+	ISOs       []string
+	Floppies   []string
+	BootOrder  []string
+}
+
+// kibToGB convierte a GB un valor de memoria en KiB
+func kibToGB(value float64) float64 {
+	// Redondear para evitar ruido de coma flotante (p. ej. 1.4999999)
+	return math.Round(value/kibPerGB*1000) / 1000
+}
+
+// parseHardware extrae el hardware de la respuesta de la API (memoria en KiB)
+func parseHardware(raw map[string]interface{}) *Hardware {
+	if raw == nil {
+		return nil
+	}
+
+	hw := &Hardware{
+		Interfaces: idList(raw["interfaces"]),
+		ISOs:       idList(raw["isos"]),
+		Floppies:   idList(raw["floppies"]),
+		BootOrder:  idList(raw["boot_order"]),
+	}
+
+	if vcpus, ok := raw["vcpus"].(float64); ok {
+		hw.VCPUs = int64(vcpus)
+	}
+	if memory, ok := raw["memory"].(float64); ok {
+		hw.Memory = kibToGB(memory)
+	}
+
+	return hw
+}
+
+// idList convierte una lista de la API en una lista de IDs. Los elementos pueden ser
+// strings ("default") u objetos con campo id ({"id": "default", "mac": "..."}).
+func idList(raw interface{}) []string {
+	items, ok := raw.([]interface{})
+	if !ok {
+		return []string{}
+	}
+
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		switch v := item.(type) {
+		case string:
+			ids = append(ids, v)
+		case map[string]interface{}:
+			if id, ok := v["id"].(string); ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	return ids
+}
+
+// parseViewers extrae los viewers habilitados de guest_properties, ordenados por nombre
+func parseViewers(guestProperties interface{}) []string {
+	props, ok := guestProperties.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	viewersMap, ok := props["viewers"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	viewers := make([]string, 0, len(viewersMap))
+	for viewer := range viewersMap {
+		viewers = append(viewers, viewer)
+	}
+	sort.Strings(viewers)
+
+	return viewers
+}
+
+// DeploymentSettings agrupa la configuración de un deployment que solo informa deployment/info
+type DeploymentSettings struct {
+	Hardware        *Hardware // nil si la API no lo informa
+	Viewers         []string  // nil si la API no lo informa
+	UserPermissions []string
+}
+
+// GetDeploymentSettings obtiene el hardware, los viewers y los permisos de usuario
+// configurados en un deployment
+func (c *Client) GetDeploymentSettings(ctx context.Context, deploymentID string) (*DeploymentSettings, error) {
+	info, err := c.GetDeploymentInfo(ctx, deploymentID)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo configuración del deployment: %w", err)
+	}
+
+	raw, _ := info["hardware"].(map[string]interface{})
+	return &DeploymentSettings{
+		Hardware:        parseHardware(raw),
+		Viewers:         parseViewers(info["guest_properties"]),
+		UserPermissions: idList(info["user_permissions"]),
+	}, nil
+}
@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/tknika/terraform-provider-isardvdi/internal/testserver"
+)
+
+func TestKiBToGB(t *testing.T) {
+	tests := []struct {
+		value float64
+		want  float64
+	}{
+		{4194304, 4},
+		{1572864, 1.5},
+		{1572863.9, 1.5}, // ruido de coma flotante
+		{4096, 0.004},    // valores pequeños también son KiB
+		{4097 * 1024 * 1024, 4097},
+	}
+
+	for _, tt := range tests {
+		if got := kibToGB(tt.value); got != tt.want {
+			t.Errorf("kibToGB(%v) = %v, se esperaba %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestIDList(t *testing.T) {
+	raw := []interface{}{"default", map[string]interface{}{"id": "wireguard", "mac": "52:54:00:00:00:01"}, 3}
+	if got := idList(raw); !slices.Equal(got, []string{"default", "wireguard"}) {
+		t.Errorf("idList = %v", got)
+	}
+	if got := idList(nil); got == nil || len(got) != 0 {
+		t.Errorf("idList(nil) = %#v, se esperaba una lista vacía", got)
+	}
+}
+
+// testAllowed da acceso al usuario administrador sembrado en el servidor falso
+var testAllowed = map[string]interface{}{
+	"roles":      false,
+	"categories": false,
+	"groups":     false,
+	"users":      []string{testserver.DefaultUserID},
+}
+
+func TestCreateDeploymentMemory(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+
+	memory := 1.5
+	id, err := c.CreateDeployment(ctx, "con-memoria", "", testserver.DefaultTemplateID, "desktop", false, testAllowed, nil, &memory, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateDeployment: %v", err)
+	}
+
+	// La memoria se envía en GB sin truncar
+	req, ok := srv.LastRequest(http.MethodPost, "/api/v3/deployments")
+	if !ok {
+		t.Fatal("no se ha enviado la creación del deployment")
+	}
+	hardware, _ := req.JSON()["hardware"].(map[string]interface{})
+	if hardware["memory"] != 1.5 {
+		t.Errorf("hardware.memory enviado = %v, se esperaba 1.5", hardware["memory"])
+	}
+
+	settings, err := c.GetDeploymentSettings(ctx, id)
+	if err != nil {
+		t.Fatalf("GetDeploymentSettings: %v", err)
+	}
+	if settings.Hardware.Memory != 1.5 {
+		t.Errorf("memoria leída = %v GB, se esperaba 1.5", settings.Hardware.Memory)
+	}
+}
+
+func TestCreateDeploymentTemplateMemory(t *testing.T) {
+	c, _ := newTestClient(t)
+	ctx := context.Background()
+
+	// Sin memory se usa la del template, que la API devuelve en KiB
+	id, err := c.CreateDeployment(ctx, "sin-memoria", "", testserver.DefaultTemplateID, "desktop", false, testAllowed, nil, nil, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateDeployment: %v", err)
+	}
+
+	settings, err := c.GetDeploymentSettings(ctx, id)
+	if err != nil {
+		t.Fatalf("GetDeploymentSettings: %v", err)
+	}
+	if settings.Hardware.Memory != 4 {
+		t.Errorf("memoria leída = %v GB, se esperaba 4 (la del template)", settings.Hardware.Memory)
+	}
+}
+
+func TestGetDesktopMemory(t *testing.T) {
+	c, _ := newTestClient(t)
+	ctx := context.Background()
+
+	memory := 1.5
+	id, err := c.CreatePersistentDesktop(ctx, "con-memoria", "", testserver.DefaultTemplateID, nil, &memory, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreatePersistentDesktop: %v", err)
+	}
+
+	// domain/info devuelve KiB; el cliente lo convierte a GB
+	desktop, err := c.GetDesktop(ctx, id)
+	if err != nil {
+		t.Fatalf("GetDesktop: %v", err)
+	}
+	if desktop.Memory != 1.5 {
+		t.Errorf("memoria leída = %v GB, se esperaba 1.5", desktop.Memory)
+	}
+}
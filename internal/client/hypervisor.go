@@ -0,0 +1,36 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Hypervisor representa un hipervisor de Isard VDI
+type Hypervisor struct {
+	ID           string                 `json:"id"`
+	Hostname     string                 `json:"hostname"`
+	Description  string                 `json:"description"`
+	Status       string                 `json:"status"`
+	Enabled      bool                   `json:"enabled"`
+	OnlyForced   bool                   `json:"only_forced"`
+	GPUOnly      bool                   `json:"gpu_only"`
+	Capabilities HypervisorCapabilities `json:"capabilities"`
+}
+
+// HypervisorCapabilities indica para qué usa Isard VDI el hipervisor: arrancar
+// desktops, operaciones de disco o ambas
+type HypervisorCapabilities struct {
+	Hypervisor     bool `json:"hypervisor"`
+	DiskOperations bool `json:"disk_operations"`
+}
+
+// GetHypervisors obtiene la lista de hipervisores (requiere rol de administrador)
+func (c *Client) GetHypervisors(ctx context.Context) ([]Hypervisor, error) {
+	var hypervisors []Hypervisor
+	if err := c.do(ctx, http.MethodGet, "/api/v3/hypervisors", nil, &hypervisors); err != nil {
+		return nil, fmt.Errorf("error obteniendo hipervisores: %w", err)
+	}
+
+	return hypervisors, nil
+}
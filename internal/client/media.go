@@ -1,10 +1,8 @@
 package client
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
 	"net/http"
 	"time"
 )
@@ -29,15 +27,13 @@ type Media struct {
 }
 
 // CreateMedia crea un nuevo media
-func (c *Client) CreateMedia(
+func (c *Client) CreateMedia(ctx context.Context, 
 	name string,
 	description string,
 	url string,
 	kind string,
 	allowed map[string]interface{},
 ) (string, error) {
-	reqURL := fmt.Sprintf("https://%s/api/v3/media", c.HostURL)
-
 	payload := map[string]interface{}{
 		"name":        name,
 		"description": description,
@@ -50,41 +46,19 @@ func (c *Client) CreateMedia(
 		payload["allowed"] = allowed
 	}
 
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return "", fmt.Errorf("error creando JSON: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", reqURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", fmt.Errorf("error creando petición POST: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Content-Type", "application/json")
-
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("error ejecutando POST: %w", err)
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return "", fmt.Errorf("error leyendo respuesta: %w", err)
-	}
-
-	if res.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("error creando media (status %d): %s", res.StatusCode, string(body))
+	if err := c.do(ctx, http.MethodPost, "/api/v3/media", payload, nil); err != nil {
+		return "", fmt.Errorf("error creando media: %w", err)
 	}
 
 	// La API devuelve el media creado, extraer el ID
 	// Como la API puede devolver vacío, necesitamos obtener el media por nombre
 	// Esperamos un poco para que se cree
-	time.Sleep(2 * time.Second)
+	if err := sleepContext(ctx, 2*time.Second); err != nil {
+		return "", fmt.Errorf("espera cancelada: %w", err)
+	}
 	
 	// Obtener la lista de medias y buscar el que acabamos de crear
-	medias, err := c.GetMedias()
+	medias, err := c.GetMedias(ctx)
 	if err != nil {
 		return "", fmt.Errorf("error obteniendo medias después de crear: %w", err)
 	}
@@ -100,9 +74,9 @@ func (c *Client) CreateMedia(
 }
 
 // GetMedia obtiene información de un media específico
-func (c *Client) GetMedia(mediaID string) (*Media, error) {
+func (c *Client) GetMedia(ctx context.Context, mediaID string) (*Media, error) {
 	// Obtener todos los medias y buscar el específico
-	medias, err := c.GetMedias()
+	medias, err := c.GetMedias(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -113,67 +87,23 @@ func (c *Client) GetMedia(mediaID string) (*Media, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("media no encontrado: %s", mediaID)
+	return nil, notFoundError("GET", "/api/v3/media", fmt.Sprintf("media no encontrado: %s", mediaID))
 }
 
 // GetMedias obtiene la lista de medias del usuario
-func (c *Client) GetMedias() ([]Media, error) {
-	reqURL := fmt.Sprintf("https://%s/api/v3/media", c.HostURL)
-
-	req, err := http.NewRequest("GET", reqURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creando petición GET: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error ejecutando GET: %w", err)
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
-	}
-
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("error obteniendo medias (status %d): %s", res.StatusCode, string(body))
-	}
-
+func (c *Client) GetMedias(ctx context.Context) ([]Media, error) {
 	var medias []Media
-	if err := json.Unmarshal(body, &medias); err != nil {
-		return nil, fmt.Errorf("error parseando respuesta: %w", err)
+	if err := c.do(ctx, http.MethodGet, "/api/v3/media", nil, &medias); err != nil {
+		return nil, fmt.Errorf("error obteniendo medias: %w", err)
 	}
 
 	return medias, nil
 }
 
 // DeleteMedia elimina un media
-func (c *Client) DeleteMedia(mediaID string) error {
-	reqURL := fmt.Sprintf("https://%s/api/v3/media/%s", c.HostURL, mediaID)
-
-	req, err := http.NewRequest("DELETE", reqURL, nil)
-	if err != nil {
-		return fmt.Errorf("error creando petición DELETE: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("error ejecutando DELETE: %w", err)
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return fmt.Errorf("error leyendo respuesta: %w", err)
-	}
-
-	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("error eliminando media (status %d): %s", res.StatusCode, string(body))
+func (c *Client) DeleteMedia(ctx context.Context, mediaID string) error {
+	if err := c.do(ctx, http.MethodDelete, "/api/v3/media/"+mediaID, nil, nil); err != nil {
+		return fmt.Errorf("error eliminando media: %w", err)
 	}
 
 	return nil
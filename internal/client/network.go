@@ -2,9 +2,9 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strconv"
 )
@@ -26,9 +26,7 @@ type Network struct {
 }
 
 // CreateNetwork crea una nueva red de usuario
-func (c *Client) CreateNetwork(name, description, model, qosID string, allowed map[string]interface{}) (string, error) {
-	reqURL := fmt.Sprintf("https://%s/api/v3/user/networks", c.HostURL)
-
+func (c *Client) CreateNetwork(ctx context.Context, name, description, model, qosID string, allowed map[string]interface{}) (string, error) {
 	// Construir el payload
 	payload := map[string]interface{}{
 		"name":        name,
@@ -47,88 +45,64 @@ func (c *Client) CreateNetwork(name, description, model, qosID string, allowed m
 		payload["allowed"] = allowed
 	}
 
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return "", fmt.Errorf("error codificando JSON: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", reqURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", fmt.Errorf("error creando la petición POST: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Content-Type", "application/json")
-
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("error ejecutando POST: %w", err)
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return "", fmt.Errorf("error leyendo respuesta: %w", err)
-	}
-
-	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
-		return "", fmt.Errorf("error creando red (status %d): %s", res.StatusCode, string(body))
-	}
-
-	// Parsear la respuesta para obtener el ID
 	var response map[string]interface{}
-	if err := json.Unmarshal(body, &response); err != nil {
-		return "", fmt.Errorf("error parseando respuesta JSON: %w", err)
+	if err := c.do(ctx, http.MethodPost, "/api/v3/user/networks", payload, &response); err != nil {
+		return "", fmt.Errorf("error creando red: %w", err)
 	}
 
 	networkID, ok := response["id"].(string)
 	if !ok {
-		return "", fmt.Errorf("no se encontró el ID en la respuesta: %s", string(body))
+		return "", fmt.Errorf("no se encontró el ID en la respuesta: %v", response)
 	}
 
 	return networkID, nil
 }
 
 // GetNetwork obtiene la información de una red
-func (c *Client) GetNetwork(networkID string) (*Network, error) {
-	reqURL := fmt.Sprintf("https://%s/api/v3/user/networks/%s", c.HostURL, networkID)
-
-	req, err := http.NewRequest("GET", reqURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creando la petición GET: %w", err)
+func (c *Client) GetNetwork(ctx context.Context, networkID string) (*Network, error) {
+	var body []byte
+	if err := c.do(ctx, http.MethodGet, "/api/v3/user/networks/"+networkID, nil, &body); err != nil {
+		return nil, fmt.Errorf("error obteniendo red: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Content-Type", "application/json")
-
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error ejecutando GET: %w", err)
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
+	// Parsear la respuesta usando un decoder con UseNumber para manejar números grandes
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	
+	var rawNetwork map[string]interface{}
+	if err := decoder.Decode(&rawNetwork); err != nil {
+		return nil, fmt.Errorf("error parseando respuesta JSON: %w", err)
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("network not found")
-	}
+	return parseNetwork(rawNetwork), nil
+}
 
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("error obteniendo red (status %d): %s", res.StatusCode, string(body))
+// ListNetworks obtiene todas las redes de usuario visibles
+func (c *Client) ListNetworks(ctx context.Context) ([]Network, error) {
+	var body []byte
+	if err := c.do(ctx, http.MethodGet, "/api/v3/user/networks", nil, &body); err != nil {
+		return nil, fmt.Errorf("error listando redes: %w", err)
 	}
 
-	// Parsear la respuesta usando un decoder con UseNumber para manejar números grandes
+	// Mismo decoder que GetNetwork para no perder precisión en metadata_id
 	decoder := json.NewDecoder(bytes.NewReader(body))
 	decoder.UseNumber()
-	
-	var rawNetwork map[string]interface{}
-	if err := decoder.Decode(&rawNetwork); err != nil {
+
+	var rawNetworks []map[string]interface{}
+	if err := decoder.Decode(&rawNetworks); err != nil {
 		return nil, fmt.Errorf("error parseando respuesta JSON: %w", err)
 	}
 
+	networks := make([]Network, 0, len(rawNetworks))
+	for _, raw := range rawNetworks {
+		networks = append(networks, *parseNetwork(raw))
+	}
+
+	return networks, nil
+}
+
+// parseNetwork construye una Network a partir de la respuesta de la API decodificada con UseNumber
+func parseNetwork(rawNetwork map[string]interface{}) *Network {
 	network := &Network{}
 	
 	// Parsear campos uno por uno
@@ -164,13 +138,24 @@ func (c *Client) GetNetwork(networkID string) (*Network, error) {
 		}
 	}
 
-	return network, nil
+	if allowed, ok := rawNetwork["allowed"].(map[string]interface{}); ok {
+		network.Allowed = allowed
+	}
+	if user, ok := rawNetwork["user"].(string); ok {
+		network.User = user
+	}
+	if group, ok := rawNetwork["group"].(string); ok {
+		network.Group = group
+	}
+	if category, ok := rawNetwork["category"].(string); ok {
+		network.Category = category
+	}
+
+	return network
 }
 
 // UpdateNetwork actualiza una red existente
-func (c *Client) UpdateNetwork(networkID string, name, description, qosID *string, allowed map[string]interface{}) error {
-	reqURL := fmt.Sprintf("https://%s/api/v3/user/networks/%s", c.HostURL, networkID)
-
+func (c *Client) UpdateNetwork(ctx context.Context, networkID string, name, description, qosID *string, allowed map[string]interface{}) error {
 	// Construir el payload solo con los campos que se actualizan
 	payload := make(map[string]interface{})
 	
@@ -190,64 +175,21 @@ func (c *Client) UpdateNetwork(networkID string, name, description, qosID *strin
 		payload["allowed"] = allowed
 	}
 
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("error codificando JSON: %w", err)
-	}
-
-	req, err := http.NewRequest("PUT", reqURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("error creando la petición PUT: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Content-Type", "application/json")
-
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("error ejecutando PUT: %w", err)
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return fmt.Errorf("error leyendo respuesta: %w", err)
-	}
-
-	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("error actualizando red (status %d): %s", res.StatusCode, string(body))
+	if err := c.do(ctx, http.MethodPut, "/api/v3/user/networks/"+networkID, payload, nil); err != nil {
+		return fmt.Errorf("error actualizando red: %w", err)
 	}
 
 	return nil
 }
 
 // DeleteNetwork elimina una red
-func (c *Client) DeleteNetwork(networkID string) error {
-	reqURL := fmt.Sprintf("https://%s/api/v3/user/networks/%s", c.HostURL, networkID)
-
-	req, err := http.NewRequest("DELETE", reqURL, nil)
-	if err != nil {
-		return fmt.Errorf("error creando la petición DELETE: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Content-Type", "application/json")
-
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("error ejecutando DELETE: %w", err)
-	}
-	defer res.Body.Close()
+func (c *Client) DeleteNetwork(ctx context.Context, networkID string) error {
+	err := c.do(ctx, http.MethodDelete, "/api/v3/user/networks/"+networkID, nil, nil)
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return fmt.Errorf("error leyendo respuesta: %w", err)
+	// Considerar éxito el 404 (ya no existe)
+	if err != nil && !IsNotFound(err) {
+		return fmt.Errorf("error eliminando red: %w", err)
 	}
 
-	// Considerar éxito los códigos 200, 204 (No Content) y 404 (ya no existe)
-	if res.StatusCode == http.StatusOK || res.StatusCode == http.StatusNoContent || res.StatusCode == http.StatusNotFound {
-		return nil
-	}
-
-	return fmt.Errorf("error eliminando red (status %d): %s", res.StatusCode, string(body))
+	return nil
 }
@@ -1,10 +1,8 @@
 package client
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
 	"net/http"
 )
 
@@ -22,9 +20,7 @@ type NetworkInterface struct {
 }
 
 // CreateNetworkInterface crea una nueva interfaz de red
-func (c *Client) CreateNetworkInterface(id, name, description, net, kind, model, qosID, ifname string, allowed map[string]interface{}) error {
-	reqURL := fmt.Sprintf("https://%s/api/v3/admin/table/add/interfaces", c.HostURL)
-
+func (c *Client) CreateNetworkInterface(ctx context.Context, id, name, description, net, kind, model, qosID, ifname string, allowed map[string]interface{}) error {
 	// Construir el payload
 	payload := map[string]interface{}{
 		"id":   id,
@@ -56,91 +52,30 @@ func (c *Client) CreateNetworkInterface(id, name, description, net, kind, model,
 		payload["allowed"] = allowed
 	}
 
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("error codificando JSON: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", reqURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("error creando la petición POST: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Content-Type", "application/json")
-
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("error ejecutando POST: %w", err)
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return fmt.Errorf("error leyendo respuesta: %w", err)
-	}
-
-	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
-		return fmt.Errorf("error creando interfaz de red (status %d): %s", res.StatusCode, string(body))
+	if err := c.do(ctx, http.MethodPost, "/api/v3/admin/table/add/interfaces", payload, nil); err != nil {
+		return fmt.Errorf("error creando interfaz de red: %w", err)
 	}
 
 	return nil
 }
 
 // GetNetworkInterface obtiene la información de una interfaz de red
-func (c *Client) GetNetworkInterface(interfaceID string) (*NetworkInterface, error) {
-	reqURL := fmt.Sprintf("https://%s/api/v3/admin/table/interfaces", c.HostURL)
-
+func (c *Client) GetNetworkInterface(ctx context.Context, interfaceID string) (*NetworkInterface, error) {
 	// Crear payload con el ID para obtener un item específico
 	payload := map[string]interface{}{
 		"id": interfaceID,
 	}
 
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return nil, fmt.Errorf("error codificando JSON: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", reqURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("error creando la petición POST: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Content-Type", "application/json")
-
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error ejecutando POST: %w", err)
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
-	}
-
-	if res.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("network interface not found")
-	}
-
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("error obteniendo interfaz de red (status %d): %s", res.StatusCode, string(body))
-	}
-
-	// Parsear la respuesta
 	var iface NetworkInterface
-	if err := json.Unmarshal(body, &iface); err != nil {
-		return nil, fmt.Errorf("error parseando respuesta JSON: %w", err)
+	if err := c.do(ctx, http.MethodPost, "/api/v3/admin/table/interfaces", payload, &iface); err != nil {
+		return nil, fmt.Errorf("error obteniendo interfaz de red: %w", err)
 	}
 
 	return &iface, nil
 }
 
 // UpdateNetworkInterface actualiza una interfaz de red existente
-func (c *Client) UpdateNetworkInterface(id string, name, description, net, kind, model, qosID, ifname *string, allowed map[string]interface{}) error {
-	reqURL := fmt.Sprintf("https://%s/api/v3/admin/table/update/interfaces", c.HostURL)
-
+func (c *Client) UpdateNetworkInterface(ctx context.Context, id string, name, description, net, kind, model, qosID, ifname *string, allowed map[string]interface{}) error {
 	// Construir el payload con el ID y los campos a actualizar
 	payload := map[string]interface{}{
 		"id": id,
@@ -178,64 +113,21 @@ func (c *Client) UpdateNetworkInterface(id string, name, description, net, kind,
 		payload["allowed"] = allowed
 	}
 
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("error codificando JSON: %w", err)
-	}
-
-	req, err := http.NewRequest("PUT", reqURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("error creando la petición PUT: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Content-Type", "application/json")
-
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("error ejecutando PUT: %w", err)
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return fmt.Errorf("error leyendo respuesta: %w", err)
-	}
-
-	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("error actualizando interfaz de red (status %d): %s", res.StatusCode, string(body))
+	if err := c.do(ctx, http.MethodPut, "/api/v3/admin/table/update/interfaces", payload, nil); err != nil {
+		return fmt.Errorf("error actualizando interfaz de red: %w", err)
 	}
 
 	return nil
 }
 
 // DeleteNetworkInterface elimina una interfaz de red
-func (c *Client) DeleteNetworkInterface(interfaceID string) error {
-	reqURL := fmt.Sprintf("https://%s/api/v3/admin/table/interfaces/%s", c.HostURL, interfaceID)
-
-	req, err := http.NewRequest("DELETE", reqURL, nil)
-	if err != nil {
-		return fmt.Errorf("error creando la petición DELETE: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Content-Type", "application/json")
+func (c *Client) DeleteNetworkInterface(ctx context.Context, interfaceID string) error {
+	err := c.do(ctx, http.MethodDelete, "/api/v3/admin/table/interfaces/"+interfaceID, nil, nil)
 
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("error ejecutando DELETE: %w", err)
+	// Considerar éxito el 404 (ya no existe)
+	if err != nil && !IsNotFound(err) {
+		return fmt.Errorf("error eliminando interfaz de red: %w", err)
 	}
-	defer res.Body.Close()
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return fmt.Errorf("error leyendo respuesta: %w", err)
-	}
-
-	// Considerar éxito los códigos 200, 204 (No Content) y 404 (ya no existe)
-	if res.StatusCode == http.StatusOK || res.StatusCode == http.StatusNoContent || res.StatusCode == http.StatusNotFound {
-		return nil
-	}
-
-	return fmt.Errorf("error eliminando interfaz de red (status %d): %s", res.StatusCode, string(body))
+	return nil
 }
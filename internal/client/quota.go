@@ -0,0 +1,115 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Tipos de entidad a los que se puede asignar una cuota
+const (
+	QuotaTargetCategory = "category"
+	QuotaTargetGroup    = "group"
+	QuotaTargetUser     = "user"
+)
+
+// Quota representa los máximos de recursos de una categoría, grupo o usuario.
+// Se usa tanto para la cuota (por usuario) como para los límites (del conjunto).
+type Quota struct {
+	Desktops         int64 `json:"desktops"`           // desktops creados
+	Running          int64 `json:"running"`            // desktops arrancados a la vez
+	VCPUs            int64 `json:"vcpus"`              // vCPUs de los desktops arrancados
+	Memory           int64 `json:"memory"`             // memoria en GB de los desktops arrancados
+	Templates        int64 `json:"templates"`          // templates creados
+	ISOs             int64 `json:"isos"`               // medios subidos
+	DesktopsDiskSize int64 `json:"desktops_disk_size"` // tamaño máximo en GB del disco de cada desktop
+}
+
+// QuotaSettings agrupa la cuota y los límites de una entidad. La API usa false
+// para indicar que no hay restricción, que aquí se representa con nil.
+type QuotaSettings struct {
+	Quota  *Quota
+	Limits *Quota
+}
+
+// UnmarshalJSON interpreta false (o null) como ausencia de cuota o límites
+func (q *QuotaSettings) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Quota  json.RawMessage `json:"quota"`
+		Limits json.RawMessage `json:"limits"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var err error
+	if q.Quota, err = decodeQuota(raw.Quota); err != nil {
+		return err
+	}
+	q.Limits, err = decodeQuota(raw.Limits)
+	return err
+}
+
+// decodeQuota decodifica un objeto de cuota o devuelve nil si vale false o null
+func decodeQuota(data json.RawMessage) (*Quota, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("false")) || bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+
+	var quota Quota
+	if err := json.Unmarshal(data, &quota); err != nil {
+		return nil, err
+	}
+	return &quota, nil
+}
+
+// quotaPayload devuelve la cuota tal como la espera la API (false si es nil)
+func quotaPayload(quota *Quota) interface{} {
+	if quota == nil {
+		return false
+	}
+	return quota
+}
+
+// GetQuota obtiene la cuota y los límites de una categoría, grupo o usuario
+func (c *Client) GetQuota(ctx context.Context, kind, id string) (*QuotaSettings, error) {
+	var settings QuotaSettings
+	if err := c.do(ctx, http.MethodGet, "/api/v3/admin/quota/"+kind+"/"+id, nil, &settings); err != nil {
+		return nil, fmt.Errorf("error obteniendo cuota: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// UpdateQuota fija la cuota por usuario de una categoría, grupo o usuario (nil la elimina).
+// Con propagate, la API la aplica también a los grupos y usuarios que contiene.
+func (c *Client) UpdateQuota(ctx context.Context, kind, id string, quota *Quota, propagate bool) error {
+	payload := map[string]interface{}{
+		"quota":     quotaPayload(quota),
+		"propagate": propagate,
+	}
+
+	if err := c.do(ctx, http.MethodPut, "/api/v3/admin/quota/"+kind+"/"+id, payload, nil); err != nil {
+		return fmt.Errorf("error actualizando cuota: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateLimits fija los límites del conjunto de una categoría o grupo (nil los elimina).
+// Los usuarios no tienen límites, solo cuota.
+func (c *Client) UpdateLimits(ctx context.Context, kind, id string, limits *Quota, propagate bool) error {
+	payload := map[string]interface{}{
+		"limits":    quotaPayload(limits),
+		"propagate": propagate,
+	}
+
+	if err := c.do(ctx, http.MethodPut, "/api/v3/admin/limits/"+kind+"/"+id, payload, nil); err != nil {
+		return fmt.Errorf("error actualizando límites: %w", err)
+	}
+
+	return nil
+}
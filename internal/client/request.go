@@ -0,0 +1,267 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Valores por defecto de la política de reintentos
+const (
+	DefaultMaxRetries   = 4
+	DefaultRetryWaitMin = 1 * time.Second
+	DefaultRetryWaitMax = 30 * time.Second
+)
+
+// apiRequest describe una petición a la API antes de ser enviada
+type apiRequest struct {
+	method      string
+	path        string
+	body        []byte
+	contentType string
+	accept      string
+	// noAuth evita enviar la cabecera Authorization (p. ej. en el login)
+	noAuth bool
+}
+
+// url construye la URL absoluta de un endpoint de la API a partir de la URL base
+func (c *Client) url(path string) string {
+	return strings.TrimRight(c.BaseURL.String(), "/") + path
+}
+
+// do ejecuta una petición JSON contra la API y decodifica la respuesta en out.
+// in se codifica como JSON si no es nil. out puede ser nil (se descarta la respuesta),
+// *[]byte (se devuelve el cuerpo sin procesar) o cualquier destino válido para json.Unmarshal.
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	r := &apiRequest{
+		method: method,
+		path:   path,
+		accept: "application/json",
+	}
+
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("error codificando JSON: %w", err)
+		}
+		r.body = data
+		r.contentType = "application/json"
+	}
+
+	body, err := c.send(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	return decodeResponse(body, out)
+}
+
+// decodeResponse vuelca el cuerpo de la respuesta en out
+func decodeResponse(body []byte, out interface{}) error {
+	switch v := out.(type) {
+	case nil:
+		return nil
+	case *[]byte:
+		*v = body
+		return nil
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("error parseando respuesta JSON: %w", err)
+	}
+
+	return nil
+}
+
+// send envía la petición aplicando la política de reintentos con backoff exponencial
+// y jitter ante respuestas 429/502/503/504 y conexiones rechazadas o reiniciadas.
+// Las peticiones no idempotentes (POST) solo se reintentan cuando es seguro que la API
+// no las ha procesado: conexión rechazada o respuesta 429/503.
+// Con login por formulario, renueva el token antes de que caduque y, ante un 401,
+// repite el login y reenvía la petición una única vez.
+func (c *Client) send(ctx context.Context, r *apiRequest) ([]byte, error) {
+	var token string
+	if !r.noAuth {
+		var err error
+		if token, err = c.currentToken(ctx); err != nil {
+			return nil, fmt.Errorf("error renovando la sesión: %w", err)
+		}
+	}
+
+	reauthenticated := false
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, r.method, c.url(r.path), bytes.NewReader(r.body))
+		if err != nil {
+			return nil, fmt.Errorf("error creando la petición %s: %w", r.method, err)
+		}
+
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		if r.contentType != "" {
+			req.Header.Set("Content-Type", r.contentType)
+		}
+		if r.accept != "" {
+			req.Header.Set("Accept", r.accept)
+		}
+
+		res, err := c.HTTPClient.Do(req)
+		if err != nil {
+			if attempt < c.MaxRetries && isRetryableNetError(ctx, r.method, err) {
+				if waitErr := sleepContext(ctx, c.backoff(attempt)); waitErr != nil {
+					return nil, fmt.Errorf("error ejecutando %s %s: %w", r.method, r.path, waitErr)
+				}
+				continue
+			}
+			return nil, fmt.Errorf("error ejecutando %s %s: %w", r.method, r.path, err)
+		}
+
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			if attempt < c.MaxRetries && isRetryableNetError(ctx, r.method, err) {
+				if waitErr := sleepContext(ctx, c.backoff(attempt)); waitErr != nil {
+					return nil, fmt.Errorf("error leyendo respuesta: %w", waitErr)
+				}
+				continue
+			}
+			return nil, fmt.Errorf("error leyendo respuesta: %w", err)
+		}
+
+		if res.StatusCode >= 200 && res.StatusCode < 300 {
+			return body, nil
+		}
+
+		apiErr := newAPIError(req, res, body)
+		if res.StatusCode == http.StatusUnauthorized && !r.noAuth && !reauthenticated && c.canReauthenticate() {
+			reauthenticated = true
+			if token, err = c.reauthenticate(ctx, token); err != nil {
+				return nil, fmt.Errorf("error renovando la sesión tras %s: %w", apiErr, err)
+			}
+			continue
+		}
+		if attempt < c.MaxRetries && isRetryableStatus(r.method, res.StatusCode) {
+			wait, ok := retryAfter(res)
+			if !ok {
+				wait = c.backoff(attempt)
+			}
+			if waitErr := sleepContext(ctx, wait); waitErr != nil {
+				return nil, fmt.Errorf("error ejecutando %s %s: %w (último error: %s)", r.method, r.path, waitErr, apiErr)
+			}
+			continue
+		}
+
+		return nil, apiErr
+	}
+}
+
+// backoff calcula la espera antes del siguiente intento (exponencial con jitter)
+func (c *Client) backoff(attempt int) time.Duration {
+	waitMin := c.RetryWaitMin
+	if waitMin <= 0 {
+		waitMin = DefaultRetryWaitMin
+	}
+	waitMax := c.RetryWaitMax
+	if waitMax < waitMin {
+		waitMax = waitMin
+	}
+
+	wait := waitMin << uint(attempt)
+	if wait <= 0 || wait > waitMax {
+		wait = waitMax
+	}
+
+	// Jitter: entre la mitad y el total de la espera calculada
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// isIdempotent indica si repetir la petición tiene el mismo efecto que enviarla una vez
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isRetryableStatus indica si el código HTTP corresponde a un error transitorio. Un
+// 502/504 puede llegar después de que la API haya procesado la petición, así que solo
+// se reintenta en peticiones idempotentes.
+func isRetryableStatus(method string, status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return isIdempotent(method)
+	}
+	return false
+}
+
+// isRetryableNetError indica si el error de red es transitorio (API reiniciándose). Una
+// conexión reiniciada o cortada puede producirse después de que la API haya procesado
+// la petición, así que solo se reintenta en peticiones idempotentes; una conexión
+// rechazada garantiza que la petición no llegó a enviarse.
+func isRetryableNetError(ctx context.Context, method string, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	if !isIdempotent(method) {
+		return false
+	}
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// retryAfter interpreta la cabecera Retry-After (segundos o fecha HTTP)
+func retryAfter(res *http.Response) (time.Duration, bool) {
+	value := res.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
+
+// sleepContext espera la duración indicada o hasta que se cancele el contexto
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
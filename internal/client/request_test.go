@@ -0,0 +1,249 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/tknika/terraform-provider-isardvdi/internal/testserver"
+)
+
+// newTestClient arranca un servidor falso y devuelve un cliente con esperas mínimas entre reintentos
+func newTestClient(t *testing.T) (*Client, *testserver.Server) {
+	t.Helper()
+
+	srv := testserver.New()
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(srv.Endpoint(), testserver.DefaultToken, true)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	c.RetryWaitMin = time.Millisecond
+	c.RetryWaitMax = 5 * time.Millisecond
+
+	return c, srv
+}
+
+// countRequests cuenta las peticiones recibidas con el método y la ruta indicados
+func countRequests(srv *testserver.Server, method, path string) int {
+	n := 0
+	for _, r := range srv.Requests() {
+		if r.Method == method && r.Path == path {
+			n++
+		}
+	}
+	return n
+}
+
+func TestSendRetriesTransientErrors(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+
+	srv.FailNext(http.MethodGet, "/api/v3/admin/groups", http.StatusServiceUnavailable, 1)
+	srv.FailNext(http.MethodGet, "/api/v3/admin/groups", http.StatusBadGateway, 1)
+
+	if _, err := c.GetGroups(ctx); err != nil {
+		t.Fatalf("GetGroups: %v", err)
+	}
+	if got := countRequests(srv, http.MethodGet, "/api/v3/admin/groups"); got != 3 {
+		t.Errorf("peticiones = %d, se esperaban 3", got)
+	}
+}
+
+func TestSendStopsAfterMaxRetries(t *testing.T) {
+	c, srv := newTestClient(t)
+	c.MaxRetries = 2
+
+	srv.FailNext(http.MethodGet, "/api/v3/admin/groups", http.StatusServiceUnavailable, 10)
+
+	_, err := c.GetGroups(context.Background())
+	if StatusCode(err) != http.StatusServiceUnavailable {
+		t.Fatalf("error = %v, se esperaba un 503", err)
+	}
+	if got := countRequests(srv, http.MethodGet, "/api/v3/admin/groups"); got != 3 {
+		t.Errorf("peticiones = %d, se esperaban 3", got)
+	}
+}
+
+func TestSendReturnsContextErrorDuringBackoff(t *testing.T) {
+	c, srv := newTestClient(t)
+	c.RetryWaitMin = time.Minute
+	c.RetryWaitMax = time.Minute
+
+	srv.FailNext(http.MethodGet, "/api/v3/admin/groups", http.StatusServiceUnavailable, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := c.GetGroups(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error = %v, se esperaba context.DeadlineExceeded", err)
+	}
+}
+
+func TestSendDoesNotRetryProcessedPost(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+
+	// Un 502 puede llegar después de que la API haya creado el desktop
+	srv.FailNext(http.MethodPost, "/api/v3/persistent_desktop", http.StatusBadGateway, 1)
+
+	_, err := c.CreatePersistentDesktop(ctx, "test", "", testserver.DefaultTemplateID, nil, nil, nil, nil, nil)
+	if StatusCode(err) != http.StatusBadGateway {
+		t.Fatalf("error = %v, se esperaba un 502", err)
+	}
+	if got := countRequests(srv, http.MethodPost, "/api/v3/persistent_desktop"); got != 1 {
+		t.Errorf("peticiones = %d, se esperaba 1", got)
+	}
+}
+
+func TestSendRetriesRejectedPost(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+
+	// Un 503 indica que la API no ha procesado la petición
+	srv.FailNext(http.MethodPost, "/api/v3/persistent_desktop", http.StatusServiceUnavailable, 1)
+
+	id, err := c.CreatePersistentDesktop(ctx, "test", "", testserver.DefaultTemplateID, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreatePersistentDesktop: %v", err)
+	}
+	if _, ok := srv.Desktop(id); !ok {
+		t.Errorf("el desktop %s no existe en el servidor", id)
+	}
+	if got := countRequests(srv, http.MethodPost, "/api/v3/persistent_desktop"); got != 2 {
+		t.Errorf("peticiones = %d, se esperaban 2", got)
+	}
+}
+
+func TestIsRetryableNetError(t *testing.T) {
+	reset := fmt.Errorf("read: %w", syscall.ECONNRESET)
+	refused := fmt.Errorf("dial: %w", syscall.ECONNREFUSED)
+
+	tests := []struct {
+		method string
+		err    error
+		want   bool
+	}{
+		{http.MethodGet, reset, true},
+		{http.MethodPut, io.ErrUnexpectedEOF, true},
+		{http.MethodDelete, syscall.EPIPE, true},
+		{http.MethodGet, refused, true},
+		{http.MethodPost, refused, true},
+		{http.MethodPost, reset, false},
+		{http.MethodPost, io.EOF, false},
+		{http.MethodGet, errors.New("x509: certificate signed by unknown authority"), false},
+	}
+
+	for _, tt := range tests {
+		if got := isRetryableNetError(context.Background(), tt.method, tt.err); got != tt.want {
+			t.Errorf("isRetryableNetError(%s, %v) = %t, se esperaba %t", tt.method, tt.err, got, tt.want)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if isRetryableNetError(ctx, http.MethodGet, reset) {
+		t.Error("no se debe reintentar con el contexto cancelado")
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		header string
+		want   time.Duration
+		ok     bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"-1", 0, false},
+		{"mañana", 0, false},
+		{time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), 0, true},
+	}
+
+	for _, tt := range tests {
+		res := &http.Response{Header: http.Header{}}
+		if tt.header != "" {
+			res.Header.Set("Retry-After", tt.header)
+		}
+		got, ok := retryAfter(res)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("retryAfter(%q) = %s, %t; se esperaba %s, %t", tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestSendReauthenticatesOnUnauthorized(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+
+	if err := c.SignIn(ctx, "form", testserver.DefaultCategoryID, testserver.DefaultUsername, testserver.DefaultPassword); err != nil {
+		t.Fatalf("SignIn: %v", err)
+	}
+	firstToken := c.Token
+
+	srv.ExpireTokens()
+
+	if _, err := c.GetGroups(ctx); err != nil {
+		t.Fatalf("GetGroups tras caducar la sesión: %v", err)
+	}
+	if c.Token == firstToken {
+		t.Error("el token no se ha renovado")
+	}
+	if got := countRequests(srv, http.MethodPost, "/authentication/login"); got != 2 {
+		t.Errorf("logins = %d, se esperaban 2", got)
+	}
+}
+
+func TestSendReauthenticatesConcurrently(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+
+	if err := c.SignIn(ctx, "form", testserver.DefaultCategoryID, testserver.DefaultUsername, testserver.DefaultPassword); err != nil {
+		t.Fatalf("SignIn: %v", err)
+	}
+	srv.ExpireTokens()
+
+	// Las operaciones en paralelo comparten el estado de la sesión (ejecutar con -race)
+	errs := make(chan error, 5)
+	for i := 0; i < cap(errs); i++ {
+		go func() {
+			_, err := c.GetGroups(ctx)
+			errs <- err
+		}()
+	}
+	for i := 0; i < cap(errs); i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("GetGroups tras caducar la sesión: %v", err)
+		}
+	}
+	if got := countRequests(srv, http.MethodPost, "/authentication/login"); got != 2 {
+		t.Errorf("logins = %d, se esperaban 2", got)
+	}
+}
+
+func TestSendReturnsAPIError(t *testing.T) {
+	c, _ := newTestClient(t)
+
+	_, err := c.GetDesktop(context.Background(), "no-existe")
+	if !IsNotFound(err) {
+		t.Fatalf("error = %v, se esperaba un 404", err)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error = %T, se esperaba *APIError", err)
+	}
+	if apiErr.RequestID == "" {
+		t.Error("el error no incluye el request ID")
+	}
+	if apiErr.Method != http.MethodGet {
+		t.Errorf("Method = %q, se esperaba GET", apiErr.Method)
+	}
+}
@@ -0,0 +1,25 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Role representa un rol de usuario de Isard VDI (admin, manager, advanced o user)
+type Role struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	SortOrder   int64  `json:"sortorder"`
+}
+
+// GetRoles obtiene la lista de roles
+func (c *Client) GetRoles(ctx context.Context) ([]Role, error) {
+	var roles []Role
+	if err := c.do(ctx, http.MethodGet, "/api/v3/admin/roles", nil, &roles); err != nil {
+		return nil, fmt.Errorf("error obteniendo roles: %w", err)
+	}
+
+	return roles, nil
+}
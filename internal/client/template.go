@@ -1,9 +1,10 @@
 package client
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"net/http"
+	"time"
 )
 
 // Template representa la estructura de un template en la API
@@ -21,25 +22,143 @@ type Template struct {
 }
 
 // GetTemplates obtiene la lista de templates disponibles para el usuario
-func (c *Client) GetTemplates() ([]Template, error) {
-	reqURL := fmt.Sprintf("https://%s/api/v3/user/templates", c.HostURL)
-
-	req, err := http.NewRequest("GET", reqURL, nil)
-	if err != nil {
+func (c *Client) GetTemplates(ctx context.Context) ([]Template, error) {
+	var templates []Template
+	if err := c.do(ctx, http.MethodGet, "/api/v3/user/templates", nil, &templates); err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.Token))
+	return templates, nil
+}
+
+// TemplateDetail representa un template con su hardware y sus permisos
+type TemplateDetail struct {
+	ID          string
+	Name        string
+	Description string
+	DesktopID   string // desktop a partir del que se creó el template
+	Enabled     bool
+	Status      string
+	Allowed     map[string]interface{}
+	Hardware    *Hardware // nil si la API no lo informa
+}
+
+// TemplateDerivative representa un dominio (desktop o template) creado a partir de un template
+type TemplateDerivative struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	User string `json:"user"`
+}
 
-	body, err := c.doRequest(req)
+// CreateTemplate crea un template a partir de un desktop existente. El desktop debe estar detenido.
+func (c *Client) CreateTemplate(ctx context.Context, desktopID, name, description string, enabled bool, allowed map[string]interface{}) (string, error) {
+	payload := map[string]interface{}{
+		"desktop_id":  desktopID,
+		"name":        name,
+		"description": description,
+		"enabled":     enabled,
+		"allowed":     allowed,
+	}
+
+	var response struct {
+		ID string `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/v3/template", payload, &response); err != nil {
+		return "", fmt.Errorf("error creando template: %w", err)
+	}
+	if response.ID == "" {
+		return "", fmt.Errorf("error creando template: la API no devolvió el ID")
+	}
+
+	return response.ID, nil
+}
+
+// GetTemplate obtiene un template con su hardware y sus permisos
+func (c *Client) GetTemplate(ctx context.Context, templateID string) (*TemplateDetail, error) {
+	response, err := c.GetTemplateInfo(ctx, templateID)
 	if err != nil {
 		return nil, err
 	}
 
-	var templates []Template
-	if err := json.Unmarshal(body, &templates); err != nil {
-		return nil, err
+	template := &TemplateDetail{
+		ID: templateID,
 	}
 
-	return templates, nil
+	if name, ok := response["name"].(string); ok {
+		template.Name = name
+	}
+	if desc, ok := response["description"].(string); ok {
+		template.Description = desc
+	}
+	if enabled, ok := response["enabled"].(bool); ok {
+		template.Enabled = enabled
+	}
+	if status, ok := response["status"].(string); ok {
+		template.Status = status
+	}
+	if allowed, ok := response["allowed"].(map[string]interface{}); ok {
+		template.Allowed = allowed
+	}
+	if createDict, ok := response["create_dict"].(map[string]interface{}); ok {
+		if origin, ok := createDict["origin"].(string); ok {
+			template.DesktopID = origin
+		}
+	}
+	if raw, ok := response["hardware"].(map[string]interface{}); ok {
+		template.Hardware = parseHardware(raw)
+	}
+
+	return template, nil
+}
+
+// UpdateTemplate modifica un template existente. updateData solo debe contener los campos
+// que cambian (name, description, enabled, allowed).
+func (c *Client) UpdateTemplate(ctx context.Context, templateID string, updateData map[string]interface{}) error {
+	payload := make(map[string]interface{}, len(updateData)+1)
+	for k, v := range updateData {
+		payload[k] = v
+	}
+	payload["id"] = templateID
+
+	if err := c.do(ctx, http.MethodPut, "/api/v3/template/update", payload, nil); err != nil {
+		return fmt.Errorf("error actualizando template: %w", err)
+	}
+
+	return nil
+}
+
+// GetTemplateDerivatives obtiene los desktops y templates creados a partir de un template
+func (c *Client) GetTemplateDerivatives(ctx context.Context, templateID string) ([]TemplateDerivative, error) {
+	var derivatives []TemplateDerivative
+	if err := c.do(ctx, http.MethodGet, "/api/v3/template/derivatives/"+templateID, nil, &derivatives); err != nil {
+		return nil, fmt.Errorf("error obteniendo derivados del template: %w", err)
+	}
+
+	return derivatives, nil
+}
+
+// DeleteTemplate elimina un template. La API elimina también todos sus derivados.
+func (c *Client) DeleteTemplate(ctx context.Context, templateID string) error {
+	err := c.do(ctx, http.MethodDelete, "/api/v3/template/"+templateID, nil, nil)
+
+	// Considerar éxito el 404 (ya no existe)
+	if err != nil && !IsNotFound(err) {
+		return fmt.Errorf("error eliminando template: %w", err)
+	}
+
+	return nil
+}
+
+// WaitForTemplateCreated espera a que el template termine de crearse a partir del disco del desktop
+func (c *Client) WaitForTemplateCreated(ctx context.Context, templateID string, maxWait time.Duration) error {
+	return c.waitForDesktopStatus(ctx, templateID, maxWait, "que se cree el template", func(status string) (bool, error) {
+		switch status {
+		case "Creating", "CreatingTemplate", "CreatingDisk", "unknown":
+			return false, nil
+		case "Failed", "failed":
+			return false, fmt.Errorf("la creación del template ha fallado (estado %s)", status)
+		}
+		return true, nil
+	})
 }
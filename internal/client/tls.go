@@ -0,0 +1,81 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+)
+
+// TLSOptions describe la configuración TLS de la conexión con la API
+type TLSOptions struct {
+	// InsecureSkipVerify desactiva la verificación del certificado del servidor
+	InsecureSkipVerify bool
+	// CACertPEM contiene certificados de CA adicionales (PEM) en los que confiar,
+	// además de los del sistema
+	CACertPEM []byte
+	// ClientCertPEM y ClientKeyPEM configuran un certificado de cliente (mTLS)
+	ClientCertPEM []byte
+	ClientKeyPEM  []byte
+	// MinVersion es la versión mínima de TLS aceptada (tls.VersionTLS12 si es 0)
+	MinVersion uint16
+}
+
+// TLSVersions relaciona los valores admitidos en la configuración con las versiones de TLS
+var TLSVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// NewTLSConfig construye un *tls.Config a partir de las opciones indicadas
+func NewTLSConfig(opts TLSOptions) (*tls.Config, error) {
+	cfg := &tls.Config{
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+		MinVersion:         opts.MinVersion,
+	}
+	if cfg.MinVersion == 0 {
+		cfg.MinVersion = tls.VersionTLS12
+	}
+
+	if len(opts.CACertPEM) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(opts.CACertPEM) {
+			return nil, fmt.Errorf("no se encontró ningún certificado PEM válido en el bundle de CA")
+		}
+		cfg.RootCAs = pool
+	}
+
+	if len(opts.ClientCertPEM) > 0 || len(opts.ClientKeyPEM) > 0 {
+		if len(opts.ClientCertPEM) == 0 || len(opts.ClientKeyPEM) == 0 {
+			return nil, fmt.Errorf("el certificado y la clave de cliente deben indicarse juntos")
+		}
+		cert, err := tls.X509KeyPair(opts.ClientCertPEM, opts.ClientKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("error cargando el certificado de cliente: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// ConfigureTLS aplica las opciones TLS al transporte HTTP del cliente
+func (c *Client) ConfigureTLS(opts TLSOptions) error {
+	cfg, err := NewTLSConfig(opts)
+	if err != nil {
+		return err
+	}
+
+	tr, ok := c.HTTPClient.Transport.(*http.Transport)
+	if !ok {
+		return fmt.Errorf("el transporte HTTP del cliente no admite configuración TLS")
+	}
+	tr.TLSClientConfig = cfg
+
+	return nil
+}
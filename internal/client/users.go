@@ -1,10 +1,8 @@
 package client
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
 	"net/http"
 )
 
@@ -70,113 +68,122 @@ type SearchUsersRequest struct {
 }
 
 // GetUsers obtiene la lista de usuarios con información completa
-func (c *Client) GetUsers() ([]User, error) {
-	reqURL := fmt.Sprintf("https://%s/api/v3/admin/users/management/users", c.HostURL)
-
-	req, err := http.NewRequest("GET", reqURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creando petición GET: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error ejecutando GET: %w", err)
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
-	}
-
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("error obteniendo usuarios (status %d): %s", res.StatusCode, string(body))
-	}
-
+func (c *Client) GetUsers(ctx context.Context) ([]User, error) {
 	var users []User
-	if err := json.Unmarshal(body, &users); err != nil {
-		return nil, fmt.Errorf("error parseando respuesta: %w", err)
+	if err := c.do(ctx, http.MethodGet, "/api/v3/admin/users/management/users", nil, &users); err != nil {
+		return nil, fmt.Errorf("error obteniendo usuarios: %w", err)
 	}
 
 	return users, nil
 }
 
 // SearchUsers busca usuarios por término (nombre)
-func (c *Client) SearchUsers(term string) ([]User, error) {
-	reqURL := fmt.Sprintf("https://%s/api/v3/admin/users/search", c.HostURL)
-
+func (c *Client) SearchUsers(ctx context.Context, term string) ([]User, error) {
 	searchReq := SearchUsersRequest{
 		Term: term,
 	}
 
-	jsonData, err := json.Marshal(searchReq)
-	if err != nil {
-		return nil, fmt.Errorf("error creando JSON de búsqueda: %w", err)
+	var users []User
+	if err := c.do(ctx, http.MethodPost, "/api/v3/admin/users/search", searchReq, &users); err != nil {
+		return nil, fmt.Errorf("error buscando usuarios: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", reqURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("error creando petición POST: %w", err)
+	return users, nil
+}
+
+// GetUser obtiene un usuario específico por ID
+func (c *Client) GetUser(ctx context.Context, userID string) (*User, error) {
+	var user User
+	if err := c.do(ctx, http.MethodGet, "/api/v3/admin/user/"+userID, nil, &user); err != nil {
+		return nil, fmt.Errorf("error obteniendo usuario: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Content-Type", "application/json")
+	return &user, nil
+}
 
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error ejecutando POST: %w", err)
-	}
-	defer res.Body.Close()
+// CreateUserRequest representa los datos de un usuario local nuevo
+type CreateUserRequest struct {
+	Username        string
+	Name            string
+	Email           string
+	Password        string
+	Role            string
+	Category        string
+	Group           string
+	SecondaryGroups []string
+	Active          bool
+}
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
+// payload construye el usuario tal como lo espera la API
+func (u CreateUserRequest) payload() map[string]interface{} {
+	secondaryGroups := u.SecondaryGroups
+	if secondaryGroups == nil {
+		secondaryGroups = []string{}
+	}
+
+	return map[string]interface{}{
+		"provider":         "local",
+		"uid":              u.Username,
+		"username":         u.Username,
+		"name":             u.Name,
+		"email":            u.Email,
+		"password":         u.Password,
+		"role":             u.Role,
+		"category":         u.Category,
+		"group":            u.Group,
+		"secondary_groups": secondaryGroups,
+		"active":           u.Active,
 	}
+}
 
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("error buscando usuarios (status %d): %s", res.StatusCode, string(body))
+// CreateUser crea un usuario local (requiere rol de administrador o manager) y devuelve su ID
+func (c *Client) CreateUser(ctx context.Context, user CreateUserRequest) (string, error) {
+	var response struct {
+		ID string `json:"id"`
 	}
-
-	var users []User
-	if err := json.Unmarshal(body, &users); err != nil {
-		return nil, fmt.Errorf("error parseando respuesta: %w", err)
+	if err := c.do(ctx, http.MethodPost, "/api/v3/admin/user", user.payload(), &response); err != nil {
+		return "", fmt.Errorf("error creando usuario: %w", err)
+	}
+	if response.ID == "" {
+		return "", fmt.Errorf("error creando usuario: la API no devolvió el ID")
 	}
 
-	return users, nil
+	return response.ID, nil
 }
 
-// GetUser obtiene un usuario específico por ID
-func (c *Client) GetUser(userID string) (*User, error) {
-	reqURL := fmt.Sprintf("https://%s/api/v3/admin/user/%s", c.HostURL, userID)
-
-	req, err := http.NewRequest("GET", reqURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creando petición GET: %w", err)
+// UpdateUser modifica un usuario existente. updateData solo debe contener los campos
+// que cambian (name, email, password, role, group, secondary_groups, active).
+func (c *Client) UpdateUser(ctx context.Context, userID string, updateData map[string]interface{}) error {
+	if err := c.do(ctx, http.MethodPut, "/api/v3/admin/user/"+userID, updateData, nil); err != nil {
+		return fmt.Errorf("error actualizando usuario: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.Token)
+	return nil
+}
 
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error ejecutando GET: %w", err)
-	}
-	defer res.Body.Close()
+// DeleteUser elimina un usuario. La API elimina también sus desktops, templates y medios.
+func (c *Client) DeleteUser(ctx context.Context, userID string) error {
+	err := c.do(ctx, http.MethodDelete, "/api/v3/admin/user/"+userID, nil, nil)
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
+	// Considerar éxito el 404 (ya no existe)
+	if err != nil && !IsNotFound(err) {
+		return fmt.Errorf("error eliminando usuario: %w", err)
 	}
 
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("error obteniendo usuario (status %d): %s", res.StatusCode, string(body))
+	return nil
+}
+
+// CreateUsers crea varios usuarios locales en una sola petición al endpoint de alta masiva.
+// La API no devuelve los IDs: hay que volver a listar los usuarios para obtenerlos.
+func (c *Client) CreateUsers(ctx context.Context, users []CreateUserRequest) error {
+	payload := make([]map[string]interface{}, len(users))
+	for i, user := range users {
+		payload[i] = user.payload()
 	}
 
-	var user User
-	if err := json.Unmarshal(body, &user); err != nil {
-		return nil, fmt.Errorf("error parseando respuesta: %w", err)
+	if err := c.do(ctx, http.MethodPost, "/api/v3/admin/users/bulk", map[string]interface{}{"users": payload}, nil); err != nil {
+		return fmt.Errorf("error creando usuarios: %w", err)
 	}
 
-	return &user, nil
+	return nil
 }
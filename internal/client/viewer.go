@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ViewerTypes son los viewers que se pueden habilitar en un desktop (claves de
+// guest_properties.viewers)
+var ViewerTypes = []string{"browser_vnc", "browser_rdp", "file_spice", "file_rdpgw", "file_rdpvpn"}
+
+// Viewer representa los datos de conexión de un viewer de un desktop. Los viewers de
+// navegador devuelven una URL y los de fichero el contenido del fichero a abrir con el
+// cliente correspondiente (.vv para SPICE, .rdp para RDP).
+type Viewer struct {
+	Kind      string `json:"kind"` // "browser" o "file"
+	Protocol  string `json:"protocol"`
+	URL       string `json:"viewer"`
+	Cookie    string `json:"cookie"`
+	Name      string `json:"name"`
+	Extension string `json:"ext"`
+	MimeType  string `json:"mime"`
+	Content   string `json:"content"`
+}
+
+// FileName devuelve el nombre del fichero de un viewer de tipo fichero (vacío en los de navegador)
+func (v *Viewer) FileName() string {
+	if v.Kind != "file" {
+		return ""
+	}
+	name := v.Name
+	if name == "" {
+		name = "isard-" + v.Protocol
+	}
+	return name + "." + v.Extension
+}
+
+// GetDesktopViewer obtiene los datos de conexión de un viewer del desktop. El desktop
+// debe estar arrancado y tener el viewer habilitado; viewer usa el formato de
+// guest_properties (p. ej. "browser_vnc").
+func (c *Client) GetDesktopViewer(ctx context.Context, desktopID, viewer string) (*Viewer, error) {
+	path := fmt.Sprintf("/api/v3/desktop/%s/viewer/%s", desktopID, strings.ReplaceAll(viewer, "_", "-"))
+
+	var out Viewer
+	if err := c.do(ctx, http.MethodGet, path, nil, &out); err != nil {
+		return nil, fmt.Errorf("error obteniendo el viewer %s del desktop: %w", viewer, err)
+	}
+
+	return &out, nil
+}
+
+// jumperURLResponse es la respuesta de los endpoints de enlace directo. jumperurl es
+// false cuando el desktop no tiene enlace directo habilitado.
+type jumperURLResponse struct {
+	JumperURL interface{} `json:"jumperurl"`
+}
+
+// jumperURL construye la URL de enlace directo a partir del token
+func (c *Client) jumperURL(r jumperURLResponse) string {
+	token, ok := r.JumperURL.(string)
+	if !ok || token == "" {
+		return ""
+	}
+	return c.url("/vw/" + token)
+}
+
+// GetJumperURL obtiene la URL de enlace directo del desktop, que permite abrirlo sin
+// iniciar sesión. Devuelve "" si el desktop no tiene enlace directo habilitado.
+func (c *Client) GetJumperURL(ctx context.Context, desktopID string) (string, error) {
+	var out jumperURLResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v3/desktop/jumperurl/"+desktopID, nil, &out); err != nil {
+		return "", fmt.Errorf("error obteniendo el enlace directo del desktop: %w", err)
+	}
+
+	return c.jumperURL(out), nil
+}
+
+// ResetJumperURL habilita el enlace directo del desktop con un token nuevo (el anterior
+// deja de funcionar) y devuelve la URL
+func (c *Client) ResetJumperURL(ctx context.Context, desktopID string) (string, error) {
+	var out jumperURLResponse
+	payload := map[string]interface{}{"disabled": false}
+	if err := c.do(ctx, http.MethodPut, "/api/v3/desktop/jumperurl_reset/"+desktopID, payload, &out); err != nil {
+		return "", fmt.Errorf("error habilitando el enlace directo del desktop: %w", err)
+	}
+
+	url := c.jumperURL(out)
+	if url == "" {
+		return "", fmt.Errorf("la respuesta no contiene el enlace directo: %v", out.JumperURL)
+	}
+	return url, nil
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/tknika/terraform-provider-isardvdi/internal/testserver"
+)
+
+func TestGetDesktopViewer(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+
+	id, err := c.CreatePersistentDesktop(ctx, "viewer", "", testserver.DefaultTemplateID, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreatePersistentDesktop: %v", err)
+	}
+	if err := c.UpdateDesktop(ctx, id, map[string]interface{}{
+		"guest_properties": map[string]interface{}{
+			"viewers": map[string]interface{}{
+				"browser_vnc": map[string]interface{}{"options": nil},
+				"file_spice":  map[string]interface{}{"options": nil},
+			},
+		},
+	}); err != nil {
+		t.Fatalf("UpdateDesktop: %v", err)
+	}
+
+	// Con el desktop detenido la API responde 428
+	srv.SetDesktopStatus(id, "Stopped")
+	if _, err := c.GetDesktopViewer(ctx, id, "browser_vnc"); !IsPreconditionRequired(err) {
+		t.Fatalf("GetDesktopViewer con el desktop detenido = %v, se esperaba un 428", err)
+	}
+
+	srv.SetDesktopStatus(id, "Started")
+	browser, err := c.GetDesktopViewer(ctx, id, "browser_vnc")
+	if err != nil {
+		t.Fatalf("GetDesktopViewer(browser_vnc): %v", err)
+	}
+	// La ruta usa guiones en lugar de los guiones bajos de guest_properties
+	if _, ok := srv.LastRequest(http.MethodGet, "/api/v3/desktop/"+id+"/viewer/browser-vnc"); !ok {
+		t.Error("no se ha pedido el viewer con la ruta browser-vnc")
+	}
+	if browser.Kind != "browser" || browser.Protocol != "vnc" || browser.URL == "" || browser.Cookie == "" {
+		t.Errorf("viewer de navegador = %+v", browser)
+	}
+	if name := browser.FileName(); name != "" {
+		t.Errorf("FileName de un viewer de navegador = %q, se esperaba vacío", name)
+	}
+
+	file, err := c.GetDesktopViewer(ctx, id, "file_spice")
+	if err != nil {
+		t.Fatalf("GetDesktopViewer(file_spice): %v", err)
+	}
+	if file.Kind != "file" || file.MimeType != "application/x-virt-viewer" || !strings.Contains(file.Content, "type=spice") {
+		t.Errorf("viewer de fichero = %+v", file)
+	}
+	if name := file.FileName(); name != "isard-spice.vv" {
+		t.Errorf("FileName = %q, se esperaba isard-spice.vv", name)
+	}
+
+	if _, err := c.GetDesktopViewer(ctx, "no-existe", "browser_vnc"); !IsNotFound(err) {
+		t.Errorf("GetDesktopViewer de un desktop inexistente = %v, se esperaba un 404", err)
+	}
+}
+
+func TestViewerFileName(t *testing.T) {
+	tests := []struct {
+		viewer Viewer
+		want   string
+	}{
+		{Viewer{Kind: "browser", Protocol: "vnc"}, ""},
+		{Viewer{Kind: "file", Protocol: "spice", Name: "escritorio", Extension: "vv"}, "escritorio.vv"},
+		// Sin nombre se usa el del protocolo
+		{Viewer{Kind: "file", Protocol: "rdp", Extension: "rdp"}, "isard-rdp.rdp"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.viewer.FileName(); got != tt.want {
+			t.Errorf("FileName(%+v) = %q, se esperaba %q", tt.viewer, got, tt.want)
+		}
+	}
+}
+
+func TestJumperURL(t *testing.T) {
+	c, _ := newTestClient(t)
+	ctx := context.Background()
+
+	id, err := c.CreatePersistentDesktop(ctx, "enlace", "", testserver.DefaultTemplateID, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreatePersistentDesktop: %v", err)
+	}
+
+	// Sin enlace directo habilitado la API devuelve jumperurl: false
+	url, err := c.GetJumperURL(ctx, id)
+	if err != nil || url != "" {
+		t.Fatalf("GetJumperURL = %q, %v; se esperaba vacío", url, err)
+	}
+
+	reset, err := c.ResetJumperURL(ctx, id)
+	if err != nil {
+		t.Fatalf("ResetJumperURL: %v", err)
+	}
+	if !strings.HasPrefix(reset, c.url("/vw/")) || reset == c.url("/vw/") {
+		t.Errorf("ResetJumperURL = %q, se esperaba una URL /vw/<token>", reset)
+	}
+
+	url, err = c.GetJumperURL(ctx, id)
+	if err != nil || url != reset {
+		t.Errorf("GetJumperURL = %q, %v; se esperaba %q", url, err, reset)
+	}
+
+	if _, err := c.GetJumperURL(ctx, "no-existe"); !IsNotFound(err) {
+		t.Errorf("GetJumperURL de un desktop inexistente = %v, se esperaba un 404", err)
+	}
+}
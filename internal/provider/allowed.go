@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// allowedKeys son los criterios de acceso del objeto allowed de la API
+var allowedKeys = []string{"roles", "categories", "groups", "users"}
+
+// allowedAttributeTypes son los tipos de los atributos del objeto allowed
+var allowedAttributeTypes = map[string]attr.Type{
+	"roles":      types.ListType{ElemType: types.StringType},
+	"categories": types.ListType{ElemType: types.StringType},
+	"groups":     types.ListType{ElemType: types.StringType},
+	"users":      types.ListType{ElemType: types.StringType},
+}
+
+// allowedSchemaAttribute define el atributo allowed de los recursos que se comparten
+// con roles, categorías, grupos o usuarios
+func allowedSchemaAttribute(description string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Optional:            true,
+		MarkdownDescription: description,
+		Attributes: map[string]schema.Attribute{
+			"roles": schema.ListAttribute{
+				ElementType:         types.StringType,
+				Optional:            true,
+				MarkdownDescription: "Lista de roles permitidos. Lista vacía = todos los roles.",
+			},
+			"categories": schema.ListAttribute{
+				ElementType:         types.StringType,
+				Optional:            true,
+				MarkdownDescription: "Lista de IDs de categorías permitidas. Lista vacía = todas las categorías.",
+			},
+			"groups": schema.ListAttribute{
+				ElementType:         types.StringType,
+				Optional:            true,
+				MarkdownDescription: "Lista de IDs de grupos permitidos. Lista vacía = todos los grupos.",
+			},
+			"users": schema.ListAttribute{
+				ElementType:         types.StringType,
+				Optional:            true,
+				MarkdownDescription: "Lista de IDs de usuarios permitidos. Lista vacía = todos los usuarios.",
+			},
+		},
+	}
+}
+
+// allowedToAPI construye el mapa allowed que espera la API. Los criterios sin configurar
+// se envían como false (sin acceso por ese criterio) y las listas vacías dan acceso a todos.
+func allowedToAPI(ctx context.Context, allowed types.Object, diags *diag.Diagnostics) map[string]interface{} {
+	result := make(map[string]interface{}, len(allowedKeys))
+	for _, key := range allowedKeys {
+		result[key] = false
+	}
+	if allowed.IsNull() || allowed.IsUnknown() {
+		return result
+	}
+
+	attrs := allowed.Attributes()
+	for _, key := range allowedKeys {
+		list, ok := attrs[key].(types.List)
+		if !ok || list.IsNull() || list.IsUnknown() {
+			continue
+		}
+		values := listToStrings(ctx, list, diags)
+		if values == nil {
+			values = []string{}
+		}
+		result[key] = values
+	}
+	return result
+}
+
+// allowedFromAPI convierte el mapa allowed de la API en el objeto de Terraform. Los
+// criterios a false o ausentes quedan a null; si ninguno da acceso, el objeto es null,
+// salvo que el valor actual ya sea un objeto sin criterios (allowed = {}).
+func allowedFromAPI(current types.Object, allowed map[string]interface{}) types.Object {
+	attrs := make(map[string]attr.Value, len(allowedKeys))
+	configured := false
+	for _, key := range allowedKeys {
+		items, ok := allowed[key].([]interface{})
+		if !ok {
+			attrs[key] = types.ListNull(types.StringType)
+			continue
+		}
+		values := make([]string, 0, len(items))
+		for _, item := range items {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		attrs[key] = stringListValue(values)
+		configured = true
+	}
+
+	if !configured {
+		if !current.IsNull() && !current.IsUnknown() {
+			return types.ObjectValueMust(allowedAttributeTypes, attrs)
+		}
+		return types.ObjectNull(allowedAttributeTypes)
+	}
+	return types.ObjectValueMust(allowedAttributeTypes, attrs)
+}
@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestAllowedToAPI(t *testing.T) {
+	ctx := context.Background()
+	var diags diag.Diagnostics
+
+	// Sin allowed no se da acceso por ningún criterio
+	got := allowedToAPI(ctx, types.ObjectNull(allowedAttributeTypes), &diags)
+	for _, key := range allowedKeys {
+		if got[key] != false {
+			t.Errorf("allowed[%s] = %v, se esperaba false", key, got[key])
+		}
+	}
+
+	allowed := types.ObjectValueMust(allowedAttributeTypes, map[string]attr.Value{
+		"roles":      stringListValue([]string{}),
+		"categories": types.ListNull(types.StringType),
+		"groups":     types.ListNull(types.StringType),
+		"users":      stringListValue([]string{"u1", "u2"}),
+	})
+	got = allowedToAPI(ctx, allowed, &diags)
+	if diags.HasError() {
+		t.Fatalf("allowedToAPI: %v", diags)
+	}
+	if roles, ok := got["roles"].([]string); !ok || len(roles) != 0 {
+		t.Errorf("roles = %#v, se esperaba una lista vacía (todos los roles)", got["roles"])
+	}
+	if got["categories"] != false || got["groups"] != false {
+		t.Errorf("categories = %v, groups = %v, se esperaba false", got["categories"], got["groups"])
+	}
+	if users, _ := got["users"].([]string); !slices.Equal(users, []string{"u1", "u2"}) {
+		t.Errorf("users = %v", got["users"])
+	}
+}
+
+func TestAllowedFromAPI(t *testing.T) {
+	null := types.ObjectNull(allowedAttributeTypes)
+	none := map[string]interface{}{"roles": false, "categories": false, "groups": false, "users": false}
+
+	if got := allowedFromAPI(null, none); !got.IsNull() {
+		t.Errorf("allowedFromAPI sin criterios = %v, se esperaba null", got)
+	}
+
+	// allowed = {} se conserva aunque la API no devuelva ningún criterio
+	empty := types.ObjectValueMust(allowedAttributeTypes, map[string]attr.Value{
+		"roles":      types.ListNull(types.StringType),
+		"categories": types.ListNull(types.StringType),
+		"groups":     types.ListNull(types.StringType),
+		"users":      types.ListNull(types.StringType),
+	})
+	if got := allowedFromAPI(empty, none); !got.Equal(empty) {
+		t.Errorf("allowedFromAPI(allowed = {}) = %v, se esperaba %v", got, empty)
+	}
+
+	got := allowedFromAPI(null, map[string]interface{}{
+		"roles":      []interface{}{},
+		"categories": false,
+		"users":      []interface{}{"u1"},
+	})
+	attrs := got.Attributes()
+	if !attrs["roles"].Equal(stringListValue([]string{})) {
+		t.Errorf("roles = %v, se esperaba una lista vacía", attrs["roles"])
+	}
+	if !attrs["categories"].IsNull() || !attrs["groups"].IsNull() {
+		t.Errorf("categories = %v, groups = %v, se esperaba null", attrs["categories"], attrs["groups"])
+	}
+	if !attrs["users"].Equal(stringListValue([]string{"u1"})) {
+		t.Errorf("users = %v", attrs["users"])
+	}
+}
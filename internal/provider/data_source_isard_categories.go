@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+)
+
+var _ datasource.DataSource = &categoriesDataSource{}
+
+func NewCategoriesDataSource() datasource.DataSource {
+	return &categoriesDataSource{}
+}
+
+type categoriesDataSource struct {
+	client *client.Client
+}
+
+type categoriesDataSourceModel struct {
+	ID         types.String    `tfsdk:"id"`
+	NameFilter types.String    `tfsdk:"name_filter"`
+	Categories []categoryModel `tfsdk:"categories"`
+}
+
+type categoryModel struct {
+	ID            types.String `tfsdk:"id"`
+	Name          types.String `tfsdk:"name"`
+	Description   types.String `tfsdk:"description"`
+	Frontend      types.Bool   `tfsdk:"frontend"`
+	CustomURLName types.String `tfsdk:"custom_url_name"`
+}
+
+func (d *categoriesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_categories"
+}
+
+func (d *categoriesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches the list of categories from Isard VDI.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Placeholder identifier for the data source.",
+				Computed:    true,
+			},
+			"name_filter": schema.StringAttribute{
+				Description: "Optional filter to match category names (case-insensitive substring match).",
+				Optional:    true,
+			},
+			"categories": schema.ListNestedAttribute{
+				Description: "List of categories available.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Description: "Category ID.",
+							Computed:    true,
+						},
+						"name": schema.StringAttribute{
+							Description: "Category name.",
+							Computed:    true,
+						},
+						"description": schema.StringAttribute{
+							Description: "Category description.",
+							Computed:    true,
+						},
+						"frontend": schema.BoolAttribute{
+							Description: "Whether the category is shown in the login page selector.",
+							Computed:    true,
+						},
+						"custom_url_name": schema.StringAttribute{
+							Description: "Custom login URL name of the category. Empty if not set.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *categoriesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *categoriesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data categoriesDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	categories, err := d.client.GetCategories(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", clientErrorDetail("Unable to read categories", err))
+		return
+	}
+
+	nameFilter := strings.ToLower(data.NameFilter.ValueString())
+
+	data.Categories = []categoryModel{}
+	for _, category := range categories {
+		if nameFilter != "" && !strings.Contains(strings.ToLower(category.Name), nameFilter) {
+			continue
+		}
+		data.Categories = append(data.Categories, categoryModel{
+			ID:            types.StringValue(category.ID),
+			Name:          types.StringValue(category.Name),
+			Description:   types.StringValue(category.Description),
+			Frontend:      types.BoolValue(category.Frontend),
+			CustomURLName: types.StringValue(category.CustomURLName),
+		})
+	}
+
+	data.ID = types.StringValue("categories")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"testing"
+
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/tknika/terraform-provider-isardvdi/internal/testserver"
+)
+
+func TestAccCategoriesDataSource(t *testing.T) {
+	srv := testAccServer(t)
+	srv.AddCategory(map[string]interface{}{
+		"id":              "informatica",
+		"name":            "Informática",
+		"description":     "Departamento de informática",
+		"frontend":        true,
+		"custom_url_name": "info",
+	})
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + `
+data "isardvdi_categories" "all" {}
+
+data "isardvdi_categories" "by_name" {
+  name_filter = "INFORM"
+}
+
+data "isardvdi_categories" "none" {
+  name_filter = "inexistente"
+}
+`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("data.isardvdi_categories.all", "id", "categories"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_categories.all", "categories.#", "2"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_categories.by_name", "categories.#", "1"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_categories.by_name", "categories.0.id", "informatica"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_categories.by_name", "categories.0.description", "Departamento de informática"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_categories.by_name", "categories.0.frontend", "true"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_categories.by_name", "categories.0.custom_url_name", "info"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_categories.none", "categories.#", "0"),
+					tfresource.TestCheckTypeSetElemNestedAttrs("data.isardvdi_categories.all", "categories.*", map[string]string{
+						"id":   testserver.DefaultCategoryID,
+						"name": "Default",
+					}),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,181 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+)
+
+var _ datasource.DataSource = &deploymentDataSource{}
+
+func NewDeploymentDataSource() datasource.DataSource {
+	return &deploymentDataSource{}
+}
+
+type deploymentDataSource struct {
+	client *client.Client
+}
+
+type deploymentDataSourceModel struct {
+	ID               types.String  `tfsdk:"id"`
+	Name             types.String  `tfsdk:"name"`
+	Description      types.String  `tfsdk:"description"`
+	DesktopName      types.String  `tfsdk:"desktop_name"`
+	TemplateID       types.String  `tfsdk:"template_id"`
+	Visible          types.Bool    `tfsdk:"visible"`
+	Allowed          types.Object  `tfsdk:"allowed"`
+	VCPUs            types.Int64   `tfsdk:"vcpus"`
+	Memory           types.Float64 `tfsdk:"memory"`
+	Interfaces       types.List    `tfsdk:"interfaces"`
+	ISOs             types.List    `tfsdk:"isos"`
+	Floppies         types.List    `tfsdk:"floppies"`
+	BootOrder        types.List    `tfsdk:"boot_order"`
+	Viewers          types.List    `tfsdk:"viewers"`
+	UserPermissions  types.List    `tfsdk:"user_permissions"`
+	TotalDesktops    types.Int64   `tfsdk:"total_desktops"`
+	VisibleDesktops  types.Int64   `tfsdk:"visible_desktops"`
+	StartedDesktops  types.Int64   `tfsdk:"started_desktops"`
+	CreatingDesktops types.Int64   `tfsdk:"creating_desktops"`
+}
+
+func (d *deploymentDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_deployment"
+}
+
+func (d *deploymentDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	computedList := func(description string) schema.ListAttribute {
+		return schema.ListAttribute{Description: description, ElementType: types.StringType, Computed: true}
+	}
+	computedInt := func(description string) schema.Int64Attribute {
+		return schema.Int64Attribute{Description: description, Computed: true}
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Fetches a deployment from Isard VDI, with its settings and desktop counters.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Deployment ID.",
+				Required:    true,
+			},
+			"name": schema.StringAttribute{
+				Description: "Deployment name.",
+				Computed:    true,
+			},
+			"description": schema.StringAttribute{
+				Description: "Deployment description.",
+				Computed:    true,
+			},
+			"desktop_name": schema.StringAttribute{
+				Description: "Name given to the desktops of the deployment.",
+				Computed:    true,
+			},
+			"template_id": schema.StringAttribute{
+				Description: "ID of the template the desktops were created from.",
+				Computed:    true,
+			},
+			"visible": schema.BoolAttribute{
+				Description: "Whether the desktops are visible to their owners.",
+				Computed:    true,
+			},
+			"allowed": schema.SingleNestedAttribute{
+				Description: "Roles, categories, groups and users the deployment was created for. Null criteria grant no access; empty lists grant access to all.",
+				Computed:    true,
+				Attributes: map[string]schema.Attribute{
+					"roles":      computedList("Allowed roles."),
+					"categories": computedList("Allowed category IDs."),
+					"groups":     computedList("Allowed group IDs."),
+					"users":      computedList("Allowed user IDs."),
+				},
+			},
+			"vcpus": computedInt("Number of virtual CPUs of each desktop."),
+			"memory": schema.Float64Attribute{
+				Description: "Memory of each desktop in GB.",
+				Computed:    true,
+			},
+			"interfaces":        computedList("Network interface IDs of the desktops."),
+			"isos":              computedList("ISO media IDs attached to the desktops."),
+			"floppies":          computedList("Floppy media IDs attached to the desktops."),
+			"boot_order":        computedList("Boot device order."),
+			"viewers":           computedList("Enabled viewers, sorted by name."),
+			"user_permissions":  computedList("Permissions granted to the desktop owners."),
+			"total_desktops":    computedInt("Number of desktops in the deployment."),
+			"visible_desktops":  computedInt("Number of desktops visible to their owners."),
+			"started_desktops":  computedInt("Number of started desktops."),
+			"creating_desktops": computedInt("Number of desktops still being created."),
+		},
+	}
+}
+
+func (d *deploymentDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *deploymentDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data deploymentDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := data.ID.ValueString()
+	deployment, err := d.client.GetDeployment(ctx, id)
+	if client.IsNotFound(err) {
+		resp.Diagnostics.AddError("Deployment no encontrado", "No existe ningún deployment con ID "+id+".")
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", clientErrorDetail("Unable to read deployment "+id, err))
+		return
+	}
+
+	// GET /deployment/{id} no incluye el hardware: se lee de deployment/info
+	settings, err := d.client.GetDeploymentSettings(ctx, id)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", clientErrorDetail("Unable to read deployment settings "+id, err))
+		return
+	}
+
+	data.Name = types.StringValue(deployment.Name)
+	data.Description = types.StringValue(deployment.Description)
+	data.DesktopName = types.StringValue(deployment.DesktopName)
+	data.TemplateID = types.StringValue(deployment.TemplateID)
+	data.Visible = types.BoolValue(deployment.Visible)
+	data.Allowed = allowedFromAPI(types.ObjectNull(allowedAttributeTypes), deployment.Allowed)
+	data.TotalDesktops = types.Int64Value(int64(deployment.TotalDesktops))
+	data.VisibleDesktops = types.Int64Value(int64(deployment.VisibleDesktops))
+	data.StartedDesktops = types.Int64Value(int64(deployment.StartedDesktops))
+	data.CreatingDesktops = types.Int64Value(int64(deployment.CreatingDesktops))
+
+	hardware := settings.Hardware
+	if hardware == nil {
+		hardware = &client.Hardware{}
+	}
+	data.VCPUs = types.Int64Value(hardware.VCPUs)
+	data.Memory = types.Float64Value(hardware.Memory)
+	data.Interfaces = stringListValue(hardware.Interfaces)
+	data.ISOs = stringListValue(hardware.ISOs)
+	data.Floppies = stringListValue(hardware.Floppies)
+	data.BootOrder = stringListValue(hardware.BootOrder)
+	data.Viewers = stringListValue(settings.Viewers)
+	data.UserPermissions = stringListValue(settings.UserPermissions)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
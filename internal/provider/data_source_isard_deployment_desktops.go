@@ -0,0 +1,189 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+)
+
+var _ datasource.DataSource = &deploymentDesktopsDataSource{}
+
+func NewDeploymentDesktopsDataSource() datasource.DataSource {
+	return &deploymentDesktopsDataSource{}
+}
+
+type deploymentDesktopsDataSource struct {
+	client *client.Client
+}
+
+type deploymentDesktopsDataSourceModel struct {
+	ID           types.String             `tfsdk:"id"`
+	DeploymentID types.String             `tfsdk:"deployment_id"`
+	Status       types.String             `tfsdk:"status"`
+	Desktops     []deploymentDesktopModel `tfsdk:"desktops"`
+}
+
+type deploymentDesktopModel struct {
+	ID              types.String `tfsdk:"id"`
+	Name            types.String `tfsdk:"name"`
+	UserID          types.String `tfsdk:"user_id"`
+	Username        types.String `tfsdk:"username"`
+	CategoryName    types.String `tfsdk:"category_name"`
+	GroupName       types.String `tfsdk:"group_name"`
+	Status          types.String `tfsdk:"status"`
+	IP              types.String `tfsdk:"ip"`
+	Viewers         types.List   `tfsdk:"viewers"`
+	ViewerAvailable types.Bool   `tfsdk:"viewer_available"`
+	Visible         types.Bool   `tfsdk:"visible"`
+}
+
+func (d *deploymentDesktopsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_deployment_desktops"
+}
+
+func (d *deploymentDesktopsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches every desktop of a deployment from Isard VDI, with its owner, status, IP and viewer availability.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the data source (the deployment ID).",
+				Computed:    true,
+			},
+			"deployment_id": schema.StringAttribute{
+				Description: "Deployment ID.",
+				Required:    true,
+			},
+			"status": schema.StringAttribute{
+				Description: "Optional filter to match desktops by status (case-insensitive).",
+				Optional:    true,
+			},
+			"desktops": schema.ListNestedAttribute{
+				Description: "Desktops of the deployment, sorted by owner username.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Description: "Desktop ID.",
+							Computed:    true,
+						},
+						"name": schema.StringAttribute{
+							Description: "Desktop name.",
+							Computed:    true,
+						},
+						"user_id": schema.StringAttribute{
+							Description: "ID of the user who owns the desktop.",
+							Computed:    true,
+						},
+						"username": schema.StringAttribute{
+							Description: "Username of the user who owns the desktop.",
+							Computed:    true,
+						},
+						"category_name": schema.StringAttribute{
+							Description: "Category name of the owner.",
+							Computed:    true,
+						},
+						"group_name": schema.StringAttribute{
+							Description: "Group name of the owner.",
+							Computed:    true,
+						},
+						"status": schema.StringAttribute{
+							Description: "Current desktop status (Started, Stopped, Failed...).",
+							Computed:    true,
+						},
+						"ip": schema.StringAttribute{
+							Description: "Guest IP address reported by the desktop. Empty if unknown.",
+							Computed:    true,
+						},
+						"viewers": schema.ListAttribute{
+							Description: "Enabled viewers, sorted by name.",
+							ElementType: types.StringType,
+							Computed:    true,
+						},
+						"viewer_available": schema.BoolAttribute{
+							Description: "Whether the owner can connect right now: the desktop is started and has at least one viewer.",
+							Computed:    true,
+						},
+						"visible": schema.BoolAttribute{
+							Description: "Whether the desktop is visible to its owner.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *deploymentDesktopsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *deploymentDesktopsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data deploymentDesktopsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := data.DeploymentID.ValueString()
+	deployment, err := d.client.GetDeployment(ctx, id)
+	if client.IsNotFound(err) {
+		resp.Diagnostics.AddError("Deployment no encontrado", "No existe ningún deployment con ID "+id+".")
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", clientErrorDetail("Unable to read deployment "+id, err))
+		return
+	}
+
+	// Ordenar por propietario para que la lista sea estable entre lecturas
+	desktops := slices.Clone(deployment.Desktops)
+	slices.SortFunc(desktops, func(a, b client.DeploymentDesktop) int {
+		return strings.Compare(a.Username+"\x00"+a.ID, b.Username+"\x00"+b.ID)
+	})
+
+	statusFilter := data.Status.ValueString()
+	data.Desktops = []deploymentDesktopModel{}
+	for _, desktop := range desktops {
+		if statusFilter != "" && !strings.EqualFold(desktop.Status, statusFilter) {
+			continue
+		}
+		data.Desktops = append(data.Desktops, deploymentDesktopModel{
+			ID:              types.StringValue(desktop.ID),
+			Name:            types.StringValue(desktop.Name),
+			UserID:          types.StringValue(desktop.User),
+			Username:        types.StringValue(desktop.Username),
+			CategoryName:    types.StringValue(desktop.CategoryName),
+			GroupName:       types.StringValue(desktop.GroupName),
+			Status:          types.StringValue(desktop.Status),
+			IP:              types.StringValue(desktop.IP),
+			Viewers:         stringListValue(slices.Sorted(slices.Values(desktop.Viewers))),
+			ViewerAvailable: types.BoolValue(desktop.ViewerAvailable()),
+			Visible:         types.BoolValue(desktop.Visible),
+		})
+	}
+
+	data.ID = types.StringValue(id)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
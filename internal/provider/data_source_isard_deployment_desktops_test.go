@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"testing"
+
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/tknika/terraform-provider-isardvdi/internal/testserver"
+)
+
+func TestAccDeploymentDesktopsDataSource(t *testing.T) {
+	srv := testAccServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + testAccDeploymentConfig("tf-acc-deployment", `
+  viewers       = ["browser_vnc"]
+  desired_state = "started"
+`) + `
+data "isardvdi_deployment_desktops" "all" {
+  deployment_id = isardvdi_deployment.test.id
+}
+
+data "isardvdi_deployment_desktops" "started" {
+  deployment_id = isardvdi_deployment.test.id
+  status        = "STARTED"
+}
+
+data "isardvdi_deployment_desktops" "stopped" {
+  deployment_id = isardvdi_deployment.test.id
+  status        = "stopped"
+}
+`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttrPair("data.isardvdi_deployment_desktops.all", "id", "isardvdi_deployment.test", "id"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_deployment_desktops.all", "desktops.#", "1"),
+					tfresource.TestCheckResourceAttrSet("data.isardvdi_deployment_desktops.all", "desktops.0.id"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_deployment_desktops.all", "desktops.0.name", "Escritorio"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_deployment_desktops.all", "desktops.0.user_id", testserver.DefaultUserID),
+					tfresource.TestCheckResourceAttr("data.isardvdi_deployment_desktops.all", "desktops.0.username", testserver.DefaultUsername),
+					tfresource.TestCheckResourceAttr("data.isardvdi_deployment_desktops.all", "desktops.0.category_name", "Default"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_deployment_desktops.all", "desktops.0.group_name", "Default"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_deployment_desktops.all", "desktops.0.status", "Started"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_deployment_desktops.all", "desktops.0.viewers.0", "browser_vnc"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_deployment_desktops.all", "desktops.0.viewer_available", "true"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_deployment_desktops.started", "desktops.#", "1"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_deployment_desktops.stopped", "desktops.#", "0"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/tknika/terraform-provider-isardvdi/internal/testserver"
+)
+
+func TestAccDeploymentDataSource(t *testing.T) {
+	srv := testAccServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + testAccDeploymentConfig("tf-acc-deployment", `
+  description = "Laboratorio"
+  memory      = 1.5
+  isos        = ["iso-ubuntu"]
+  viewers     = ["browser_vnc"]
+`) + `
+data "isardvdi_deployment" "test" {
+  id = isardvdi_deployment.test.id
+}
+`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttrPair("data.isardvdi_deployment.test", "id", "isardvdi_deployment.test", "id"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_deployment.test", "name", "tf-acc-deployment"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_deployment.test", "description", "Laboratorio"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_deployment.test", "desktop_name", "Escritorio"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_deployment.test", "template_id", testserver.DefaultTemplateID),
+					tfresource.TestCheckResourceAttr("data.isardvdi_deployment.test", "allowed.users.0", testserver.DefaultUserID),
+					tfresource.TestCheckResourceAttr("data.isardvdi_deployment.test", "memory", "1.5"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_deployment.test", "isos.0", "iso-ubuntu"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_deployment.test", "viewers.0", "browser_vnc"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_deployment.test", "total_desktops", "1"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_deployment.test", "creating_desktops", "0"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+data "isardvdi_deployment" "test" {
+  id = "no-existe"
+}
+`,
+				ExpectError: regexp.MustCompile(`Deployment no encontrado`),
+			},
+		},
+	})
+}
@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+)
+
+var (
+	_ datasource.DataSource                     = &desktopDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &desktopDataSource{}
+)
+
+func NewDesktopDataSource() datasource.DataSource {
+	return &desktopDataSource{}
+}
+
+type desktopDataSource struct {
+	client *client.Client
+}
+
+func (d *desktopDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_desktop"
+}
+
+func (d *desktopDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches a single desktop from Isard VDI, by ID or by name and owner.",
+		Attributes:  desktopAttributes("id", "name", "user_id"),
+	}
+}
+
+// ConfigValidators exige buscar el desktop por id o por name (con user_id opcional).
+func (d *desktopDataSource) ConfigValidators(_ context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(path.MatchRoot("id"), path.MatchRoot("name")),
+		datasourcevalidator.Conflicting(path.MatchRoot("id"), path.MatchRoot("user_id")),
+	}
+}
+
+func (d *desktopDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *desktopDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data desktopModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var desktop *client.Desktop
+	if id := data.ID.ValueString(); id != "" {
+		found, err := d.client.GetDesktop(ctx, id)
+		if client.IsNotFound(err) {
+			resp.Diagnostics.AddError("Desktop no encontrado", "No existe ningún desktop con ID "+id+".")
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", clientErrorDetail("Unable to read desktop "+id, err))
+			return
+		}
+		desktop = found
+	} else {
+		desktops, err := d.client.ListDesktops(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", clientErrorDetail("Unable to read desktops", err))
+			return
+		}
+		found, err := findDesktopByName(desktops, data.Name.ValueString(), data.UserID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Desktop no encontrado", err.Error())
+			return
+		}
+		desktop = found
+	}
+
+	data = newDesktopModel(*desktop)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// findDesktopByName busca el desktop con el nombre exacto indicado. Si hay varios desktops
+// con el mismo nombre (de distintos usuarios) es necesario indicar el propietario.
+func findDesktopByName(desktops []client.Desktop, name, userID string) (*client.Desktop, error) {
+	var matches []client.Desktop
+	for _, desktop := range desktops {
+		if desktop.Name == name && (userID == "" || desktop.User == userID) {
+			matches = append(matches, desktop)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		if userID != "" {
+			return nil, fmt.Errorf("no existe ningún desktop llamado %q del usuario %s", name, userID)
+		}
+		return nil, fmt.Errorf("no existe ningún desktop llamado %q", name)
+	case 1:
+		return &matches[0], nil
+	}
+
+	owners := make([]string, len(matches))
+	for i, desktop := range matches {
+		owners[i] = desktop.User
+	}
+	return nil, fmt.Errorf("hay %d desktops llamados %q (usuarios: %s); indica user_id para elegir uno", len(matches), name, strings.Join(owners, ", "))
+}
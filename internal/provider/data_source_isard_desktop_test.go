@@ -0,0 +1,173 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+	"github.com/tknika/terraform-provider-isardvdi/internal/testserver"
+)
+
+func TestAccDesktopDataSource(t *testing.T) {
+	srv := testAccServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + testAccDesktopDataSourceConfig + fmt.Sprintf(`
+data "isardvdi_desktop" "by_id" {
+  id = isardvdi_vm.test.id
+}
+
+data "isardvdi_desktop" "by_name" {
+  name    = isardvdi_vm.test.name
+  user_id = %q
+}
+`, testserver.DefaultUserID),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttrPair("data.isardvdi_desktop.by_id", "id", "isardvdi_vm.test", "id"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_desktop.by_id", "name", "tf-acc-desktop"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_desktop.by_id", "description", "Desktop de prueba"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_desktop.by_id", "template_id", testserver.DefaultTemplateID),
+					tfresource.TestCheckResourceAttr("data.isardvdi_desktop.by_id", "user_id", testserver.DefaultUserID),
+					tfresource.TestCheckResourceAttr("data.isardvdi_desktop.by_id", "username", testserver.DefaultUsername),
+					tfresource.TestCheckResourceAttr("data.isardvdi_desktop.by_id", "group", testserver.DefaultGroupID),
+					tfresource.TestCheckResourceAttr("data.isardvdi_desktop.by_id", "tag", ""),
+					tfresource.TestCheckResourceAttr("data.isardvdi_desktop.by_id", "memory", "4"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_desktop.by_id", "interfaces.0", "default"),
+					tfresource.TestCheckResourceAttrPair("data.isardvdi_desktop.by_name", "id", "isardvdi_vm.test", "id"),
+					tfresource.TestCheckResourceAttrPair("data.isardvdi_desktop.by_name", "vcpus", "data.isardvdi_desktop.by_id", "vcpus"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(srv) + testAccDesktopDataSourceConfig + `
+data "isardvdi_desktop" "test" {
+  name = "no-existe"
+}
+`,
+				ExpectError: regexp.MustCompile(`Desktop no encontrado`),
+			},
+		},
+	})
+}
+
+func TestAccDesktopDataSourceValidation(t *testing.T) {
+	srv := testAccServer(t)
+
+	tests := []struct {
+		name, lookup, want string
+	}{
+		{"id y name", "id = \"x\"\nname = \"x\"", `Invalid Attribute Combination`},
+		{"ninguno", "user_id = \"x\"", `Missing Attribute Configuration`},
+		{"id y user_id", "id = \"x\"\nuser_id = \"x\"", `Invalid Attribute Combination`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tfresource.UnitTest(t, tfresource.TestCase{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []tfresource.TestStep{
+					{
+						Config:      testAccProviderConfig(srv) + "data \"isardvdi_desktop\" \"test\" {\n" + tt.lookup + "\n}\n",
+						PlanOnly:    true,
+						ExpectError: regexp.MustCompile(tt.want),
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestDesktopDataSourceConfigValidators(t *testing.T) {
+	ctx := context.Background()
+	d := &desktopDataSource{}
+
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+
+	tests := []struct {
+		attrs     map[string]interface{}
+		wantError bool
+	}{
+		{map[string]interface{}{"id": "x"}, false},
+		{map[string]interface{}{"name": "x", "user_id": "x"}, false},
+		{map[string]interface{}{"id": types.StringUnknown(), "name": "x"}, false},
+		{map[string]interface{}{"id": "x", "name": "x"}, true},
+		{map[string]interface{}{"user_id": "x"}, true},
+		{map[string]interface{}{"id": "x", "user_id": "x"}, true},
+	}
+
+	for _, tt := range tests {
+		// El estado sirve para construir la configuración con los atributos indicados
+		raw, _ := types.ObjectNull(schemaResp.Schema.Type().(types.ObjectType).AttrTypes).ToTerraformValue(ctx)
+		state := tfsdk.State{Schema: schemaResp.Schema, Raw: raw}
+		for name, value := range tt.attrs {
+			if diags := state.SetAttribute(ctx, path.Root(name), value); diags.HasError() {
+				t.Fatalf("SetAttribute(%s): %v", name, diags)
+			}
+		}
+		req := datasource.ValidateConfigRequest{Config: tfsdk.Config{Schema: state.Schema, Raw: state.Raw}}
+
+		var diags diag.Diagnostics
+		for _, v := range d.ConfigValidators(ctx) {
+			var resp datasource.ValidateConfigResponse
+			v.ValidateDataSource(ctx, req, &resp)
+			diags.Append(resp.Diagnostics...)
+		}
+		if diags.HasError() != tt.wantError {
+			t.Errorf("ConfigValidators(%v) = %v, se esperaba error: %t", tt.attrs, diags, tt.wantError)
+		}
+	}
+}
+
+func TestFindDesktopByName(t *testing.T) {
+	desktops := []client.Desktop{
+		{ID: "1", Name: "ubuntu", User: "ana"},
+		{ID: "2", Name: "ubuntu", User: "beto"},
+		{ID: "3", Name: "windows", User: "ana"},
+	}
+
+	tests := []struct {
+		name, userID string
+		wantID       string
+		wantErr      string
+	}{
+		{"windows", "", "3", ""},
+		{"ubuntu", "beto", "2", ""},
+		{"ubuntu", "", "", "indica user_id"},
+		{"windows", "beto", "", "del usuario beto"},
+		{"debian", "", "", "no existe ningún desktop"},
+	}
+
+	for _, tt := range tests {
+		desktop, err := findDesktopByName(desktops, tt.name, tt.userID)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("findDesktopByName(%s, %s) = %v, se esperaba un error con %q", tt.name, tt.userID, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || desktop.ID != tt.wantID {
+			t.Errorf("findDesktopByName(%s, %s) = %v, %v; se esperaba el desktop %s", tt.name, tt.userID, desktop, err, tt.wantID)
+		}
+	}
+}
+
+// testAccDesktopDataSourceConfig es el desktop que buscan los tests de los data sources
+var testAccDesktopDataSourceConfig = fmt.Sprintf(`
+resource "isardvdi_vm" "test" {
+  name        = "tf-acc-desktop"
+  description = "Desktop de prueba"
+  template_id = %q
+}
+`, testserver.DefaultTemplateID)
@@ -0,0 +1,255 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+)
+
+var _ datasource.DataSource = &desktopsDataSource{}
+
+func NewDesktopsDataSource() datasource.DataSource {
+	return &desktopsDataSource{}
+}
+
+type desktopsDataSource struct {
+	client *client.Client
+}
+
+type desktopsDataSourceModel struct {
+	ID         types.String   `tfsdk:"id"`
+	NameFilter types.String   `tfsdk:"name_filter"`
+	UserID     types.String   `tfsdk:"user_id"`
+	GroupID    types.String   `tfsdk:"group_id"`
+	CategoryID types.String   `tfsdk:"category_id"`
+	TemplateID types.String   `tfsdk:"template_id"`
+	Status     types.String   `tfsdk:"status"`
+	Tags       []types.String `tfsdk:"tags"`
+	Desktops   []desktopModel `tfsdk:"desktops"`
+}
+
+// desktopModel es la representación de un desktop compartida por los data sources
+// isardvdi_desktop e isardvdi_desktops
+type desktopModel struct {
+	ID          types.String  `tfsdk:"id"`
+	Name        types.String  `tfsdk:"name"`
+	Description types.String  `tfsdk:"description"`
+	TemplateID  types.String  `tfsdk:"template_id"`
+	Status      types.String  `tfsdk:"status"`
+	UserID      types.String  `tfsdk:"user_id"`
+	Username    types.String  `tfsdk:"username"`
+	Category    types.String  `tfsdk:"category"`
+	Group       types.String  `tfsdk:"group"`
+	Tag         types.String  `tfsdk:"tag"`
+	VCPUs       types.Int64   `tfsdk:"vcpus"`
+	Memory      types.Float64 `tfsdk:"memory"`
+	Interfaces  types.List    `tfsdk:"interfaces"`
+	ISOs        types.List    `tfsdk:"isos"`
+	Floppies    types.List    `tfsdk:"floppies"`
+	BootOrder   types.List    `tfsdk:"boot_order"`
+	Viewers     types.List    `tfsdk:"viewers"`
+	IPs         types.List    `tfsdk:"ips"`
+}
+
+// newDesktopModel convierte un desktop de la API al modelo de los data sources
+func newDesktopModel(desktop client.Desktop) desktopModel {
+	return desktopModel{
+		ID:          types.StringValue(desktop.ID),
+		Name:        types.StringValue(desktop.Name),
+		Description: types.StringValue(desktop.Description),
+		TemplateID:  types.StringValue(desktop.TemplateID),
+		Status:      types.StringValue(desktop.Status),
+		UserID:      types.StringValue(desktop.User),
+		Username:    types.StringValue(desktop.Username),
+		Category:    types.StringValue(desktop.Category),
+		Group:       types.StringValue(desktop.Group),
+		Tag:         types.StringValue(desktop.Tag),
+		VCPUs:       types.Int64Value(desktop.VCPUs),
+		Memory:      types.Float64Value(desktop.Memory),
+		Interfaces:  stringListValue(desktop.Interfaces),
+		ISOs:        stringListValue(desktop.ISOs),
+		Floppies:    stringListValue(desktop.Floppies),
+		BootOrder:   stringListValue(desktop.BootOrder),
+		Viewers:     stringListValue(desktop.Viewers),
+		IPs:         stringListValue(desktop.IPs),
+	}
+}
+
+// desktopAttributes devuelve el esquema de los atributos de un desktop. Todos son
+// calculados salvo los indicados en lookup, que además son opcionales (para buscar el desktop).
+func desktopAttributes(lookup ...string) map[string]schema.Attribute {
+	str := func(description string) schema.StringAttribute {
+		return schema.StringAttribute{Description: description, Computed: true}
+	}
+	list := func(description string) schema.ListAttribute {
+		return schema.ListAttribute{Description: description, ElementType: types.StringType, Computed: true}
+	}
+
+	attributes := map[string]schema.Attribute{
+		"id":          str("Desktop ID."),
+		"name":        str("Desktop name."),
+		"description": str("Desktop description."),
+		"template_id": str("ID of the template the desktop was created from."),
+		"status":      str("Current desktop status (Started, Stopped, Failed...)."),
+		"user_id":     str("ID of the user who owns the desktop."),
+		"username":    str("Username of the user who owns the desktop."),
+		"category":    str("Category ID of the owner."),
+		"group":       str("Group ID of the owner."),
+		"tag":         str("ID of the deployment the desktop belongs to. Empty for desktops not created by a deployment."),
+		"vcpus": schema.Int64Attribute{
+			Description: "Number of virtual CPUs.",
+			Computed:    true,
+		},
+		"memory": schema.Float64Attribute{
+			Description: "Memory in GB.",
+			Computed:    true,
+		},
+		"interfaces": list("Network interface IDs attached to the desktop."),
+		"isos":       list("ISO media IDs attached to the desktop."),
+		"floppies":   list("Floppy media IDs attached to the desktop."),
+		"boot_order": list("Boot device order."),
+		"viewers":    list("Enabled viewers, sorted by name."),
+		"ips":        list("IP addresses reported by the desktop (guest IP and interface addresses)."),
+	}
+
+	for _, name := range lookup {
+		attr := attributes[name].(schema.StringAttribute)
+		attr.Optional = true
+		attributes[name] = attr
+	}
+
+	return attributes
+}
+
+func (d *desktopsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_desktops"
+}
+
+func (d *desktopsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches the list of desktops from Isard VDI. Allows filtering by name, user, group, category, template, status and deployment tag.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Placeholder identifier for the data source.",
+				Computed:    true,
+			},
+			"name_filter": schema.StringAttribute{
+				Description: "Optional filter to match desktop names (case-insensitive substring match).",
+				Optional:    true,
+			},
+			"user_id": schema.StringAttribute{
+				Description: "Optional filter to match desktops by owner user ID.",
+				Optional:    true,
+			},
+			"group_id": schema.StringAttribute{
+				Description: "Optional filter to match desktops by owner group ID.",
+				Optional:    true,
+			},
+			"category_id": schema.StringAttribute{
+				Description: "Optional filter to match desktops by owner category ID.",
+				Optional:    true,
+			},
+			"template_id": schema.StringAttribute{
+				Description: "Optional filter to match desktops created from a template.",
+				Optional:    true,
+			},
+			"status": schema.StringAttribute{
+				Description: "Optional filter to match desktops by status (case-insensitive).",
+				Optional:    true,
+			},
+			"tags": schema.ListAttribute{
+				Description: "Optional filter to match desktops belonging to any of the given deployment IDs.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"desktops": schema.ListNestedAttribute{
+				Description: "List of desktops matching the filters.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: desktopAttributes(),
+				},
+			},
+		},
+	}
+}
+
+func (d *desktopsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *desktopsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data desktopsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	desktops, err := d.client.ListDesktops(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", clientErrorDetail("Unable to read desktops", err))
+		return
+	}
+
+	tags := make([]string, 0, len(data.Tags))
+	for _, tag := range data.Tags {
+		tags = append(tags, tag.ValueString())
+	}
+
+	data.Desktops = []desktopModel{}
+	for _, desktop := range desktops {
+		if !matchesDesktopFilters(desktop, data, tags) {
+			continue
+		}
+		data.Desktops = append(data.Desktops, newDesktopModel(desktop))
+	}
+
+	data.ID = types.StringValue("desktops")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// matchesDesktopFilters indica si el desktop cumple todos los filtros configurados
+func matchesDesktopFilters(desktop client.Desktop, data desktopsDataSourceModel, tags []string) bool {
+	if f := data.NameFilter.ValueString(); f != "" && !strings.Contains(strings.ToLower(desktop.Name), strings.ToLower(f)) {
+		return false
+	}
+	if f := data.UserID.ValueString(); f != "" && desktop.User != f {
+		return false
+	}
+	if f := data.GroupID.ValueString(); f != "" && desktop.Group != f {
+		return false
+	}
+	if f := data.CategoryID.ValueString(); f != "" && desktop.Category != f {
+		return false
+	}
+	if f := data.TemplateID.ValueString(); f != "" && desktop.TemplateID != f {
+		return false
+	}
+	if f := data.Status.ValueString(); f != "" && !strings.EqualFold(desktop.Status, f) {
+		return false
+	}
+	if len(tags) > 0 && !slices.Contains(tags, desktop.Tag) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+	"github.com/tknika/terraform-provider-isardvdi/internal/testserver"
+)
+
+func TestAccDesktopsDataSource(t *testing.T) {
+	srv := testAccServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "isardvdi_vm" "alumno" {
+  name        = "tf-acc-alumno"
+  template_id = %[1]q
+}
+
+resource "isardvdi_vm" "profesor" {
+  name        = "tf-acc-profesor"
+  template_id = %[1]q
+}
+
+data "isardvdi_desktops" "all" {
+  depends_on = [isardvdi_vm.alumno, isardvdi_vm.profesor]
+}
+
+data "isardvdi_desktops" "by_name" {
+  name_filter = "ALUMNO"
+  depends_on  = [isardvdi_vm.alumno, isardvdi_vm.profesor]
+}
+
+data "isardvdi_desktops" "by_owner" {
+  user_id     = %[2]q
+  group_id    = %[3]q
+  category_id = %[4]q
+  template_id = %[1]q
+  status      = "stopped"
+  depends_on  = [isardvdi_vm.alumno, isardvdi_vm.profesor]
+}
+
+data "isardvdi_desktops" "by_tag" {
+  tags       = ["deployment-inexistente"]
+  depends_on = [isardvdi_vm.alumno, isardvdi_vm.profesor]
+}
+`, testserver.DefaultTemplateID, testserver.DefaultUserID, testserver.DefaultGroupID, testserver.DefaultCategoryID),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("data.isardvdi_desktops.all", "id", "desktops"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_desktops.all", "desktops.#", "2"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_desktops.by_name", "desktops.#", "1"),
+					tfresource.TestCheckResourceAttrPair("data.isardvdi_desktops.by_name", "desktops.0.id", "isardvdi_vm.alumno", "id"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_desktops.by_name", "desktops.0.username", testserver.DefaultUsername),
+					tfresource.TestCheckResourceAttr("data.isardvdi_desktops.by_owner", "desktops.#", "2"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_desktops.by_tag", "desktops.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestMatchesDesktopFilters(t *testing.T) {
+	desktop := client.Desktop{
+		Name:       "Ubuntu Alumno",
+		User:       "ana",
+		Group:      "daw1",
+		Category:   "tknika",
+		TemplateID: "_template_ubuntu",
+		Status:     "Started",
+		Tag:        "deployment-1",
+	}
+
+	tests := []struct {
+		data desktopsDataSourceModel
+		tags []string
+		want bool
+	}{
+		{desktopsDataSourceModel{}, nil, true},
+		{desktopsDataSourceModel{NameFilter: types.StringValue("alumno")}, nil, true},
+		{desktopsDataSourceModel{NameFilter: types.StringValue("profesor")}, nil, false},
+		{desktopsDataSourceModel{UserID: types.StringValue("ana"), GroupID: types.StringValue("daw1"), CategoryID: types.StringValue("tknika")}, nil, true},
+		{desktopsDataSourceModel{GroupID: types.StringValue("daw2")}, nil, false},
+		{desktopsDataSourceModel{TemplateID: types.StringValue("_template_windows")}, nil, false},
+		{desktopsDataSourceModel{Status: types.StringValue("started")}, nil, true},
+		{desktopsDataSourceModel{Status: types.StringValue("Stopped")}, nil, false},
+		{desktopsDataSourceModel{}, []string{"deployment-2", "deployment-1"}, true},
+		{desktopsDataSourceModel{}, []string{"deployment-2"}, false},
+	}
+
+	for i, tt := range tests {
+		if got := matchesDesktopFilters(desktop, tt.data, tt.tags); got != tt.want {
+			t.Errorf("caso %d: matchesDesktopFilters = %t, se esperaba %t", i, got, tt.want)
+		}
+	}
+}
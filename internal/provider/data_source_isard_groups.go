@@ -32,6 +32,7 @@ type groupModel struct {
 	Name           types.String `tfsdk:"name"`
 	Description    types.String `tfsdk:"description"`
 	ParentCategory types.String `tfsdk:"parent_category"`
+	Enrollment     types.Map    `tfsdk:"enrollment"`
 }
 
 func (d *groupsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -75,6 +76,12 @@ func (d *groupsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 							Description: "Parent category ID.",
 							Computed:    true,
 						},
+						"enrollment": schema.MapAttribute{
+							Description: "Active self-registration codes of the group, keyed by role (manager, advanced, user).",
+							ElementType: types.StringType,
+							Computed:    true,
+							Sensitive:   true,
+						},
 					},
 				},
 			},
@@ -107,9 +114,9 @@ func (d *groupsDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
-	groups, err := d.client.GetGroups()
+	groups, err := d.client.GetGroups(ctx)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read groups, got error: %s", err))
+		resp.Diagnostics.AddError("Client Error", clientErrorDetail("Unable to read groups", err))
 		return
 	}
 
@@ -140,6 +147,7 @@ func (d *groupsDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 			Name:           types.StringValue(group.Name),
 			Description:    types.StringValue(group.Description),
 			ParentCategory: types.StringValue(group.ParentCategory),
+			Enrollment:     enrollmentCodesValue(group.EnrollmentCodes()),
 		}
 	}
 
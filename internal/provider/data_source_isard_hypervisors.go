@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+)
+
+var _ datasource.DataSource = &hypervisorsDataSource{}
+
+func NewHypervisorsDataSource() datasource.DataSource {
+	return &hypervisorsDataSource{}
+}
+
+type hypervisorsDataSource struct {
+	client *client.Client
+}
+
+type hypervisorsDataSourceModel struct {
+	ID          types.String      `tfsdk:"id"`
+	NameFilter  types.String      `tfsdk:"name_filter"`
+	Status      types.String      `tfsdk:"status"`
+	Hypervisors []hypervisorModel `tfsdk:"hypervisors"`
+}
+
+type hypervisorModel struct {
+	ID             types.String `tfsdk:"id"`
+	Hostname       types.String `tfsdk:"hostname"`
+	Description    types.String `tfsdk:"description"`
+	Status         types.String `tfsdk:"status"`
+	Enabled        types.Bool   `tfsdk:"enabled"`
+	OnlyForced     types.Bool   `tfsdk:"only_forced"`
+	GPUOnly        types.Bool   `tfsdk:"gpu_only"`
+	Hypervisor     types.Bool   `tfsdk:"hypervisor"`
+	DiskOperations types.Bool   `tfsdk:"disk_operations"`
+}
+
+func (d *hypervisorsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hypervisors"
+}
+
+func (d *hypervisorsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches the list of hypervisors from Isard VDI.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Placeholder identifier for the data source.",
+				Computed:    true,
+			},
+			"name_filter": schema.StringAttribute{
+				Description: "Optional filter to match hypervisor IDs or hostnames (case-insensitive substring match).",
+				Optional:    true,
+			},
+			"status": schema.StringAttribute{
+				Description: "Optional filter to match hypervisors by status, e.g. Online (case-insensitive).",
+				Optional:    true,
+			},
+			"hypervisors": schema.ListNestedAttribute{
+				Description: "List of hypervisors.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Description: "Hypervisor ID.",
+							Computed:    true,
+						},
+						"hostname": schema.StringAttribute{
+							Description: "Hypervisor hostname.",
+							Computed:    true,
+						},
+						"description": schema.StringAttribute{
+							Description: "Hypervisor description.",
+							Computed:    true,
+						},
+						"status": schema.StringAttribute{
+							Description: "Hypervisor status (Online, Offline, Error...).",
+							Computed:    true,
+						},
+						"enabled": schema.BoolAttribute{
+							Description: "Whether the hypervisor is enabled.",
+							Computed:    true,
+						},
+						"only_forced": schema.BoolAttribute{
+							Description: "Whether the hypervisor only runs desktops explicitly forced to it.",
+							Computed:    true,
+						},
+						"gpu_only": schema.BoolAttribute{
+							Description: "Whether the hypervisor only runs desktops with GPU.",
+							Computed:    true,
+						},
+						"hypervisor": schema.BoolAttribute{
+							Description: "Whether the hypervisor can run desktops.",
+							Computed:    true,
+						},
+						"disk_operations": schema.BoolAttribute{
+							Description: "Whether the hypervisor can run disk operations.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *hypervisorsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *hypervisorsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data hypervisorsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	hypervisors, err := d.client.GetHypervisors(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", clientErrorDetail("Unable to read hypervisors", err))
+		return
+	}
+
+	nameFilter := strings.ToLower(data.NameFilter.ValueString())
+	statusFilter := data.Status.ValueString()
+
+	data.Hypervisors = []hypervisorModel{}
+	for _, hyper := range hypervisors {
+		if nameFilter != "" &&
+			!strings.Contains(strings.ToLower(hyper.ID), nameFilter) &&
+			!strings.Contains(strings.ToLower(hyper.Hostname), nameFilter) {
+			continue
+		}
+		if statusFilter != "" && !strings.EqualFold(hyper.Status, statusFilter) {
+			continue
+		}
+		data.Hypervisors = append(data.Hypervisors, hypervisorModel{
+			ID:             types.StringValue(hyper.ID),
+			Hostname:       types.StringValue(hyper.Hostname),
+			Description:    types.StringValue(hyper.Description),
+			Status:         types.StringValue(hyper.Status),
+			Enabled:        types.BoolValue(hyper.Enabled),
+			OnlyForced:     types.BoolValue(hyper.OnlyForced),
+			GPUOnly:        types.BoolValue(hyper.GPUOnly),
+			Hypervisor:     types.BoolValue(hyper.Capabilities.Hypervisor),
+			DiskOperations: types.BoolValue(hyper.Capabilities.DiskOperations),
+		})
+	}
+
+	data.ID = types.StringValue("hypervisors")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
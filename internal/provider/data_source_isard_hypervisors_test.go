@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"testing"
+
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/tknika/terraform-provider-isardvdi/internal/testserver"
+)
+
+func TestAccHypervisorsDataSource(t *testing.T) {
+	srv := testAccServer(t)
+	srv.AddHypervisor(map[string]interface{}{
+		"id":           "isard-gpu",
+		"hostname":     "gpu.isardvdi.local",
+		"description":  "Hipervisor con GPU",
+		"status":       "Offline",
+		"enabled":      false,
+		"only_forced":  true,
+		"gpu_only":     true,
+		"capabilities": map[string]interface{}{"hypervisor": true, "disk_operations": false},
+	})
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []tfresource.TestStep{
+			{
+				// name_filter acepta el ID o el hostname; status no distingue mayúsculas
+				Config: testAccProviderConfig(srv) + `
+data "isardvdi_hypervisors" "all" {}
+
+data "isardvdi_hypervisors" "by_hostname" {
+  name_filter = "GPU.ISARDVDI"
+}
+
+data "isardvdi_hypervisors" "online" {
+  status = "online"
+}
+
+data "isardvdi_hypervisors" "none" {
+  name_filter = "gpu"
+  status      = "Online"
+}
+`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("data.isardvdi_hypervisors.all", "id", "hypervisors"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_hypervisors.all", "hypervisors.#", "2"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_hypervisors.by_hostname", "hypervisors.#", "1"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_hypervisors.by_hostname", "hypervisors.0.id", "isard-gpu"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_hypervisors.by_hostname", "hypervisors.0.status", "Offline"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_hypervisors.by_hostname", "hypervisors.0.enabled", "false"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_hypervisors.by_hostname", "hypervisors.0.only_forced", "true"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_hypervisors.by_hostname", "hypervisors.0.gpu_only", "true"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_hypervisors.by_hostname", "hypervisors.0.disk_operations", "false"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_hypervisors.online", "hypervisors.#", "1"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_hypervisors.online", "hypervisors.0.id", testserver.DefaultHypervisorID),
+					tfresource.TestCheckResourceAttr("data.isardvdi_hypervisors.online", "hypervisors.0.hypervisor", "true"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_hypervisors.online", "hypervisors.0.disk_operations", "true"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_hypervisors.none", "hypervisors.#", "0"),
+				),
+			},
+		},
+	})
+}
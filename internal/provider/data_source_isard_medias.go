@@ -2,7 +2,6 @@ package provider
 
 import (
 	"context"
-	"fmt"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
@@ -155,11 +154,11 @@ func (d *mediasDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	}
 
 	// Obtener todos los medios
-	medias, err := d.client.GetMedias()
+	medias, err := d.client.GetMedias(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error obteniendo medios",
-			fmt.Sprintf("No se pudo obtener la lista de medios: %s", err.Error()),
+			clientErrorDetail("No se pudo obtener la lista de medios", err),
 		)
 		return
 	}
@@ -140,11 +140,11 @@ func (d *networkInterfacesDataSource) Read(ctx context.Context, req datasource.R
 	}
 
 	// Obtener todas las interfaces
-	interfaces, err := d.client.ListNetworkInterfaces()
+	interfaces, err := d.client.ListNetworkInterfaces(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error obteniendo interfaces de red",
-			"No se pudo obtener la lista de interfaces: "+err.Error(),
+			clientErrorDetail("No se pudo obtener la lista de interfaces", err),
 		)
 		return
 	}
@@ -0,0 +1,190 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+)
+
+var _ datasource.DataSource = &networksDataSource{}
+
+func NewNetworksDataSource() datasource.DataSource {
+	return &networksDataSource{}
+}
+
+type networksDataSource struct {
+	client *client.Client
+}
+
+type networksDataSourceModel struct {
+	ID         types.String   `tfsdk:"id"`
+	NameFilter types.String   `tfsdk:"name_filter"`
+	Model      types.String   `tfsdk:"model"`
+	UserID     types.String   `tfsdk:"user_id"`
+	QoSID      types.String   `tfsdk:"qos_id"`
+	Networks   []networkModel `tfsdk:"networks"`
+}
+
+type networkModel struct {
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+	Model       types.String `tfsdk:"model"`
+	QoSID       types.String `tfsdk:"qos_id"`
+	MetadataID  types.String `tfsdk:"metadata_id"`
+	UserID      types.String `tfsdk:"user_id"`
+	GroupID     types.String `tfsdk:"group_id"`
+	CategoryID  types.String `tfsdk:"category_id"`
+}
+
+func (d *networksDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_networks"
+}
+
+func (d *networksDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches the list of user networks from Isard VDI.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Placeholder identifier for the data source.",
+				Computed:    true,
+			},
+			"name_filter": schema.StringAttribute{
+				Description: "Optional filter to match network names (case-insensitive substring match).",
+				Optional:    true,
+			},
+			"model": schema.StringAttribute{
+				Description: "Optional filter to match networks by interface model (e.g. virtio).",
+				Optional:    true,
+			},
+			"user_id": schema.StringAttribute{
+				Description: "Optional filter to match networks by owner user ID.",
+				Optional:    true,
+			},
+			"qos_id": schema.StringAttribute{
+				Description: "Optional filter to match networks by QoS profile ID.",
+				Optional:    true,
+			},
+			"networks": schema.ListNestedAttribute{
+				Description: "List of networks available.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Description: "Network ID.",
+							Computed:    true,
+						},
+						"name": schema.StringAttribute{
+							Description: "Network name.",
+							Computed:    true,
+						},
+						"description": schema.StringAttribute{
+							Description: "Network description.",
+							Computed:    true,
+						},
+						"model": schema.StringAttribute{
+							Description: "Interface model of the network.",
+							Computed:    true,
+						},
+						"qos_id": schema.StringAttribute{
+							Description: "ID of the QoS profile applied to the network.",
+							Computed:    true,
+						},
+						"metadata_id": schema.StringAttribute{
+							Description: "Metadata ID generated for OpenFlow.",
+							Computed:    true,
+						},
+						"user_id": schema.StringAttribute{
+							Description: "ID of the user who owns the network.",
+							Computed:    true,
+						},
+						"group_id": schema.StringAttribute{
+							Description: "Group ID of the owner.",
+							Computed:    true,
+						},
+						"category_id": schema.StringAttribute{
+							Description: "Category ID of the owner.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *networksDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *networksDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data networksDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	networks, err := d.client.ListNetworks(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", clientErrorDetail("Unable to read networks", err))
+		return
+	}
+
+	data.Networks = []networkModel{}
+	for _, network := range networks {
+		if !matchesNetworkFilters(network, data) {
+			continue
+		}
+		data.Networks = append(data.Networks, networkModel{
+			ID:          types.StringValue(network.ID),
+			Name:        types.StringValue(network.Name),
+			Description: types.StringValue(network.Description),
+			Model:       types.StringValue(network.Model),
+			QoSID:       types.StringValue(network.QoSID),
+			MetadataID:  types.StringValue(network.MetadataID),
+			UserID:      types.StringValue(network.User),
+			GroupID:     types.StringValue(network.Group),
+			CategoryID:  types.StringValue(network.Category),
+		})
+	}
+
+	data.ID = types.StringValue("networks")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// matchesNetworkFilters indica si la red cumple todos los filtros indicados
+func matchesNetworkFilters(network client.Network, data networksDataSourceModel) bool {
+	if f := data.NameFilter.ValueString(); f != "" && !strings.Contains(strings.ToLower(network.Name), strings.ToLower(f)) {
+		return false
+	}
+	if f := data.Model.ValueString(); f != "" && network.Model != f {
+		return false
+	}
+	if f := data.UserID.ValueString(); f != "" && network.User != f {
+		return false
+	}
+	if f := data.QoSID.ValueString(); f != "" && network.QoSID != f {
+		return false
+	}
+	return true
+}
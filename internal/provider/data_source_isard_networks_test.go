@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/tknika/terraform-provider-isardvdi/internal/testserver"
+)
+
+func TestAccNetworksDataSource(t *testing.T) {
+	srv := testAccServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "isardvdi_network" "alumnos" {
+  name   = "tf-acc-alumnos"
+  qos_id = "limitado"
+}
+
+resource "isardvdi_network" "profesores" {
+  name  = "tf-acc-profesores"
+  model = "e1000"
+}
+
+data "isardvdi_networks" "all" {
+  depends_on = [isardvdi_network.alumnos, isardvdi_network.profesores]
+}
+
+data "isardvdi_networks" "by_name" {
+  name_filter = "ALUMNOS"
+  depends_on  = [isardvdi_network.alumnos, isardvdi_network.profesores]
+}
+
+data "isardvdi_networks" "by_model" {
+  model      = "e1000"
+  user_id    = %q
+  depends_on = [isardvdi_network.alumnos, isardvdi_network.profesores]
+}
+
+data "isardvdi_networks" "by_qos" {
+  qos_id     = "limitado"
+  depends_on = [isardvdi_network.alumnos, isardvdi_network.profesores]
+}
+
+data "isardvdi_networks" "by_user" {
+  user_id    = "otro-usuario"
+  depends_on = [isardvdi_network.alumnos, isardvdi_network.profesores]
+}
+`, testserver.DefaultUserID),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("data.isardvdi_networks.all", "id", "networks"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_networks.all", "networks.#", "2"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_networks.by_name", "networks.#", "1"),
+					tfresource.TestCheckResourceAttrPair("data.isardvdi_networks.by_name", "networks.0.id", "isardvdi_network.alumnos", "id"),
+					tfresource.TestCheckResourceAttrPair("data.isardvdi_networks.by_name", "networks.0.metadata_id", "isardvdi_network.alumnos", "metadata_id"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_networks.by_name", "networks.0.user_id", testserver.DefaultUserID),
+					tfresource.TestCheckResourceAttr("data.isardvdi_networks.by_name", "networks.0.group_id", testserver.DefaultGroupID),
+					tfresource.TestCheckResourceAttr("data.isardvdi_networks.by_name", "networks.0.category_id", testserver.DefaultCategoryID),
+					tfresource.TestCheckResourceAttr("data.isardvdi_networks.by_model", "networks.#", "1"),
+					tfresource.TestCheckResourceAttrPair("data.isardvdi_networks.by_model", "networks.0.id", "isardvdi_network.profesores", "id"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_networks.by_qos", "networks.#", "1"),
+					tfresource.TestCheckResourceAttrPair("data.isardvdi_networks.by_qos", "networks.0.id", "isardvdi_network.alumnos", "id"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_networks.by_qos", "networks.0.qos_id", "limitado"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_networks.by_user", "networks.#", "0"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,157 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+)
+
+var _ datasource.DataSource = &qosNetsDataSource{}
+
+func NewQoSNetsDataSource() datasource.DataSource {
+	return &qosNetsDataSource{}
+}
+
+type qosNetsDataSource struct {
+	client *client.Client
+}
+
+type qosNetsDataSourceModel struct {
+	ID         types.String  `tfsdk:"id"`
+	NameFilter types.String  `tfsdk:"name_filter"`
+	QoSNets    []qosNetModel `tfsdk:"qos_nets"`
+}
+
+type qosNetModel struct {
+	ID              types.String `tfsdk:"id"`
+	Name            types.String `tfsdk:"name"`
+	Description     types.String `tfsdk:"description"`
+	AverageDownload types.Int64  `tfsdk:"average_download"`
+	AverageUpload   types.Int64  `tfsdk:"average_upload"`
+	PeakDownload    types.Int64  `tfsdk:"peak_download"`
+	PeakUpload      types.Int64  `tfsdk:"peak_upload"`
+	BurstDownload   types.Int64  `tfsdk:"burst_download"`
+	BurstUpload     types.Int64  `tfsdk:"burst_upload"`
+}
+
+func (d *qosNetsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_qos_nets"
+}
+
+func (d *qosNetsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	computedInt := func(description string) schema.Int64Attribute {
+		return schema.Int64Attribute{Description: description, Computed: true}
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Fetches the list of network QoS profiles from Isard VDI.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Placeholder identifier for the data source.",
+				Computed:    true,
+			},
+			"name_filter": schema.StringAttribute{
+				Description: "Optional filter to match QoS profile IDs or names (case-insensitive substring match).",
+				Optional:    true,
+			},
+			"qos_nets": schema.ListNestedAttribute{
+				Description: "List of network QoS profiles available.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Description: "QoS profile ID.",
+							Computed:    true,
+						},
+						"name": schema.StringAttribute{
+							Description: "QoS profile name.",
+							Computed:    true,
+						},
+						"description": schema.StringAttribute{
+							Description: "QoS profile description.",
+							Computed:    true,
+						},
+						"average_download": computedInt("Average download rate in KB/s. Null if not limited."),
+						"average_upload":   computedInt("Average upload rate in KB/s. Null if not limited."),
+						"peak_download":    computedInt("Peak download rate in KB/s. Null if not limited."),
+						"peak_upload":      computedInt("Peak upload rate in KB/s. Null if not limited."),
+						"burst_download":   computedInt("Download burst size in KB. Null if not limited."),
+						"burst_upload":     computedInt("Upload burst size in KB. Null if not limited."),
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *qosNetsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *qosNetsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data qosNetsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	qosNets, err := d.client.ListQoSNets(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", clientErrorDetail("Unable to read network QoS profiles", err))
+		return
+	}
+
+	// El ID de los QoS suele coincidir con el nombre, pero no siempre: el filtro acepta ambos
+	nameFilter := strings.ToLower(data.NameFilter.ValueString())
+
+	data.QoSNets = []qosNetModel{}
+	for _, qos := range qosNets {
+		if nameFilter != "" &&
+			!strings.Contains(strings.ToLower(qos.ID), nameFilter) &&
+			!strings.Contains(strings.ToLower(qos.Name), nameFilter) {
+			continue
+		}
+		data.QoSNets = append(data.QoSNets, qosNetModel{
+			ID:              types.StringValue(qos.ID),
+			Name:            types.StringValue(qos.Name),
+			Description:     types.StringValue(qos.Description),
+			AverageDownload: bandwidthValue(qos.Bandwidth, "average_download"),
+			AverageUpload:   bandwidthValue(qos.Bandwidth, "average_upload"),
+			PeakDownload:    bandwidthValue(qos.Bandwidth, "peak_download"),
+			PeakUpload:      bandwidthValue(qos.Bandwidth, "peak_upload"),
+			BurstDownload:   bandwidthValue(qos.Bandwidth, "burst_download"),
+			BurstUpload:     bandwidthValue(qos.Bandwidth, "burst_upload"),
+		})
+	}
+
+	data.ID = types.StringValue("qos_nets")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// bandwidthValue devuelve un límite de bandwidth del QoS, o null si no está definido
+func bandwidthValue(bandwidth map[string]interface{}, key string) types.Int64 {
+	if val, ok := bandwidth[key].(float64); ok {
+		return types.Int64Value(int64(val))
+	}
+	return types.Int64Null()
+}
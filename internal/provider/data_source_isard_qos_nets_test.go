@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"testing"
+
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccQoSNetsDataSource(t *testing.T) {
+	srv := testAccServer(t)
+	srv.AddTableItem("qos_net", map[string]interface{}{
+		"id":          "limitado",
+		"name":        "Limitado 10M",
+		"description": "Aulas",
+		"bandwidth": map[string]interface{}{
+			"average_download": 10000,
+			"average_upload":   5000,
+		},
+	})
+	srv.AddTableItem("qos_net", map[string]interface{}{
+		"id":   "unlimited",
+		"name": "Sin límite",
+	})
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []tfresource.TestStep{
+			{
+				// El filtro acepta el ID o el nombre del QoS
+				Config: testAccProviderConfig(srv) + `
+data "isardvdi_qos_nets" "all" {}
+
+data "isardvdi_qos_nets" "by_name" {
+  name_filter = "10m"
+}
+
+data "isardvdi_qos_nets" "by_id" {
+  name_filter = "UNLIMITED"
+}
+
+data "isardvdi_qos_nets" "none" {
+  name_filter = "inexistente"
+}
+`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("data.isardvdi_qos_nets.all", "id", "qos_nets"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_qos_nets.all", "qos_nets.#", "2"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_qos_nets.by_name", "qos_nets.#", "1"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_qos_nets.by_name", "qos_nets.0.id", "limitado"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_qos_nets.by_name", "qos_nets.0.description", "Aulas"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_qos_nets.by_name", "qos_nets.0.average_download", "10000"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_qos_nets.by_name", "qos_nets.0.average_upload", "5000"),
+					tfresource.TestCheckNoResourceAttr("data.isardvdi_qos_nets.by_name", "qos_nets.0.peak_download"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_qos_nets.by_id", "qos_nets.#", "1"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_qos_nets.by_id", "qos_nets.0.name", "Sin límite"),
+					tfresource.TestCheckNoResourceAttr("data.isardvdi_qos_nets.by_id", "qos_nets.0.average_download"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_qos_nets.none", "qos_nets.#", "0"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+)
+
+var _ datasource.DataSource = &rolesDataSource{}
+
+func NewRolesDataSource() datasource.DataSource {
+	return &rolesDataSource{}
+}
+
+type rolesDataSource struct {
+	client *client.Client
+}
+
+type rolesDataSourceModel struct {
+	ID         types.String `tfsdk:"id"`
+	NameFilter types.String `tfsdk:"name_filter"`
+	Roles      []roleModel  `tfsdk:"roles"`
+}
+
+type roleModel struct {
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+	SortOrder   types.Int64  `tfsdk:"sort_order"`
+}
+
+func (d *rolesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_roles"
+}
+
+func (d *rolesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches the list of user roles from Isard VDI.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Placeholder identifier for the data source.",
+				Computed:    true,
+			},
+			"name_filter": schema.StringAttribute{
+				Description: "Optional filter to match role IDs or names (case-insensitive substring match).",
+				Optional:    true,
+			},
+			"roles": schema.ListNestedAttribute{
+				Description: "List of roles available.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Description: "Role ID (admin, manager, advanced or user).",
+							Computed:    true,
+						},
+						"name": schema.StringAttribute{
+							Description: "Role name.",
+							Computed:    true,
+						},
+						"description": schema.StringAttribute{
+							Description: "Role description.",
+							Computed:    true,
+						},
+						"sort_order": schema.Int64Attribute{
+							Description: "Role privilege order (higher values have more privileges).",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *rolesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *rolesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data rolesDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	roles, err := d.client.GetRoles(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", clientErrorDetail("Unable to read roles", err))
+		return
+	}
+
+	// Los roles tienen ID (admin) y nombre (Administrator): el filtro acepta ambos
+	nameFilter := strings.ToLower(data.NameFilter.ValueString())
+
+	data.Roles = []roleModel{}
+	for _, role := range roles {
+		if nameFilter != "" &&
+			!strings.Contains(strings.ToLower(role.ID), nameFilter) &&
+			!strings.Contains(strings.ToLower(role.Name), nameFilter) {
+			continue
+		}
+		data.Roles = append(data.Roles, roleModel{
+			ID:          types.StringValue(role.ID),
+			Name:        types.StringValue(role.Name),
+			Description: types.StringValue(role.Description),
+			SortOrder:   types.Int64Value(role.SortOrder),
+		})
+	}
+
+	data.ID = types.StringValue("roles")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"testing"
+
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccRolesDataSource(t *testing.T) {
+	srv := testAccServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []tfresource.TestStep{
+			{
+				// El filtro acepta el ID (admin) o el nombre (Administrator) del rol
+				Config: testAccProviderConfig(srv) + `
+data "isardvdi_roles" "all" {}
+
+data "isardvdi_roles" "by_id" {
+  name_filter = "ADV"
+}
+
+data "isardvdi_roles" "by_name" {
+  name_filter = "administrator"
+}
+
+data "isardvdi_roles" "none" {
+  name_filter = "inexistente"
+}
+`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("data.isardvdi_roles.all", "id", "roles"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_roles.all", "roles.#", "4"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_roles.by_id", "roles.#", "1"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_roles.by_id", "roles.0.id", "advanced"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_roles.by_id", "roles.0.sort_order", "2"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_roles.by_name", "roles.#", "1"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_roles.by_name", "roles.0.id", "admin"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_roles.by_name", "roles.0.name", "Administrator"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_roles.none", "roles.#", "0"),
+				),
+			},
+		},
+	})
+}
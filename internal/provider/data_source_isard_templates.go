@@ -132,9 +132,9 @@ func (d *templatesDataSource) Read(ctx context.Context, req datasource.ReadReque
 		return
 	}
 
-	templates, err := d.client.GetTemplates()
+	templates, err := d.client.GetTemplates(ctx)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read templates, got error: %s", err))
+		resp.Diagnostics.AddError("Client Error", clientErrorDetail("Unable to read templates", err))
 		return
 	}
 
@@ -185,10 +185,10 @@ func (d *usersDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	}
 
 	// Obtener todos los usuarios y aplicar filtros localmente
-	users, err := d.client.GetUsers()
+	users, err := d.client.GetUsers(ctx)
 	
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read users, got error: %s", err))
+		resp.Diagnostics.AddError("Client Error", clientErrorDetail("Unable to read users", err))
 		return
 	}
 
@@ -0,0 +1,189 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ ephemeral.EphemeralResource              = &desktopViewerEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &desktopViewerEphemeralResource{}
+)
+
+// NewDesktopViewerEphemeralResource is a helper function to simplify the provider implementation.
+func NewDesktopViewerEphemeralResource() ephemeral.EphemeralResource {
+	return &desktopViewerEphemeralResource{}
+}
+
+// desktopViewerEphemeralResource is the ephemeral resource implementation.
+type desktopViewerEphemeralResource struct {
+	client *client.Client
+}
+
+// desktopViewerEphemeralResourceModel maps the ephemeral resource schema data.
+type desktopViewerEphemeralResourceModel struct {
+	DesktopID  types.String `tfsdk:"desktop_id"`
+	Viewer     types.String `tfsdk:"viewer"`
+	DirectLink types.Bool   `tfsdk:"direct_link"`
+	Kind       types.String `tfsdk:"kind"`
+	Protocol   types.String `tfsdk:"protocol"`
+	URL        types.String `tfsdk:"url"`
+	Cookie     types.String `tfsdk:"cookie"`
+	FileName   types.String `tfsdk:"file_name"`
+	MimeType   types.String `tfsdk:"mime_type"`
+	Content    types.String `tfsdk:"content"`
+	JumperURL  types.String `tfsdk:"jumper_url"`
+}
+
+// Metadata returns the ephemeral resource type name.
+func (r *desktopViewerEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_desktop_viewer"
+}
+
+// Schema defines the schema for the ephemeral resource.
+func (r *desktopViewerEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Obtiene los datos de conexión de un viewer de un desktop de Isard VDI sin guardarlos en el estado (requiere Terraform 1.10+).",
+		Attributes: map[string]schema.Attribute{
+			"desktop_id": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "ID del desktop. Debe estar arrancado.",
+			},
+			"viewer": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Viewer a usar, con el mismo nombre que en `viewers` (`browser_vnc`, `browser_rdp`, `file_spice`, `file_rdpgw` o `file_rdpvpn`). Debe estar habilitado en el desktop.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(client.ViewerTypes...),
+				},
+			},
+			"direct_link": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Si es true, devuelve también el enlace directo (`jumper_url`) del desktop, que permite abrirlo sin iniciar sesión. El enlace debe estar habilitado en Isard VDI: este recurso no lo habilita.",
+			},
+			"kind": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Tipo de viewer: `browser` (se abre con `url`) o `file` (se abre el fichero `content` con el cliente correspondiente)",
+			},
+			"protocol": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Protocolo del viewer (`vnc`, `rdp` o `spice`)",
+			},
+			"url": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "URL del viewer de navegador. Vacía en los viewers de fichero.",
+			},
+			"cookie": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "Cookie de sesión del viewer de navegador. Vacía en los viewers de fichero.",
+			},
+			"file_name": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Nombre sugerido del fichero del viewer (p. ej. `isard-spice.vv`). Vacío en los viewers de navegador.",
+			},
+			"mime_type": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Tipo MIME del fichero del viewer. Vacío en los viewers de navegador.",
+			},
+			"content": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "Contenido del fichero del viewer (`.vv` para SPICE, `.rdp` para RDP). Vacío en los viewers de navegador.",
+			},
+			"jumper_url": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "Enlace directo del desktop. Solo se informa con `direct_link = true`.",
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the ephemeral resource.
+func (r *desktopViewerEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Open obtiene los datos de conexión del viewer. No se guardan en el estado.
+func (r *desktopViewerEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data desktopViewerEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	desktopID := data.DesktopID.ValueString()
+	viewer, err := r.client.GetDesktopViewer(ctx, desktopID, data.Viewer.ValueString())
+	if err != nil {
+		switch {
+		case client.IsNotFound(err):
+			resp.Diagnostics.AddError("Desktop no encontrado", "No existe ningún desktop con ID "+desktopID+".")
+		case client.IsPreconditionRequired(err):
+			resp.Diagnostics.AddError(
+				"Desktop no arrancado",
+				clientErrorDetail(fmt.Sprintf("El desktop (ID: %s) debe estar arrancado para obtener el viewer. Usa desired_state = \"started\" en isardvdi_vm o isardvdi_deployment", desktopID), err),
+			)
+		default:
+			resp.Diagnostics.AddError(
+				"Error obteniendo el viewer",
+				clientErrorDetail(fmt.Sprintf("No se pudo obtener el viewer %s del desktop (ID: %s)", data.Viewer.ValueString(), desktopID), err),
+			)
+		}
+		return
+	}
+
+	data.Kind = types.StringValue(viewer.Kind)
+	data.Protocol = types.StringValue(viewer.Protocol)
+	data.URL = types.StringValue(viewer.URL)
+	data.Cookie = types.StringValue(viewer.Cookie)
+	data.FileName = types.StringValue(viewer.FileName())
+	data.MimeType = types.StringValue(viewer.MimeType)
+	data.Content = types.StringValue(viewer.Content)
+	data.JumperURL = types.StringNull()
+
+	if data.DirectLink.ValueBool() {
+		// Solo se lee: habilitar el enlace cambiaría el desktop de forma permanente
+		jumperURL, err := r.client.GetJumperURL(ctx, desktopID)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error obteniendo el enlace directo",
+				clientErrorDetail(fmt.Sprintf("No se pudo obtener el enlace directo del desktop (ID: %s)", desktopID), err),
+			)
+			return
+		}
+		if jumperURL == "" {
+			resp.Diagnostics.AddError(
+				"Enlace directo no habilitado",
+				fmt.Sprintf("El desktop (ID: %s) no tiene enlace directo. Habilítalo desde Isard VDI o usa direct_link = false.", desktopID),
+			)
+			return
+		}
+		data.JumperURL = types.StringValue(jumperURL)
+	}
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+	"github.com/tknika/terraform-provider-isardvdi/internal/testserver"
+)
+
+func TestDesktopViewerOpenNotStarted(t *testing.T) {
+	c, srv := newTestClient(t)
+	r := &desktopViewerEphemeralResource{client: c}
+
+	id := testViewerDesktop(t, c)
+	srv.SetDesktopStatus(id, "Stopped")
+
+	resp := openDesktopViewer(t, r, map[string]interface{}{"desktop_id": id, "viewer": "browser_vnc"})
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Summary() != "Desktop no arrancado" {
+		t.Fatalf("Open con el desktop detenido = %v, se esperaba \"Desktop no arrancado\"", resp.Diagnostics)
+	}
+
+	resp = openDesktopViewer(t, r, map[string]interface{}{"desktop_id": "no-existe", "viewer": "browser_vnc"})
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Summary() != "Desktop no encontrado" {
+		t.Errorf("Open de un desktop inexistente = %v, se esperaba \"Desktop no encontrado\"", resp.Diagnostics)
+	}
+}
+
+func TestDesktopViewerOpenDirectLink(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+	r := &desktopViewerEphemeralResource{client: c}
+
+	id := testViewerDesktop(t, c)
+	srv.SetDesktopStatus(id, "Started")
+	attrs := map[string]interface{}{"desktop_id": id, "viewer": "browser_vnc", "direct_link": true}
+	resetPath := "/api/v3/desktop/jumperurl_reset/" + id
+
+	// Sin enlace directo habilitado falla sin habilitarlo
+	resp := openDesktopViewer(t, r, attrs)
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Summary() != "Enlace directo no habilitado" {
+		t.Fatalf("Open sin enlace directo = %v, se esperaba \"Enlace directo no habilitado\"", resp.Diagnostics)
+	}
+	if _, ok := srv.LastRequest(http.MethodPut, resetPath); ok {
+		t.Fatal("se ha habilitado el enlace directo")
+	}
+
+	// Con el enlace habilitado se devuelve sin cambiar el token
+	enabled, err := c.ResetJumperURL(ctx, id)
+	if err != nil {
+		t.Fatalf("ResetJumperURL: %v", err)
+	}
+	requests := len(srv.Requests())
+	resp = openDesktopViewer(t, r, attrs)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Open: %v", resp.Diagnostics)
+	}
+	var kind, jumperURL types.String
+	resp.Result.GetAttribute(ctx, path.Root("kind"), &kind)
+	resp.Result.GetAttribute(ctx, path.Root("jumper_url"), &jumperURL)
+	if kind.ValueString() != "browser" {
+		t.Errorf("kind = %s, se esperaba browser", kind)
+	}
+	if jumperURL.ValueString() != enabled {
+		t.Errorf("jumper_url = %s, se esperaba el existente %s", jumperURL, enabled)
+	}
+	for _, req := range srv.Requests()[requests:] {
+		if req.Method == http.MethodPut && req.Path == resetPath {
+			t.Error("se ha regenerado un enlace directo ya habilitado")
+		}
+	}
+
+	// Sin direct_link no se consulta el enlace
+	resp = openDesktopViewer(t, r, map[string]interface{}{"desktop_id": id, "viewer": "browser_vnc"})
+	resp.Result.GetAttribute(ctx, path.Root("jumper_url"), &jumperURL)
+	if !jumperURL.IsNull() {
+		t.Errorf("jumper_url sin direct_link = %s, se esperaba null", jumperURL)
+	}
+}
+
+// testViewerDesktop crea un desktop con el viewer browser_vnc del template por defecto
+func testViewerDesktop(t *testing.T, c *client.Client) string {
+	t.Helper()
+
+	id, err := c.CreatePersistentDesktop(context.Background(), "viewer", "", testserver.DefaultTemplateID, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreatePersistentDesktop: %v", err)
+	}
+	return id
+}
+
+// openDesktopViewer ejecuta Open con la configuración indicada; el resto de atributos queda a null
+func openDesktopViewer(t *testing.T, r *desktopViewerEphemeralResource, attrs map[string]interface{}) *ephemeral.OpenResponse {
+	t.Helper()
+	ctx := context.Background()
+
+	var schemaResp ephemeral.SchemaResponse
+	r.Schema(ctx, ephemeral.SchemaRequest{}, &schemaResp)
+	raw, err := types.ObjectNull(schemaResp.Schema.Type().(types.ObjectType).AttrTypes).ToTerraformValue(ctx)
+	if err != nil {
+		t.Fatalf("ToTerraformValue: %v", err)
+	}
+
+	// El estado sirve para construir la configuración con los atributos indicados
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: raw}
+	for name, value := range attrs {
+		if diags := state.SetAttribute(ctx, path.Root(name), value); diags.HasError() {
+			t.Fatalf("SetAttribute(%s): %v", name, diags)
+		}
+	}
+
+	req := ephemeral.OpenRequest{Config: tfsdk.Config{Schema: state.Schema, Raw: state.Raw}}
+	resp := &ephemeral.OpenResponse{Result: tfsdk.EphemeralResultData{Schema: state.Schema, Raw: raw}}
+	r.Open(ctx, req, resp)
+	return resp
+}
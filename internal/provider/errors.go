@@ -0,0 +1,22 @@
+package provider
+
+import (
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+)
+
+// clientErrorDetail compone el detalle de un diagnóstico a partir de un error del cliente,
+// añadiendo una pista cuando la API rechaza la operación por falta de permisos o conflicto
+func clientErrorDetail(detail string, err error) string {
+	msg := detail + ": " + err.Error()
+
+	switch {
+	case client.IsForbidden(err):
+		msg += ". El usuario autenticado no tiene permisos suficientes para esta operación (algunos endpoints requieren rol de administrador)."
+	case client.IsUnauthorized(err):
+		msg += ". Las credenciales del provider no son válidas o la sesión ha caducado."
+	case client.IsConflict(err):
+		msg += ". Ya existe un recurso con los mismos datos; puede importarlo con 'terraform import'."
+	}
+
+	return msg
+}
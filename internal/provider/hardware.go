@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// stringListValue convierte un slice de strings en una lista de Terraform (vacía si es nil)
+func stringListValue(values []string) types.List {
+	elems := make([]attr.Value, len(values))
+	for i, v := range values {
+		elems[i] = types.StringValue(v)
+	}
+	return types.ListValueMust(types.StringType, elems)
+}
+
+// unorderedListValue construye la lista para atributos cuyo orden no es significativo en la API
+// (p. ej. viewers, que la API guarda como mapa). Si contiene los mismos elementos que la lista
+// actual, se conserva el orden actual para no generar diferencias espurias en el plan.
+func unorderedListValue(ctx context.Context, current types.List, values []string) types.List {
+	if !current.IsNull() && !current.IsUnknown() {
+		var existing []string
+		if diags := current.ElementsAs(ctx, &existing, false); !diags.HasError() && sameElements(existing, values) {
+			return current
+		}
+	}
+	return stringListValue(values)
+}
+
+// sameElements indica si dos slices contienen los mismos elementos sin importar el orden
+func sameElements(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	sortedA := append([]string(nil), a...)
+	sortedB := append([]string(nil), b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// listToStrings convierte una lista de Terraform en un slice de strings
+func listToStrings(ctx context.Context, list types.List, diags *diag.Diagnostics) []string {
+	if list.IsNull() || list.IsUnknown() {
+		return nil
+	}
+
+	var values []string
+	diags.Append(list.ElementsAs(ctx, &values, false)...)
+	return values
+}
+
+// mediaRefs construye la lista de medios ({"id": ...}) que espera el hardware de la API
+func mediaRefs(ids []string) []map[string]interface{} {
+	refs := make([]map[string]interface{}, len(ids))
+	for i, id := range ids {
+		refs[i] = map[string]interface{}{"id": id}
+	}
+	return refs
+}
+
+// viewersMap construye el mapa de viewers de guest_properties que espera la API
+func viewersMap(viewers []string) map[string]interface{} {
+	m := make(map[string]interface{}, len(viewers))
+	for _, viewer := range viewers {
+		m[viewer] = map[string]interface{}{"options": nil}
+	}
+	return m
+}
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestUnorderedListValue(t *testing.T) {
+	ctx := context.Background()
+	current := stringListValue([]string{"browser_vnc", "file_spice"})
+
+	// Mismos elementos en otro orden: se conserva el orden actual
+	if got := unorderedListValue(ctx, current, []string{"file_spice", "browser_vnc"}); !got.Equal(current) {
+		t.Errorf("unorderedListValue = %v, se esperaba %v", got, current)
+	}
+
+	want := stringListValue([]string{"file_spice"})
+	if got := unorderedListValue(ctx, current, []string{"file_spice"}); !got.Equal(want) {
+		t.Errorf("unorderedListValue = %v, se esperaba %v", got, want)
+	}
+
+	want = stringListValue([]string{"b", "a"})
+	if got := unorderedListValue(ctx, types.ListNull(types.StringType), []string{"b", "a"}); !got.Equal(want) {
+		t.Errorf("unorderedListValue(null) = %v, se esperaba %v", got, want)
+	}
+}
+
+func TestSameElements(t *testing.T) {
+	tests := []struct {
+		a, b []string
+		want bool
+	}{
+		{nil, []string{}, true},
+		{[]string{"a", "b"}, []string{"b", "a"}, true},
+		{[]string{"a", "a"}, []string{"a", "b"}, false},
+		{[]string{"a"}, []string{"a", "b"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := sameElements(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameElements(%v, %v) = %t, se esperaba %t", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestHardwareRefs(t *testing.T) {
+	refs := mediaRefs([]string{"iso1", "iso2"})
+	if len(refs) != 2 || refs[0]["id"] != "iso1" || refs[1]["id"] != "iso2" {
+		t.Errorf("mediaRefs = %v", refs)
+	}
+
+	viewers := viewersMap([]string{"browser_vnc"})
+	if _, ok := viewers["browser_vnc"].(map[string]interface{}); !ok || len(viewers) != 1 {
+		t.Errorf("viewersMap = %v", viewers)
+	}
+}
@@ -2,11 +2,17 @@ package provider
 
 import (
 	"context"
-
 	"fmt"
+	"os"
+	"strconv"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -15,8 +21,22 @@ import (
 	"github.com/tknika/terraform-provider-isardvdi/internal/client"
 )
 
+// Variables de entorno que se usan cuando el atributo no se define en el bloque provider
+const (
+	envEndpoint   = "ISARDVDI_ENDPOINT"
+	envAuthMethod = "ISARDVDI_AUTH_METHOD"
+	envCategoryID = "ISARDVDI_CATEGORY_ID"
+	envToken      = "ISARDVDI_TOKEN"
+	envUsername   = "ISARDVDI_USERNAME"
+	envPassword   = "ISARDVDI_PASSWORD"
+	envInsecure   = "ISARDVDI_INSECURE"
+)
+
 // Ensure IsardProvider satisfies various provider interfaces.
-var _ provider.Provider = &IsardProvider{}
+var (
+	_ provider.Provider                       = &IsardProvider{}
+	_ provider.ProviderWithEphemeralResources = &IsardProvider{}
+)
 
 // IsardProvider defines the provider implementation.
 type IsardProvider struct {
@@ -35,6 +55,15 @@ type IsardProviderModel struct {
 	Username        types.String `tfsdk:"username"`
 	Password        types.String `tfsdk:"password"`
 	SSLVerification types.Bool   `tfsdk:"ssl_verification"`
+	RequestTimeout  types.String `tfsdk:"request_timeout"`
+	MaxRetries      types.Int64  `tfsdk:"max_retries"`
+	RetryWaitMin    types.String `tfsdk:"retry_wait_min"`
+	RetryWaitMax    types.String `tfsdk:"retry_wait_max"`
+	CACertPEM       types.String `tfsdk:"ca_cert_pem"`
+	CACertFile      types.String `tfsdk:"ca_cert_file"`
+	ClientCert      types.String `tfsdk:"client_cert"`
+	ClientKey       types.String `tfsdk:"client_key"`
+	TLSMinVersion   types.String `tfsdk:"tls_min_version"`
 }
 
 func New(version string) func() provider.Provider {
@@ -57,38 +86,90 @@ func (p *IsardProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp
 		Description: "Interact with Isard VDI.",
 		Attributes: map[string]schema.Attribute{
 			"endpoint": schema.StringAttribute{
-				MarkdownDescription: "EndPoint of the Isard VDI Server",
-				Required:            true,
+				MarkdownDescription: "EndPoint of the Isard VDI Server: a bare host (HTTPS is assumed) or a full base URL with scheme, port and optional path prefix (e.g. `http://localhost:8080/isard`). May also be set with the `ISARDVDI_ENDPOINT` environment variable",
+				Optional:            true,
 			},
 			"token": schema.StringAttribute{
-				MarkdownDescription: "Authentication token for API access",
+				MarkdownDescription: "Authentication token for API access. May also be set with the `ISARDVDI_TOKEN` environment variable",
 				Optional:            true,
 				Sensitive:           true,
 			},
 			"auth_method": schema.StringAttribute{
-				MarkdownDescription: "Authentication method to use (token or credentials)",
+				MarkdownDescription: "Authentication method to use (token or credentials). May also be set with the `ISARDVDI_AUTH_METHOD` environment variable",
 				Optional:            true,
 				Validators: []validator.String{
 					stringvalidator.OneOf("token", "form"),
 				},
 			},
 			"cathegory_id": schema.StringAttribute{
-				MarkdownDescription: "Cathegory ID to scope the operations",
+				MarkdownDescription: "Cathegory ID to scope the operations. May also be set with the `ISARDVDI_CATEGORY_ID` environment variable. Default: `default`",
 				Optional:            true,
 			},
 			"username": schema.StringAttribute{
-				MarkdownDescription: "Username for authentication",
+				MarkdownDescription: "Username for authentication. May also be set with the `ISARDVDI_USERNAME` environment variable",
 				Optional:            true,
 			},
 			"password": schema.StringAttribute{
-				MarkdownDescription: "Password for authentication",
+				MarkdownDescription: "Password for authentication. May also be set with the `ISARDVDI_PASSWORD` environment variable",
 				Optional:            true,
 				Sensitive:           true,
 			},
 			"ssl_verification": schema.BoolAttribute{
-				MarkdownDescription: "Enable SSL certificate verification. Set to false to disable SSL verification (useful for development with self-signed certificates). Default: true",
+				MarkdownDescription: "Enable SSL certificate verification. Set to false to disable SSL verification (useful for development with self-signed certificates). If unset, `ISARDVDI_INSECURE=true` disables verification. Default: true",
+				Optional:            true,
+			},
+			"request_timeout": schema.StringAttribute{
+				MarkdownDescription: "Maximum duration of a single HTTP request to the API, as a Go duration (e.g. `60s`, `5m`). Each retry gets its own timeout. Default: `60s`",
+				Optional:            true,
+			},
+			"max_retries": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of retries for transient API errors (HTTP 429/502/503/504 and connection resets). POST requests are only retried on refused connections and HTTP 429/503, so creations are never sent twice. Set to 0 to disable retries. Default: 4",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_wait_min": schema.StringAttribute{
+				MarkdownDescription: "Minimum wait between retries, as a Go duration (e.g. `1s`). The wait grows exponentially with jitter up to `retry_wait_max`. A `Retry-After` header from the API takes precedence. Default: `1s`",
+				Optional:            true,
+			},
+			"retry_wait_max": schema.StringAttribute{
+				MarkdownDescription: "Maximum wait between retries, as a Go duration (e.g. `30s`). Default: `30s`",
 				Optional:            true,
 			},
+			"ca_cert_pem": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded CA certificates to trust in addition to the system pool, for installs behind an internal PKI",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("ca_cert_file")),
+				},
+			},
+			"ca_cert_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a PEM file with CA certificates to trust in addition to the system pool",
+				Optional:            true,
+			},
+			"client_cert": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded client certificate for mutual TLS. Requires `client_key`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("client_key")),
+				},
+			},
+			"client_key": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded private key of the client certificate. Requires `client_cert`",
+				Optional:            true,
+				Sensitive:           true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("client_cert")),
+				},
+			},
+			"tls_min_version": schema.StringAttribute{
+				MarkdownDescription: "Minimum TLS version accepted when connecting to the API (`1.0`, `1.1`, `1.2` or `1.3`). Default: `1.2`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("1.0", "1.1", "1.2", "1.3"),
+				},
+			},
 		},
 	}
 }
@@ -104,20 +185,110 @@ func (p *IsardProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		return
 	}
 
+	// Los valores desconocidos (p. ej. dependientes de otro recurso) no se pueden
+	// resolver en Configure
+	for _, attr := range []struct {
+		name  string
+		value types.String
+	}{
+		{"endpoint", data.Endpoint},
+		{"auth_method", data.AuthMethod},
+		{"cathegory_id", data.CathegoryID},
+		{"token", data.Token},
+		{"username", data.Username},
+		{"password", data.Password},
+	} {
+		if attr.value.IsUnknown() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attr.name),
+				"Unknown Configuration Value",
+				fmt.Sprintf("The provider cannot be configured because '%s' is unknown. Set it to a static value or use the corresponding ISARDVDI_* environment variable.", attr.name),
+			)
+		}
+	}
+	if data.SSLVerification.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ssl_verification"),
+			"Unknown Configuration Value",
+			"The provider cannot be configured because 'ssl_verification' is unknown. Set it to a static value or use the ISARDVDI_INSECURE environment variable.",
+		)
+	}
+	// Los ajustes TLS no tienen variable de entorno: un valor desconocido dejaría la
+	// conexión con la configuración TLS por defecto
+	for _, attr := range []struct {
+		name  string
+		value types.String
+	}{
+		{"ca_cert_pem", data.CACertPEM},
+		{"ca_cert_file", data.CACertFile},
+		{"client_cert", data.ClientCert},
+		{"client_key", data.ClientKey},
+		{"tls_min_version", data.TLSMinVersion},
+	} {
+		if attr.value.IsUnknown() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attr.name),
+				"Unknown Configuration Value",
+				fmt.Sprintf("The provider cannot be configured because '%s' is unknown. Set it to a static value.", attr.name),
+			)
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Los valores del bloque provider tienen prioridad sobre las variables de entorno
+	data.Endpoint = stringFromEnv(data.Endpoint, envEndpoint)
+	data.AuthMethod = stringFromEnv(data.AuthMethod, envAuthMethod)
+	data.CathegoryID = stringFromEnv(data.CathegoryID, envCategoryID)
+	data.Token = stringFromEnv(data.Token, envToken)
+	data.Username = stringFromEnv(data.Username, envUsername)
+	data.Password = stringFromEnv(data.Password, envPassword)
+
+	if data.Endpoint.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("endpoint"),
+			"Missing Endpoint",
+			fmt.Sprintf("The provider requires the Isard VDI endpoint. Set 'endpoint' in the provider block or the %s environment variable.", envEndpoint),
+		)
+		return
+	}
+
+	// El validador del esquema no se aplica a los valores leídos del entorno
+	switch data.AuthMethod.ValueString() {
+	case "", "token", "form":
+	default:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("auth_method"),
+			"Invalid Configuration",
+			fmt.Sprintf("%s must be \"token\" or \"form\", got: %q", envAuthMethod, data.AuthMethod.ValueString()),
+		)
+		return
+	}
+
 	if data.CathegoryID.IsNull() {
 		data.CathegoryID = types.StringValue("default")
 	}
 
 	// Default ssl_verification to true if not specified
 	if data.SSLVerification.IsNull() {
-		data.SSLVerification = types.BoolValue(true)
+		insecure, err := boolFromEnv(envInsecure)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("ssl_verification"),
+				"Invalid Configuration",
+				err.Error(),
+			)
+			return
+		}
+		data.SSLVerification = types.BoolValue(!insecure)
 	}
 
 	if data.AuthMethod.ValueString() == "form" {
 		if data.Username.IsNull() || data.Password.IsNull() {
 			resp.Diagnostics.AddError(
 				"Invalid Configuration",
-				"When using 'form' authentication method, both 'username' and 'password' must be provided.",
+				fmt.Sprintf("When using 'form' authentication method, both 'username' and 'password' must be provided (or the %s and %s environment variables).", envUsername, envPassword),
 			)
 			return
 		}
@@ -127,20 +298,84 @@ func (p *IsardProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		if data.Token.IsNull() {
 			resp.Diagnostics.AddError(
 				"Invalid Configuration",
-				"When using 'token' authentication method, 'token' must be provided.",
+				fmt.Sprintf("When using 'token' authentication method, 'token' must be provided (or the %s environment variable).", envToken),
 			)
 			return
 		}
 	}
 
+	requestTimeout := parseDurationAttribute(data.RequestTimeout, "request_timeout", client.DefaultRequestTimeout, &resp.Diagnostics)
+	retryWaitMin := parseDurationAttribute(data.RetryWaitMin, "retry_wait_min", client.DefaultRetryWaitMin, &resp.Diagnostics)
+	retryWaitMax := parseDurationAttribute(data.RetryWaitMax, "retry_wait_max", client.DefaultRetryWaitMax, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if retryWaitMax < retryWaitMin {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("retry_wait_max"),
+			"Invalid Configuration",
+			"'retry_wait_max' must be greater than or equal to 'retry_wait_min'.",
+		)
+		return
+	}
+
+	tlsOptions := client.TLSOptions{
+		InsecureSkipVerify: !data.SSLVerification.ValueBool(),
+		ClientCertPEM:      []byte(data.ClientCert.ValueString()),
+		ClientKeyPEM:       []byte(data.ClientKey.ValueString()),
+		MinVersion:         client.TLSVersions[data.TLSMinVersion.ValueString()],
+	}
+
+	switch {
+	case !data.CACertPEM.IsNull():
+		tlsOptions.CACertPEM = []byte(data.CACertPEM.ValueString())
+	case !data.CACertFile.IsNull():
+		caCert, err := os.ReadFile(data.CACertFile.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("ca_cert_file"),
+				"Invalid Configuration",
+				fmt.Sprintf("Unable to read CA certificate file: %s", err),
+			)
+			return
+		}
+		tlsOptions.CACertPEM = caCert
+	}
+
 	// Configuration values are now available.
 
 	// Create the client
-	c := client.NewClient(data.Endpoint.ValueString(), data.Token.ValueString(), data.SSLVerification.ValueBool())
+	c, err := client.NewClient(data.Endpoint.ValueString(), data.Token.ValueString(), data.SSLVerification.ValueBool())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("endpoint"),
+			"Invalid Configuration",
+			fmt.Sprintf("Unable to parse endpoint: %s", err),
+		)
+		return
+	}
+
+	if err := c.ConfigureTLS(tlsOptions); err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid TLS Configuration",
+			fmt.Sprintf("Unable to configure TLS: %s", err),
+		)
+		return
+	}
+
+	c.HTTPClient.Timeout = requestTimeout
+
+	if !data.MaxRetries.IsNull() {
+		c.MaxRetries = int(data.MaxRetries.ValueInt64())
+	}
+	c.RetryWaitMin = retryWaitMin
+	c.RetryWaitMax = retryWaitMax
 
 	// Authenticate
 	// SignIn manejará "salm" y "form". Si es "token", no hará nada (ya tenemos el token).
-	err := c.SignIn(
+	err = c.SignIn(
+		ctx,
 		data.AuthMethod.ValueString(),
 		data.CathegoryID.ValueString(),
 		data.Username.ValueString(),
@@ -157,6 +392,7 @@ func (p *IsardProvider) Configure(ctx context.Context, req provider.ConfigureReq
 
 	resp.DataSourceData = c
 	resp.ResourceData = c
+	resp.EphemeralResourceData = c
 }
 
 // Resources defines the resources implemented in the provider.
@@ -168,6 +404,20 @@ func (p *IsardProvider) Resources(_ context.Context) []func() resource.Resource
 		NewQoSNetResource,
 		NewNetworkInterfaceResource,
 		NewMediaResource,
+		NewTemplateResource,
+		NewCategoryResource,
+		NewGroupResource,
+		NewGroupEnrollmentResource,
+		NewUserResource,
+		NewUsersBulkResource,
+		NewQuotaResource,
+	}
+}
+
+// EphemeralResources defines the ephemeral resources implemented in the provider.
+func (p *IsardProvider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewDesktopViewerEphemeralResource,
 	}
 }
 
@@ -179,5 +429,58 @@ func (p *IsardProvider) DataSources(_ context.Context) []func() datasource.DataS
 		NewGroupsDataSource,
 		NewUsersDataSource,
 		NewMediasDataSource,
+		NewDesktopDataSource,
+		NewDesktopsDataSource,
+		NewDeploymentDataSource,
+		NewDeploymentDesktopsDataSource,
+		NewCategoriesDataSource,
+		NewRolesDataSource,
+		NewHypervisorsDataSource,
+		NewNetworksDataSource,
+		NewQoSNetsDataSource,
+	}
+}
+
+// parseDurationAttribute interpreta un atributo de duración del provider (p. ej. "30s")
+func parseDurationAttribute(value types.String, name string, defaultValue time.Duration, diags *diag.Diagnostics) time.Duration {
+	if value.IsNull() || value.IsUnknown() {
+		return defaultValue
+	}
+
+	d, err := time.ParseDuration(value.ValueString())
+	if err != nil || d < 0 {
+		diags.AddAttributeError(
+			path.Root(name),
+			"Invalid Configuration",
+			fmt.Sprintf("'%s' must be a valid non-negative duration such as \"30s\" or \"2m\", got: %q", name, value.ValueString()),
+		)
+		return defaultValue
+	}
+
+	return d
+}
+
+// stringFromEnv devuelve el valor configurado o, si no se ha definido, el de la variable de entorno
+func stringFromEnv(value types.String, envVar string) types.String {
+	if !value.IsNull() {
+		return value
+	}
+	if v, ok := os.LookupEnv(envVar); ok && v != "" {
+		return types.StringValue(v)
+	}
+	return value
+}
+
+// boolFromEnv interpreta una variable de entorno booleana; si no está definida devuelve false
+func boolFromEnv(envVar string) (bool, error) {
+	v, ok := os.LookupEnv(envVar)
+	if !ok || v == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("%s must be a boolean value such as \"true\" or \"false\", got: %q", envVar, v)
 	}
+	return b, nil
 }
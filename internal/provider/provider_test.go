@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+	"github.com/tknika/terraform-provider-isardvdi/internal/testserver"
+)
+
+// testAccProtoV6ProviderFactories arranca el provider en el mismo proceso que los tests
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"isardvdi": providerserver.NewProtocol6WithError(New("test")()),
+}
+
+// testAccServer arranca un servidor falso para un test con terraform-plugin-testing
+func testAccServer(t *testing.T) *testserver.Server {
+	t.Helper()
+
+	srv := testserver.New()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// testAccProviderConfig devuelve el bloque provider que apunta al servidor falso, con
+// esperas mínimas entre reintentos
+func testAccProviderConfig(srv *testserver.Server) string {
+	return fmt.Sprintf(`
+provider "isardvdi" {
+  endpoint       = %q
+  auth_method    = "token"
+  token          = %q
+  retry_wait_min = "1ms"
+  retry_wait_max = "5ms"
+}
+`, srv.Endpoint(), testserver.DefaultToken)
+}
+
+// testAccCheckServerDoc comprueba el documento que guarda el servidor falso para el
+// recurso indicado (get lo busca por el ID del estado)
+func testAccCheckServerDoc(resourceName string, get func(id string) (map[string]interface{}, bool), check func(doc map[string]interface{}) error) tfresource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("%s no está en el estado", resourceName)
+		}
+		doc, ok := get(rs.Primary.ID)
+		if !ok {
+			return fmt.Errorf("%s (ID: %s) no existe en el servidor", resourceName, rs.Primary.ID)
+		}
+		return check(doc)
+	}
+}
+
+// testAccCheckDestroyed comprueba que los recursos del tipo indicado ya no existen en el servidor
+func testAccCheckDestroyed(resourceType string, get func(id string) (map[string]interface{}, bool)) tfresource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+			if _, ok := get(rs.Primary.ID); ok {
+				return fmt.Errorf("%s (ID: %s) sigue existiendo en el servidor", resourceType, rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+}
+
+func TestAccProviderFormLogin(t *testing.T) {
+	srv := testAccServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []tfresource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "isardvdi" {
+  endpoint    = %q
+  auth_method = "form"
+  username    = %q
+  password    = %q
+}
+
+data "isardvdi_templates" "all" {}
+`, srv.Endpoint(), testserver.DefaultUsername, testserver.DefaultPassword),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("data.isardvdi_templates.all", "templates.#", "1"),
+					tfresource.TestCheckResourceAttr("data.isardvdi_templates.all", "templates.0.id", testserver.DefaultTemplateID),
+					func(*terraform.State) error {
+						if _, ok := srv.LastRequest(http.MethodPost, "/authentication/login"); !ok {
+							return fmt.Errorf("el provider no ha hecho login con usuario y contraseña")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+// newTestClient arranca un servidor falso y devuelve un cliente con esperas mínimas entre reintentos
+func newTestClient(t *testing.T) (*client.Client, *testserver.Server) {
+	t.Helper()
+
+	srv := testserver.New()
+	t.Cleanup(srv.Close)
+
+	c, err := client.NewClient(srv.Endpoint(), testserver.DefaultToken, true)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	c.RetryWaitMin = time.Millisecond
+	c.RetryWaitMax = 5 * time.Millisecond
+
+	return c, srv
+}
+
+// resourceSchema devuelve el esquema del recurso
+func resourceSchema(t *testing.T, r resource.Resource) resource.SchemaResponse {
+	t.Helper()
+
+	var resp resource.SchemaResponse
+	r.Schema(context.Background(), resource.SchemaRequest{}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Schema: %v", resp.Diagnostics)
+	}
+	return resp
+}
+
+// newState construye un estado del recurso con los atributos indicados; el resto queda a null
+func newState(t *testing.T, r resource.Resource, attrs map[string]interface{}) tfsdk.State {
+	t.Helper()
+	ctx := context.Background()
+
+	s := resourceSchema(t, r).Schema
+	raw, err := types.ObjectNull(s.Type().(types.ObjectType).AttrTypes).ToTerraformValue(ctx)
+	if err != nil {
+		t.Fatalf("ToTerraformValue: %v", err)
+	}
+
+	state := tfsdk.State{Schema: s, Raw: raw}
+	for name, value := range attrs {
+		if diags := state.SetAttribute(ctx, path.Root(name), value); diags.HasError() {
+			t.Fatalf("SetAttribute(%s): %v", name, diags)
+		}
+	}
+	return state
+}
+
+// newPlan construye un plan del recurso con los atributos indicados; el resto queda a null
+func newPlan(t *testing.T, r resource.Resource, attrs map[string]interface{}) tfsdk.Plan {
+	t.Helper()
+
+	state := newState(t, r, attrs)
+	return tfsdk.Plan{Schema: state.Schema, Raw: state.Raw}
+}
+
+// newConfig construye una configuración del recurso con los atributos indicados; el resto queda a null
+func newConfig(t *testing.T, r resource.Resource, attrs map[string]interface{}) tfsdk.Config {
+	t.Helper()
+
+	state := newState(t, r, attrs)
+	return tfsdk.Config{Schema: state.Schema, Raw: state.Raw}
+}
@@ -0,0 +1,295 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &categoryResource{}
+	_ resource.ResourceWithConfigure   = &categoryResource{}
+	_ resource.ResourceWithImportState = &categoryResource{}
+)
+
+// Timeouts por defecto de las operaciones sobre categorías
+const (
+	defaultCategoryCreateTimeout = 5 * time.Minute
+	defaultCategoryReadTimeout   = 5 * time.Minute
+	defaultCategoryUpdateTimeout = 5 * time.Minute
+	defaultCategoryDeleteTimeout = 5 * time.Minute
+)
+
+// NewCategoryResource is a helper function to simplify the provider implementation.
+func NewCategoryResource() resource.Resource {
+	return &categoryResource{}
+}
+
+// categoryResource is the resource implementation.
+type categoryResource struct {
+	client *client.Client
+}
+
+// categoryResourceModel maps the resource schema data.
+type categoryResourceModel struct {
+	ID            types.String   `tfsdk:"id"`
+	Name          types.String   `tfsdk:"name"`
+	Description   types.String   `tfsdk:"description"`
+	Frontend      types.Bool     `tfsdk:"frontend"`
+	CustomURLName types.String   `tfsdk:"custom_url_name"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
+}
+
+// Metadata returns the resource type name.
+func (r *categoryResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_category"
+}
+
+// Schema defines the schema for the resource.
+func (r *categoryResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Gestiona una categoría (organización) de Isard VDI (requiere rol de administrador).",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identificador único de la categoría",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Nombre de la categoría. Debe ser único.",
+			},
+			"description": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Descripción de la categoría",
+			},
+			"frontend": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Si la categoría aparece en el desplegable de la página de login (por defecto: false)",
+			},
+			"custom_url_name": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Nombre para la URL de login propia de la categoría (`/login/<custom_url_name>`)",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *categoryResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *categoryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan categoryResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCategoryCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	categoryID, err := r.client.CreateCategory(
+		ctx,
+		plan.Name.ValueString(),
+		plan.Description.ValueString(),
+		plan.Frontend.ValueBool(),
+		plan.CustomURLName.ValueString(),
+	)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creando categoría",
+			clientErrorDetail("No se pudo crear la categoría "+plan.Name.ValueString(), err),
+		)
+		return
+	}
+
+	plan.ID = types.StringValue(categoryID)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *categoryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state categoryResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultCategoryReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	category, err := r.client.GetCategory(ctx, state.ID.ValueString())
+	if err != nil {
+		if client.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error leyendo categoría",
+			"No se pudo leer la categoría ID "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	state.Name = types.StringValue(category.Name)
+	// description y custom_url_name son opcionales sin valor computado: "" en la API
+	// equivale a no configurarlos
+	if category.Description != "" || !state.Description.IsNull() {
+		state.Description = types.StringValue(category.Description)
+	}
+	if category.CustomURLName != "" || !state.CustomURLName.IsNull() {
+		state.CustomURLName = types.StringValue(category.CustomURLName)
+	}
+	state.Frontend = types.BoolValue(category.Frontend)
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *categoryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state categoryResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultCategoryUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	// Preparar los valores a actualizar (solo los que cambiaron)
+	updateData := map[string]interface{}{}
+
+	if !plan.Name.Equal(state.Name) {
+		updateData["name"] = plan.Name.ValueString()
+	}
+	if !plan.Description.Equal(state.Description) {
+		updateData["description"] = plan.Description.ValueString()
+	}
+	if !plan.Frontend.Equal(state.Frontend) {
+		updateData["frontend"] = plan.Frontend.ValueBool()
+	}
+	if !plan.CustomURLName.Equal(state.CustomURLName) {
+		updateData["custom_url_name"] = plan.CustomURLName.ValueString()
+	}
+
+	if len(updateData) > 0 {
+		err := r.client.UpdateCategory(ctx, plan.ID.ValueString(), updateData)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error actualizando categoría",
+				clientErrorDetail("No se pudo actualizar la categoría ID "+plan.ID.ValueString(), err),
+			)
+			return
+		}
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *categoryResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state categoryResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultCategoryDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	err := r.client.DeleteCategory(ctx, state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error eliminando categoría",
+			clientErrorDetail("No se pudo eliminar la categoría ID "+state.ID.ValueString(), err),
+		)
+		return
+	}
+}
+
+// ImportState importa una categoría existente a partir de su ID.
+func (r *categoryResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccCategoryResource(t *testing.T) {
+	srv := testAccServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed("isardvdi_category", srv.Category),
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + `
+resource "isardvdi_category" "test" {
+  name = "tf-acc-categoria"
+}
+`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttrSet("isardvdi_category.test", "id"),
+					tfresource.TestCheckResourceAttr("isardvdi_category.test", "frontend", "false"),
+					tfresource.TestCheckNoResourceAttr("isardvdi_category.test", "description"),
+					tfresource.TestCheckNoResourceAttr("isardvdi_category.test", "custom_url_name"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+resource "isardvdi_category" "test" {
+  name            = "tf-acc-categoria-renombrada"
+  description     = "Centro de pruebas"
+  frontend        = true
+  custom_url_name = "pruebas"
+}
+`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("isardvdi_category.test", "name", "tf-acc-categoria-renombrada"),
+					tfresource.TestCheckResourceAttr("isardvdi_category.test", "description", "Centro de pruebas"),
+					tfresource.TestCheckResourceAttr("isardvdi_category.test", "frontend", "true"),
+					tfresource.TestCheckResourceAttr("isardvdi_category.test", "custom_url_name", "pruebas"),
+				),
+			},
+			{
+				ResourceName:      "isardvdi_category.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestCategoryReadRemovesDeleted(t *testing.T) {
+	c, _ := newTestClient(t)
+	ctx := context.Background()
+	r := &categoryResource{client: c}
+
+	categoryID, err := c.CreateCategory(ctx, "categoria", "", false, "")
+	if err != nil {
+		t.Fatalf("CreateCategory: %v", err)
+	}
+	if err := c.DeleteCategory(ctx, categoryID); err != nil {
+		t.Fatalf("DeleteCategory: %v", err)
+	}
+
+	state := newState(t, r, map[string]interface{}{"id": categoryID, "name": "categoria", "frontend": false})
+	resp := resource.ReadResponse{State: state}
+	r.Read(ctx, resource.ReadRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", resp.Diagnostics)
+	}
+
+	// Una categoría eliminada fuera de Terraform se quita del estado para recrearla
+	var id types.String
+	resp.State.GetAttribute(ctx, path.Root("id"), &id)
+	if !id.IsNull() {
+		t.Errorf("id en el estado = %s, se esperaba que la categoría se quitara del estado", id)
+	}
+}
@@ -2,26 +2,42 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/float64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/tknika/terraform-provider-isardvdi/internal/client"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &deploymentResource{}
-	_ resource.ResourceWithConfigure = &deploymentResource{}
+	_ resource.Resource                = &deploymentResource{}
+	_ resource.ResourceWithConfigure   = &deploymentResource{}
+	_ resource.ResourceWithImportState = &deploymentResource{}
+	_ resource.ResourceWithModifyPlan  = &deploymentResource{}
+)
+
+// Timeouts por defecto de las operaciones sobre deployments
+const (
+	defaultDeploymentCreateTimeout = 20 * time.Minute
+	defaultDeploymentReadTimeout   = 5 * time.Minute
+	defaultDeploymentUpdateTimeout = 10 * time.Minute
+	defaultDeploymentDeleteTimeout = 10 * time.Minute
 )
 
 // NewDeploymentResource is a helper function to simplify the provider implementation.
@@ -36,21 +52,28 @@ type deploymentResource struct {
 
 // deploymentResourceModel maps the resource schema data.
 type deploymentResourceModel struct {
-	ID                 types.String `tfsdk:"id"`
-	Name               types.String `tfsdk:"name"`
-	Description        types.String `tfsdk:"description"`
-	TemplateID         types.String `tfsdk:"template_id"`
-	DesktopName        types.String `tfsdk:"desktop_name"`
-	Visible            types.Bool   `tfsdk:"visible"`
-	Allowed            types.Object `tfsdk:"allowed"`
-	VCPUs              types.Int64  `tfsdk:"vcpus"`
-	Memory             types.Float64 `tfsdk:"memory"`
-	NetworkInterfaces  types.List   `tfsdk:"network_interfaces"`
-	ISOs               types.List   `tfsdk:"isos"`
-	Floppies           types.List   `tfsdk:"floppies"`
-	UserPermissions    types.List   `tfsdk:"user_permissions"`
-	Viewers            types.List   `tfsdk:"viewers"`
-	ForceStopOnDestroy types.Bool   `tfsdk:"force_stop_on_destroy"`
+	ID                 types.String   `tfsdk:"id"`
+	Name               types.String   `tfsdk:"name"`
+	Description        types.String   `tfsdk:"description"`
+	TemplateID         types.String   `tfsdk:"template_id"`
+	DesktopName        types.String   `tfsdk:"desktop_name"`
+	Visible            types.Bool     `tfsdk:"visible"`
+	Allowed            types.Object   `tfsdk:"allowed"`
+	VCPUs              types.Int64    `tfsdk:"vcpus"`
+	Memory             types.Float64  `tfsdk:"memory"`
+	NetworkInterfaces  types.List     `tfsdk:"network_interfaces"`
+	ISOs               types.List     `tfsdk:"isos"`
+	Floppies           types.List     `tfsdk:"floppies"`
+	UserPermissions    types.List     `tfsdk:"user_permissions"`
+	Viewers            types.List     `tfsdk:"viewers"`
+	BootOrder          types.List     `tfsdk:"boot_order"`
+	ForceStopOnDestroy types.Bool     `tfsdk:"force_stop_on_destroy"`
+	DesiredState       types.String   `tfsdk:"desired_state"`
+	WaitForState       types.Bool     `tfsdk:"wait_for_state"`
+	TotalDesktops      types.Int64    `tfsdk:"total_desktops"`
+	StartedDesktops    types.Int64    `tfsdk:"started_desktops"`
+	CreatingDesktops   types.Int64    `tfsdk:"creating_desktops"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
 }
 
 // Metadata returns the resource type name.
@@ -59,7 +82,12 @@ func (r *deploymentResource) Metadata(_ context.Context, req resource.MetadataRe
 }
 
 // Schema defines the schema for the resource.
-func (r *deploymentResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *deploymentResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	// Un deployment crea un desktop por cada usuario permitido: allowed es obligatorio
+	allowed := allowedSchemaAttribute("Usuarios, grupos, categorías y roles para los que se crean los desktops del deployment. Los criterios sin especificar no dan acceso.")
+	allowed.Optional = false
+	allowed.Required = true
+
 	resp.Schema = schema.Schema{
 		Description: "Gestiona un deployment en Isard VDI. Los deployments permiten crear múltiples desktops a partir de una plantilla para diferentes usuarios.",
 		Attributes: map[string]schema.Attribute{
@@ -78,6 +106,9 @@ func (r *deploymentResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Optional:            true,
 				Computed:            true,
 				MarkdownDescription: "Descripción del deployment (máximo 255 caracteres)",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"template_id": schema.StringAttribute{
 				Required:            true,
@@ -96,32 +127,7 @@ func (r *deploymentResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Default:             booldefault.StaticBool(false),
 				MarkdownDescription: "Si los desktops del deployment son visibles para los usuarios (por defecto: false)",
 			},
-			"allowed": schema.SingleNestedAttribute{
-				Required:            true,
-				MarkdownDescription: "Configuración de usuarios, grupos y categorías permitidos para acceder a este deployment",
-				Attributes: map[string]schema.Attribute{
-					"roles": schema.ListAttribute{
-						ElementType:         types.StringType,
-						Optional:            true,
-						MarkdownDescription: "Lista de roles permitidos",
-					},
-					"categories": schema.ListAttribute{
-						ElementType:         types.StringType,
-						Optional:            true,
-						MarkdownDescription: "Lista de IDs de categorías permitidas",
-					},
-					"groups": schema.ListAttribute{
-						ElementType:         types.StringType,
-						Optional:            true,
-						MarkdownDescription: "Lista de IDs de grupos permitidos",
-					},
-					"users": schema.ListAttribute{
-						ElementType:         types.StringType,
-						Optional:            true,
-						MarkdownDescription: "Lista de IDs de usuarios permitidos",
-					},
-				},
-			},
+			"allowed": allowed,
 			"vcpus": schema.Int64Attribute{
 				Optional:            true,
 				Computed:            true,
@@ -147,12 +153,20 @@ func (r *deploymentResource) Schema(_ context.Context, _ resource.SchemaRequest,
 			"isos": schema.ListAttribute{
 				ElementType:         types.StringType,
 				Optional:            true,
+				Computed:            true,
 				MarkdownDescription: "Lista de IDs de medios ISO a adjuntar a los desktops del deployment",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"floppies": schema.ListAttribute{
 				ElementType:         types.StringType,
 				Optional:            true,
+				Computed:            true,
 				MarkdownDescription: "Lista de IDs de medios floppy a adjuntar a los desktops del deployment",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"user_permissions": schema.ListAttribute{
 				ElementType:         types.StringType,
@@ -162,7 +176,19 @@ func (r *deploymentResource) Schema(_ context.Context, _ resource.SchemaRequest,
 			"viewers": schema.ListAttribute{
 				ElementType:         types.StringType,
 				Optional:            true,
+				Computed:            true,
 				MarkdownDescription: "Lista de viewers habilitados (ej: ['browser_vnc', 'file_spice', 'file_rdpgw', 'browser_rdp']). Si no se especifica, se usan los del template.",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"boot_order": schema.ListAttribute{
+				ElementType:         types.StringType,
+				Computed:            true,
+				MarkdownDescription: "Orden de arranque de los desktops del deployment según la API",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"force_stop_on_destroy": schema.BoolAttribute{
 				Optional:            true,
@@ -170,6 +196,48 @@ func (r *deploymentResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Default:             booldefault.StaticBool(false),
 				MarkdownDescription: "Si es true, detiene todas las máquinas virtuales del deployment antes de eliminarlo (por defecto: false)",
 			},
+			"desired_state": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Estado de energía deseado de los desktops del deployment: `started` o `stopped`. Si no se especifica, Terraform no arranca ni detiene los desktops.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(desiredStateStarted, desiredStateStopped),
+				},
+			},
+			"wait_for_state": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+				MarkdownDescription: "Si es true, espera a que se creen todos los desktops del deployment y alcancen `desired_state` (como máximo el timeout de la operación) (por defecto: true)",
+			},
+			"total_desktops": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Número de desktops del deployment",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"started_desktops": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Número de desktops del deployment en marcha",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"creating_desktops": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Número de desktops del deployment que todavía se están creando",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
@@ -203,71 +271,19 @@ func (r *deploymentResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
-	// Extraer el objeto allowed
-	allowedAttrs := plan.Allowed.Attributes()
-
-	// Construir el mapa allowed para la API
-	// La API espera false para campos no utilizados, no omitirlos
-	allowed := make(map[string]interface{})
-	
-	if rolesAttr, ok := allowedAttrs["roles"]; ok {
-		if roles, ok := rolesAttr.(types.List); ok && !roles.IsNull() {
-			var rolesList []string
-			diags := roles.ElementsAs(ctx, &rolesList, false)
-			resp.Diagnostics.Append(diags...)
-			if !resp.Diagnostics.HasError() {
-				if len(rolesList) > 0 {
-					allowed["roles"] = rolesList
-				} else {
-					allowed["roles"] = false
-				}
-			}
-		}
-	}
-	
-	if categoriesAttr, ok := allowedAttrs["categories"]; ok {
-		if categories, ok := categoriesAttr.(types.List); ok && !categories.IsNull() {
-			var categoriesList []string
-			diags := categories.ElementsAs(ctx, &categoriesList, false)
-			resp.Diagnostics.Append(diags...)
-			if !resp.Diagnostics.HasError() {
-				if len(categoriesList) > 0 {
-					allowed["categories"] = categoriesList
-				} else {
-					allowed["categories"] = false
-				}
-			}
-		}
-	}
-	
-	if groupsAttr, ok := allowedAttrs["groups"]; ok {
-		if groups, ok := groupsAttr.(types.List); ok && !groups.IsNull() {
-			var groupsList []string
-			diags := groups.ElementsAs(ctx, &groupsList, false)
-			resp.Diagnostics.Append(diags...)
-			if !resp.Diagnostics.HasError() {
-				if len(groupsList) > 0 {
-					allowed["groups"] = groupsList
-				} else {
-					allowed["groups"] = false
-				}
-			}
-		}
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultDeploymentCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	
-	if usersAttr, ok := allowedAttrs["users"]; ok {
-		if users, ok := usersAttr.(types.List); ok && !users.IsNull() {
-			var usersList []string
-			diags := users.ElementsAs(ctx, &usersList, false)
-			resp.Diagnostics.Append(diags...)
-			if !resp.Diagnostics.HasError() {
-				if len(usersList) > 0 {
-					allowed["users"] = usersList
-				} else {
-					allowed["users"] = false
-				}
-			}
-		}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	// La API espera false para los criterios no utilizados, no omitirlos
+	allowed := allowedToAPI(ctx, plan.Allowed, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	// Preparar hardware personalizado si se especifica
@@ -289,7 +305,9 @@ func (r *deploymentResource) Create(ctx context.Context, req resource.CreateRequ
 		memory = &m
 	}
 	
+	// Las listas vacías también se envían: quitan las interfaces, medios o viewers del template
 	if !plan.NetworkInterfaces.IsNull() && !plan.NetworkInterfaces.IsUnknown() {
+		interfaces = []string{}
 		diags := plan.NetworkInterfaces.ElementsAs(ctx, &interfaces, false)
 		resp.Diagnostics.Append(diags...)
 		if resp.Diagnostics.HasError() {
@@ -298,6 +316,7 @@ func (r *deploymentResource) Create(ctx context.Context, req resource.CreateRequ
 	}
 	
 	if !plan.ISOs.IsNull() && !plan.ISOs.IsUnknown() {
+		isos = []string{}
 		diags := plan.ISOs.ElementsAs(ctx, &isos, false)
 		resp.Diagnostics.Append(diags...)
 		if resp.Diagnostics.HasError() {
@@ -306,6 +325,7 @@ func (r *deploymentResource) Create(ctx context.Context, req resource.CreateRequ
 	}
 	
 	if !plan.Floppies.IsNull() && !plan.Floppies.IsUnknown() {
+		floppies = []string{}
 		diags := plan.Floppies.ElementsAs(ctx, &floppies, false)
 		resp.Diagnostics.Append(diags...)
 		if resp.Diagnostics.HasError() {
@@ -322,6 +342,7 @@ func (r *deploymentResource) Create(ctx context.Context, req resource.CreateRequ
 	}
 	
 	if !plan.Viewers.IsNull() && !plan.Viewers.IsUnknown() {
+		viewers = []string{}
 		diags := plan.Viewers.ElementsAs(ctx, &viewers, false)
 		resp.Diagnostics.Append(diags...)
 		if resp.Diagnostics.HasError() {
@@ -331,18 +352,15 @@ func (r *deploymentResource) Create(ctx context.Context, req resource.CreateRequ
 
 	// Construir guest_properties si se especifican viewers
 	var guestProperties map[string]interface{}
-	if len(viewers) > 0 {
-		viewersMap := make(map[string]interface{})
-		for _, viewer := range viewers {
-			viewersMap[viewer] = map[string]interface{}{"options": nil}
-		}
+	if viewers != nil {
 		guestProperties = map[string]interface{}{
-			"viewers": viewersMap,
+			"viewers": viewersMap(viewers),
 		}
 	}
 
 	// Crear el deployment usando la API
 	deploymentID, err := r.client.CreateDeployment(
+		ctx,
 		plan.Name.ValueString(),
 		plan.Description.ValueString(),
 		plan.TemplateID.ValueString(),
@@ -361,16 +379,21 @@ func (r *deploymentResource) Create(ctx context.Context, req resource.CreateRequ
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creando el deployment",
-			fmt.Sprintf("No se pudo crear el deployment: %s", err.Error()),
+			clientErrorDetail("No se pudo crear el deployment", err),
 		)
 		return
 	}
 
-	// Actualizar el plan con el ID devuelto por la API
+	// Guardar el ID antes de seguir para no perder el deployment y sus desktops si falla
+	// algún paso posterior (la espera a los desktops puede agotar el timeout de creación)
 	plan.ID = types.StringValue(deploymentID)
+	resp.Diagnostics.Append(setPartialState(ctx, &resp.State, plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	// Obtener los valores finales del deployment creado
-	deployment, err := r.client.GetDeployment(deploymentID)
+	deployment, err := r.client.GetDeployment(ctx, deploymentID)
 	if err == nil {
 		if deployment.Description != "" {
 			plan.Description = types.StringValue(deployment.Description)
@@ -378,9 +401,41 @@ func (r *deploymentResource) Create(ctx context.Context, req resource.CreateRequ
 		plan.Visible = types.BoolValue(deployment.Visible)
 	}
 
-	// Los valores de hardware (vcpus, memory, network_interfaces) ya están en el plan
-	// por los defaults del schema o por los valores especificados por el usuario
-	// No necesitamos leerlos de la API en Create ya que los enviamos nosotros
+	if plan.Description.IsUnknown() {
+		plan.Description = types.StringValue("")
+	}
+
+	// Completar los atributos no configurados (isos, floppies, viewers, boot_order)
+	// con el hardware que la API ha asignado a partir del template
+	settings, err := r.client.GetDeploymentSettings(ctx, deploymentID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error leyendo el deployment creado",
+			fmt.Sprintf("No se pudo leer el hardware del deployment (ID: %s): %s", deploymentID, err.Error()),
+		)
+		return
+	}
+	fillUnknownDeploymentAttributes(&plan, settings.Hardware, settings.Viewers)
+
+	// Esperar a los desktops y llevarlos al estado de energía deseado
+	err = r.applyDesiredState(ctx, deploymentID, plan.DesiredState.ValueString(), plan.WaitForState.ValueBool(), createTimeout)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error esperando a los desktops del deployment",
+			clientErrorDetail(fmt.Sprintf("Los desktops del deployment (ID: %s) no alcanzaron el estado esperado", deploymentID), err),
+		)
+		return
+	}
+
+	deployment, err = r.client.GetDeployment(ctx, deploymentID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error leyendo el deployment creado",
+			fmt.Sprintf("No se pudo leer el deployment (ID: %s): %s", deploymentID, err.Error()),
+		)
+		return
+	}
+	setDeploymentCounters(&plan, deployment)
 
 	// Escribir el estado
 	diags = resp.State.Set(ctx, plan)
@@ -396,11 +451,20 @@ func (r *deploymentResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultDeploymentReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
 	// Obtener el deployment de la API
-	deployment, err := r.client.GetDeployment(state.ID.ValueString())
+	deployment, err := r.client.GetDeployment(ctx, state.ID.ValueString())
 	if err != nil {
 		// Si el deployment no existe (404), eliminarlo del estado
-		if err.Error() == "deployment not found" {
+		if client.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -417,72 +481,36 @@ func (r *deploymentResource) Read(ctx context.Context, req resource.ReadRequest,
 	state.DesktopName = types.StringValue(deployment.DesktopName)
 	state.TemplateID = types.StringValue(deployment.TemplateID)
 	state.Visible = types.BoolValue(deployment.Visible)
+	setDeploymentCounters(&state, deployment)
 
-	// Actualizar allowed
-	if deployment.Allowed != nil {
-		allowedBytes, err := json.Marshal(deployment.Allowed)
-		if err == nil {
-			var allowedMap map[string]interface{}
-			if err := json.Unmarshal(allowedBytes, &allowedMap); err == nil {
-				// Construir el objeto allowed para terraform
-				allowedAttrs := make(map[string]attr.Value)
-				
-				if roles, ok := allowedMap["roles"].([]interface{}); ok {
-					rolesList := make([]attr.Value, len(roles))
-					for i, role := range roles {
-						rolesList[i] = types.StringValue(fmt.Sprintf("%v", role))
-					}
-					allowedAttrs["roles"] = types.ListValueMust(types.StringType, rolesList)
-				} else {
-					allowedAttrs["roles"] = types.ListNull(types.StringType)
-				}
-				
-				if categories, ok := allowedMap["categories"].([]interface{}); ok {
-					categoriesList := make([]attr.Value, len(categories))
-					for i, cat := range categories {
-						categoriesList[i] = types.StringValue(fmt.Sprintf("%v", cat))
-					}
-					allowedAttrs["categories"] = types.ListValueMust(types.StringType, categoriesList)
-				} else {
-					allowedAttrs["categories"] = types.ListNull(types.StringType)
-				}
-				
-				if groups, ok := allowedMap["groups"].([]interface{}); ok {
-					groupsList := make([]attr.Value, len(groups))
-					for i, group := range groups {
-						groupsList[i] = types.StringValue(fmt.Sprintf("%v", group))
-					}
-					allowedAttrs["groups"] = types.ListValueMust(types.StringType, groupsList)
-				} else {
-					allowedAttrs["groups"] = types.ListNull(types.StringType)
-				}
-				
-				if users, ok := allowedMap["users"].([]interface{}); ok {
-					usersList := make([]attr.Value, len(users))
-					for i, user := range users {
-						usersList[i] = types.StringValue(fmt.Sprintf("%v", user))
-					}
-					allowedAttrs["users"] = types.ListValueMust(types.StringType, usersList)
-				} else {
-					allowedAttrs["users"] = types.ListNull(types.StringType)
-				}
-				
-				allowedType := types.ObjectType{
-					AttrTypes: map[string]attr.Type{
-						"roles":      types.ListType{ElemType: types.StringType},
-						"categories": types.ListType{ElemType: types.StringType},
-						"groups":     types.ListType{ElemType: types.StringType},
-						"users":      types.ListType{ElemType: types.StringType},
-					},
-				}
-				state.Allowed = types.ObjectValueMust(allowedType.AttrTypes, allowedAttrs)
-			}
-		}
-	}
+	state.Allowed = allowedFromAPI(state.Allowed, deployment.Allowed)
 
-	// Nota: La API devuelve null para hardware en deployments
-	// Los valores de vcpus, memory y network_interfaces se mantienen del state
-	// ya que son los que se enviaron en la creación
+	// GET /deployment/{id} no incluye el hardware: se lee de deployment/info
+	// para reflejar los cambios hechos fuera de Terraform
+	settings, err := r.client.GetDeploymentSettings(ctx, state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error leyendo el deployment",
+			fmt.Sprintf("No se pudo leer el hardware del deployment (ID: %s): %s", state.ID.ValueString(), err.Error()),
+		)
+		return
+	}
+	if hardware := settings.Hardware; hardware != nil {
+		state.VCPUs = types.Int64Value(hardware.VCPUs)
+		state.Memory = types.Float64Value(hardware.Memory)
+		state.NetworkInterfaces = stringListValue(hardware.Interfaces)
+		state.ISOs = unorderedListValue(ctx, state.ISOs, hardware.ISOs)
+		state.Floppies = unorderedListValue(ctx, state.Floppies, hardware.Floppies)
+		state.BootOrder = stringListValue(hardware.BootOrder)
+	}
+	if settings.Viewers != nil {
+		state.Viewers = unorderedListValue(ctx, state.Viewers, settings.Viewers)
+	}
+	// user_permissions es opcional sin valor computado: una lista vacía en la API
+	// equivale a no configurarlo, salvo que el estado ya tuviera una lista vacía
+	if len(settings.UserPermissions) > 0 || !state.UserPermissions.IsNull() {
+		state.UserPermissions = unorderedListValue(ctx, state.UserPermissions, settings.UserPermissions)
+	}
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -497,77 +525,27 @@ func (r *deploymentResource) Update(ctx context.Context, req resource.UpdateRequ
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultDeploymentUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	// Construir los datos de actualización
 	updateData := make(map[string]interface{})
 	updateData["name"] = plan.Name.ValueString()
 	updateData["description"] = plan.Description.ValueString()
 	updateData["desktop_name"] = plan.DesktopName.ValueString()
 
-	// Extraer el objeto allowed
-	allowedAttrs := plan.Allowed.Attributes()
-
-	// Construir el mapa allowed para la API
-	allowed := make(map[string]interface{})
-	
-	if rolesAttr, ok := allowedAttrs["roles"]; ok {
-		if roles, ok := rolesAttr.(types.List); ok && !roles.IsNull() {
-			var rolesList []string
-			diags := roles.ElementsAs(ctx, &rolesList, false)
-			resp.Diagnostics.Append(diags...)
-			if !resp.Diagnostics.HasError() && len(rolesList) > 0 {
-				allowed["roles"] = rolesList
-			}
-		}
-	}
-	
-	if categoriesAttr, ok := allowedAttrs["categories"]; ok {
-		if categories, ok := categoriesAttr.(types.List); ok && !categories.IsNull() {
-			var categoriesList []string
-			diags := categories.ElementsAs(ctx, &categoriesList, false)
-			resp.Diagnostics.Append(diags...)
-			if !resp.Diagnostics.HasError() && len(categoriesList) > 0 {
-				allowed["categories"] = categoriesList
-			}
-		}
-	}
-	
-	if groupsAttr, ok := allowedAttrs["groups"]; ok {
-		if groups, ok := groupsAttr.(types.List); ok && !groups.IsNull() {
-			var groupsList []string
-			diags := groups.ElementsAs(ctx, &groupsList, false)
-			resp.Diagnostics.Append(diags...)
-			if !resp.Diagnostics.HasError() && len(groupsList) > 0 {
-				allowed["groups"] = groupsList
-			}
-		}
-	}
-	
-	if usersAttr, ok := allowedAttrs["users"]; ok {
-		if users, ok := usersAttr.(types.List); ok && !users.IsNull() {
-			var usersList []string
-			diags := users.ElementsAs(ctx, &usersList, false)
-			resp.Diagnostics.Append(diags...)
-			if !resp.Diagnostics.HasError() && len(usersList) > 0 {
-				allowed["users"] = usersList
-			}
-		}
-	}
-
-	updateData["allowed"] = allowed
+	updateData["allowed"] = allowedToAPI(ctx, plan.Allowed, &resp.Diagnostics)
 
-	// Actualizar guest_properties si se especifican viewers
+	// Actualizar guest_properties si se especifican viewers (una lista vacía los quita todos)
 	if !plan.Viewers.IsNull() && !plan.Viewers.IsUnknown() {
-		var viewers []string
-		diags := plan.Viewers.ElementsAs(ctx, &viewers, false)
-		resp.Diagnostics.Append(diags...)
-		if !resp.Diagnostics.HasError() && len(viewers) > 0 {
-			viewersMap := make(map[string]interface{})
-			for _, viewer := range viewers {
-				viewersMap[viewer] = map[string]interface{}{"options": nil}
-			}
-			updateData["guest_properties"] = map[string]interface{}{
-				"viewers": viewersMap,
-			}
+		updateData["guest_properties"] = map[string]interface{}{
+			"viewers": viewersMap(listToStrings(ctx, plan.Viewers, &resp.Diagnostics)),
 		}
 	}
 
@@ -583,24 +561,39 @@ func (r *deploymentResource) Update(ctx context.Context, req resource.UpdateRequ
 			hardware["memory"] = plan.Memory.ValueFloat64()
 		}
 		
+		// Mismo formato que en la creación: lista de IDs
 		if !plan.NetworkInterfaces.IsNull() && !plan.NetworkInterfaces.IsUnknown() {
-			var interfaces []string
-			diags := plan.NetworkInterfaces.ElementsAs(ctx, &interfaces, false)
-			resp.Diagnostics.Append(diags...)
-			if !resp.Diagnostics.HasError() && len(interfaces) > 0 {
-				interfacesList := make([]map[string]interface{}, len(interfaces))
-				for i, iface := range interfaces {
-					interfacesList[i] = map[string]interface{}{"id": iface}
-				}
-				hardware["interfaces"] = interfacesList
+			interfaces := listToStrings(ctx, plan.NetworkInterfaces, &resp.Diagnostics)
+			if interfaces == nil {
+				interfaces = []string{}
 			}
+			hardware["interfaces"] = interfaces
+		}
+
+		// El hardware se sustituye completo: se envían también los medios para no perderlos
+		if !plan.ISOs.IsUnknown() {
+			hardware["isos"] = mediaRefs(listToStrings(ctx, plan.ISOs, &resp.Diagnostics))
+		}
+		if !plan.Floppies.IsUnknown() {
+			hardware["floppies"] = mediaRefs(listToStrings(ctx, plan.Floppies, &resp.Diagnostics))
 		}
 		
 		updateData["hardware"] = hardware
 	}
 
+	// Mismo formato que en la creación: sin user_permissions se envía una lista vacía
+	userPermissions := listToStrings(ctx, plan.UserPermissions, &resp.Diagnostics)
+	if userPermissions == nil {
+		userPermissions = []string{}
+	}
+	updateData["user_permissions"] = userPermissions
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Actualizar el deployment usando la API
-	err := r.client.UpdateDeployment(plan.ID.ValueString(), updateData)
+	err := r.client.UpdateDeployment(ctx, plan.ID.ValueString(), updateData)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error actualizando el deployment",
@@ -609,6 +602,31 @@ func (r *deploymentResource) Update(ctx context.Context, req resource.UpdateRequ
 		return
 	}
 
+	// Llevar los desktops al estado de energía deseado
+	if !plan.DesiredState.IsNull() {
+		err := r.applyDesiredState(ctx, plan.ID.ValueString(), plan.DesiredState.ValueString(), plan.WaitForState.ValueBool(), updateTimeout)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error aplicando el estado del deployment",
+				clientErrorDetail(fmt.Sprintf("Los desktops del deployment (ID: %s) no alcanzaron el estado %q", plan.ID.ValueString(), plan.DesiredState.ValueString()), err),
+			)
+			return
+		}
+	}
+
+	// Los contadores solo se recalculan si el plan los marcó como desconocidos
+	if plan.StartedDesktops.IsUnknown() {
+		deployment, err := r.client.GetDeployment(ctx, plan.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error leyendo el deployment",
+				fmt.Sprintf("No se pudo leer el deployment (ID: %s): %s", plan.ID.ValueString(), err.Error()),
+			)
+			return
+		}
+		plan.StartedDesktops = types.Int64Value(int64(deployment.StartedDesktops))
+	}
+
 	// Escribir el estado
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -623,17 +641,27 @@ func (r *deploymentResource) Delete(ctx context.Context, req resource.DeleteRequ
 		return
 	}
 
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeploymentDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	// Si force_stop_on_destroy es true, detener todas las VMs del deployment primero
 	if !state.ForceStopOnDestroy.IsNull() && state.ForceStopOnDestroy.ValueBool() {
-		err := r.client.StopDeployment(state.ID.ValueString())
+		err := r.client.StopDeployment(ctx, state.ID.ValueString())
 		if err != nil {
 			resp.Diagnostics.AddWarning(
 				"Advertencia al detener desktops del deployment",
 				fmt.Sprintf("No se pudieron detener todos los desktops del deployment (ID: %s): %s. Se procederá con la eliminación.", state.ID.ValueString(), err.Error()),
 			)
 		} else {
-			// Esperar a que las VMs se detengan completamente (máximo 120 segundos)
-			err = r.client.WaitForDeploymentStopped(state.ID.ValueString(), 120)
+			// Esperar a que las VMs se detengan completamente como máximo la mitad del timeout
+			// de delete, para que quede tiempo para eliminar el deployment
+			err = r.client.WaitForDeploymentStopped(ctx, state.ID.ValueString(), deleteTimeout/2)
 			if err != nil {
 				resp.Diagnostics.AddWarning(
 					"Advertencia al esperar el stop de desktops",
@@ -643,8 +671,10 @@ func (r *deploymentResource) Delete(ctx context.Context, req resource.DeleteRequ
 		}
 	}
 
-	// Eliminar el deployment usando la API (permanent=true)
-	err := r.client.DeleteDeployment(state.ID.ValueString(), true)
+	// Eliminar el deployment usando la API (permanent=true). Si hay que detener los
+	// desktops, la espera se limita a lo que queda del timeout de delete.
+	deadline, _ := ctx.Deadline()
+	err := r.client.DeleteDeployment(ctx, state.ID.ValueString(), true, time.Until(deadline))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error eliminando el deployment",
@@ -655,3 +685,116 @@ func (r *deploymentResource) Delete(ctx context.Context, req resource.DeleteRequ
 
 	// El estado se elimina automáticamente si la función termina sin errores
 }
+
+// ModifyPlan marca started_desktops como desconocido cuando los desktops no están en el
+// desired_state configurado, de forma que el plan muestre el cambio y Update los arranque o detenga.
+func (r *deploymentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nada que hacer en la creación ni en la destrucción
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var desiredState types.String
+	var total, started, creating types.Int64
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("desired_state"), &desiredState)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("total_desktops"), &total)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("started_desktops"), &started)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("creating_desktops"), &creating)...)
+	if resp.Diagnostics.HasError() || desiredState.IsNull() || desiredState.IsUnknown() {
+		return
+	}
+
+	inState := true
+	switch desiredState.ValueString() {
+	case desiredStateStarted:
+		inState = creating.ValueInt64() == 0 && started.ValueInt64() >= total.ValueInt64()
+	case desiredStateStopped:
+		inState = started.ValueInt64() == 0
+	}
+
+	if !inState {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("started_desktops"), types.Int64Unknown())...)
+	}
+}
+
+// applyDesiredState arranca o detiene los desktops del deployment según desiredState (vacío si
+// no se gestiona el estado de energía). Si wait es true espera, como máximo maxWait, a que se
+// creen todos los desktops y alcancen el estado. Antes de arrancarlos se espera siempre a que
+// terminen de crearse.
+func (r *deploymentResource) applyDesiredState(ctx context.Context, deploymentID, desiredState string, wait bool, maxWait time.Duration) error {
+	switch desiredState {
+	case desiredStateStarted:
+		if err := r.client.WaitForDeploymentCreated(ctx, deploymentID, maxWait); err != nil {
+			return err
+		}
+		deployment, err := r.client.GetDeployment(ctx, deploymentID)
+		if err != nil {
+			return err
+		}
+		if deployment.StartedDesktops < deployment.TotalDesktops {
+			if err := r.client.StartDeployment(ctx, deploymentID); err != nil {
+				return err
+			}
+		}
+		if wait {
+			return r.client.WaitForDeploymentStarted(ctx, deploymentID, maxWait)
+		}
+
+	case desiredStateStopped:
+		deployment, err := r.client.GetDeployment(ctx, deploymentID)
+		if err != nil {
+			return err
+		}
+		if deployment.StartedDesktops > 0 {
+			if err := r.client.StopDeployment(ctx, deploymentID); err != nil {
+				return err
+			}
+		}
+		if wait {
+			return r.client.WaitForDeploymentStopped(ctx, deploymentID, maxWait)
+		}
+
+	default:
+		if wait {
+			return r.client.WaitForDeploymentCreated(ctx, deploymentID, maxWait)
+		}
+	}
+
+	return nil
+}
+
+// setDeploymentCounters copia en el modelo los contadores de desktops del deployment
+func setDeploymentCounters(model *deploymentResourceModel, deployment *client.DeploymentInfo) {
+	model.TotalDesktops = types.Int64Value(int64(deployment.TotalDesktops))
+	model.StartedDesktops = types.Int64Value(int64(deployment.StartedDesktops))
+	model.CreatingDesktops = types.Int64Value(int64(deployment.CreatingDesktops))
+}
+
+// fillUnknownDeploymentAttributes completa los atributos computados que siguen desconocidos
+// tras crear el deployment con el hardware devuelto por la API
+func fillUnknownDeploymentAttributes(model *deploymentResourceModel, hardware *client.Hardware, viewers []string) {
+	if hardware == nil {
+		hardware = &client.Hardware{}
+	}
+
+	if model.ISOs.IsUnknown() {
+		model.ISOs = stringListValue(hardware.ISOs)
+	}
+	if model.Floppies.IsUnknown() {
+		model.Floppies = stringListValue(hardware.Floppies)
+	}
+	if model.BootOrder.IsUnknown() {
+		model.BootOrder = stringListValue(hardware.BootOrder)
+	}
+	if model.Viewers.IsUnknown() {
+		model.Viewers = stringListValue(viewers)
+	}
+}
+
+// ImportState importa un deployment existente a partir de su ID. Los atributos que solo
+// controlan el comportamiento del provider toman su valor por defecto.
+func (r *deploymentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("force_stop_on_destroy"), false)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("wait_for_state"), true)...)
+}
@@ -0,0 +1,221 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/tknika/terraform-provider-isardvdi/internal/testserver"
+)
+
+func TestAccDeploymentResource(t *testing.T) {
+	srv := testAccServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed("isardvdi_deployment", srv.Deployment),
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + testAccDeploymentConfig("tf-acc-deployment", `
+  memory = 1.5
+  isos   = ["iso-ubuntu"]
+`),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttrSet("isardvdi_deployment.test", "id"),
+					tfresource.TestCheckResourceAttr("isardvdi_deployment.test", "memory", "1.5"),
+					tfresource.TestCheckResourceAttr("isardvdi_deployment.test", "isos.#", "1"),
+					tfresource.TestCheckResourceAttr("isardvdi_deployment.test", "floppies.#", "0"),
+					tfresource.TestCheckNoResourceAttr("isardvdi_deployment.test", "user_permissions"),
+					tfresource.TestCheckResourceAttr("isardvdi_deployment.test", "total_desktops", "1"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(srv) + testAccDeploymentConfig("tf-acc-deployment", `
+  description      = "Actualizado"
+  memory           = 3
+  isos             = ["iso-ubuntu", "iso-drivers"]
+  floppies         = ["floppy-1"]
+  user_permissions = ["viewer", "desktop_start"]
+`),
+				ConfigPlanChecks: tfresource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("isardvdi_deployment.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("isardvdi_deployment.test", "description", "Actualizado"),
+					tfresource.TestCheckResourceAttr("isardvdi_deployment.test", "memory", "3"),
+					tfresource.TestCheckResourceAttr("isardvdi_deployment.test", "isos.#", "2"),
+					tfresource.TestCheckResourceAttr("isardvdi_deployment.test", "floppies.0", "floppy-1"),
+					tfresource.TestCheckResourceAttr("isardvdi_deployment.test", "user_permissions.#", "2"),
+					testAccCheckServerDoc("isardvdi_deployment.test", srv.Deployment, func(doc map[string]interface{}) error {
+						hardware, _ := doc["hardware"].(map[string]interface{})
+						if isos, _ := hardware["isos"].([]interface{}); len(isos) != 2 {
+							return fmt.Errorf("isos en el servidor = %v, se esperaban 2", hardware["isos"])
+						}
+						if perms, _ := doc["user_permissions"].([]interface{}); len(perms) != 2 {
+							return fmt.Errorf("user_permissions en el servidor = %v, se esperaban 2", doc["user_permissions"])
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				// Quitar los medios y los permisos
+				Config: testAccProviderConfig(srv) + testAccDeploymentConfig("tf-acc-deployment", `
+  description      = "Actualizado"
+  memory           = 3
+  isos             = []
+  floppies         = []
+  user_permissions = []
+`),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("isardvdi_deployment.test", "isos.#", "0"),
+					tfresource.TestCheckResourceAttr("isardvdi_deployment.test", "floppies.#", "0"),
+					tfresource.TestCheckResourceAttr("isardvdi_deployment.test", "user_permissions.#", "0"),
+				),
+			},
+			{
+				ResourceName:      "isardvdi_deployment.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccDeploymentResourceEmptyLists(t *testing.T) {
+	srv := testAccServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed("isardvdi_deployment", srv.Deployment),
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + testAccDeploymentConfig("tf-acc-deployment", `
+  viewers            = []
+  network_interfaces = []
+`),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("isardvdi_deployment.test", "viewers.#", "0"),
+					tfresource.TestCheckResourceAttr("isardvdi_deployment.test", "network_interfaces.#", "0"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(srv) + testAccDeploymentConfig("tf-acc-deployment", `
+  viewers = ["file_spice"]
+`),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("isardvdi_deployment.test", "viewers.#", "1"),
+					tfresource.TestCheckResourceAttr("isardvdi_deployment.test", "network_interfaces.#", "2"),
+				),
+			},
+			{
+				// Vaciar las listas en una actualización
+				Config: testAccProviderConfig(srv) + testAccDeploymentConfig("tf-acc-deployment", `
+  viewers            = []
+  network_interfaces = []
+`),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("isardvdi_deployment.test", "viewers.#", "0"),
+					tfresource.TestCheckResourceAttr("isardvdi_deployment.test", "network_interfaces.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestDeploymentCreateKeepsIDOnFailure(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+	r := &deploymentResource{client: c}
+
+	srv.FailNext(http.MethodGet, "/api/v3/deployment/info/", http.StatusBadRequest, 1)
+
+	req := resource.CreateRequest{Plan: newPlan(t, r, map[string]interface{}{
+		"name":           "tf-deployment",
+		"template_id":    testserver.DefaultTemplateID,
+		"desktop_name":   "Escritorio",
+		"total_desktops": types.Int64Unknown(),
+	})}
+	resp := resource.CreateResponse{State: newState(t, r, nil)}
+
+	r.Create(ctx, req, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("se esperaba un error al leer el deployment creado")
+	}
+
+	// El deployment y sus desktops ya existen: deben quedar en el estado como tainted
+	var id types.String
+	var total types.Int64
+	resp.State.GetAttribute(ctx, path.Root("id"), &id)
+	resp.State.GetAttribute(ctx, path.Root("total_desktops"), &total)
+	if _, ok := srv.Deployment(id.ValueString()); !ok {
+		t.Fatalf("id en el estado = %s, se esperaba el del deployment creado", id)
+	}
+	if !total.IsNull() {
+		t.Errorf("total_desktops = %s, se esperaba null en el estado parcial", total)
+	}
+}
+
+func TestDeploymentDeleteAfterStopTimeout(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+	r := &deploymentResource{client: c}
+
+	allowed := map[string]interface{}{
+		"roles":      false,
+		"categories": false,
+		"groups":     false,
+		"users":      []string{testserver.DefaultUserID},
+	}
+	id, err := c.CreateDeployment(ctx, "tf-deployment", "", testserver.DefaultTemplateID, "Escritorio", false, allowed, nil, nil, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateDeployment: %v", err)
+	}
+	// El desktop no se detiene durante la espera de force_stop_on_destroy
+	for _, desktopID := range srv.DeploymentDesktopIDs(id) {
+		srv.SetDesktopStatus(desktopID, "Started")
+		srv.HoldDesktopStatus(desktopID, 1)
+	}
+
+	state := newState(t, r, map[string]interface{}{"id": id, "force_stop_on_destroy": true})
+	if diags := state.SetAttribute(ctx, path.Root("timeouts").AtName("delete"), "1s"); diags.HasError() {
+		t.Fatalf("SetAttribute(timeouts): %v", diags)
+	}
+	resp := resource.DeleteResponse{State: state}
+
+	// Tras agotar su parte del timeout queda tiempo para que la eliminación espere a los desktops
+	r.Delete(ctx, resource.DeleteRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Delete: %v", resp.Diagnostics)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("Delete = %v, se esperaba un aviso por el timeout de la parada", resp.Diagnostics)
+	}
+	if _, ok := srv.Deployment(id); ok {
+		t.Error("el deployment sigue existiendo")
+	}
+}
+
+// testAccDeploymentConfig devuelve un isardvdi_deployment para el usuario administrador del
+// servidor falso con los argumentos adicionales indicados
+func testAccDeploymentConfig(name, extra string) string {
+	return fmt.Sprintf(`
+resource "isardvdi_deployment" "test" {
+  name         = %q
+  template_id  = %q
+  desktop_name = "Escritorio"
+
+  allowed = {
+    users = [%q]
+  }
+%s}
+`, name, testserver.DefaultTemplateID, testserver.DefaultUserID, extra)
+}
@@ -0,0 +1,360 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &groupResource{}
+	_ resource.ResourceWithConfigure   = &groupResource{}
+	_ resource.ResourceWithImportState = &groupResource{}
+)
+
+// Timeouts por defecto de las operaciones sobre grupos
+const (
+	defaultGroupCreateTimeout = 5 * time.Minute
+	defaultGroupReadTimeout   = 5 * time.Minute
+	defaultGroupUpdateTimeout = 5 * time.Minute
+	defaultGroupDeleteTimeout = 5 * time.Minute
+)
+
+// NewGroupResource is a helper function to simplify the provider implementation.
+func NewGroupResource() resource.Resource {
+	return &groupResource{}
+}
+
+// groupResource is the resource implementation.
+type groupResource struct {
+	client *client.Client
+}
+
+// groupResourceModel maps the resource schema data.
+type groupResourceModel struct {
+	ID             types.String   `tfsdk:"id"`
+	Name           types.String   `tfsdk:"name"`
+	Description    types.String   `tfsdk:"description"`
+	ParentCategory types.String   `tfsdk:"parent_category"`
+	LinkedGroups   types.List     `tfsdk:"linked_groups"`
+	Enrollment     types.Map      `tfsdk:"enrollment"`
+	Timeouts       timeouts.Value `tfsdk:"timeouts"`
+}
+
+// Metadata returns the resource type name.
+func (r *groupResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_group"
+}
+
+// Schema defines the schema for the resource.
+func (r *groupResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Gestiona un grupo de usuarios de Isard VDI (requiere rol de administrador o manager).",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identificador único del grupo",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Nombre del grupo. Debe ser único dentro de la categoría.",
+			},
+			"description": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Descripción del grupo",
+			},
+			"parent_category": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "ID de la categoría a la que pertenece el grupo. Cambiarlo recrea el grupo.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"linked_groups": schema.ListAttribute{
+				ElementType:         types.StringType,
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "IDs de grupos vinculados: sus usuarios también acceden a los recursos compartidos con este grupo (el orden no es significativo)",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"enrollment": schema.MapAttribute{
+				ElementType:         types.StringType,
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "Códigos de auto-registro activos del grupo por rol (`manager`, `advanced`, `user`). Los roles sin código no aparecen.",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *groupResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *groupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan groupResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultGroupCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	linkedGroups := listToStrings(ctx, plan.LinkedGroups, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	groupID, err := r.client.CreateGroup(
+		ctx,
+		plan.Name.ValueString(),
+		plan.Description.ValueString(),
+		plan.ParentCategory.ValueString(),
+		linkedGroups,
+	)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creando grupo",
+			clientErrorDetail("No se pudo crear el grupo "+plan.Name.ValueString(), err),
+		)
+		return
+	}
+
+	// Guardar el ID antes de leer para no perder el grupo si la lectura falla
+	plan.ID = types.StringValue(groupID)
+
+	group, err := r.client.GetGroup(ctx, groupID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error leyendo grupo creado",
+			"No se pudo leer el grupo recién creado: "+err.Error(),
+		)
+		plan.LinkedGroups = stringListValue(linkedGroups)
+		plan.Enrollment = types.MapNull(types.StringType)
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		return
+	}
+
+	setGroupComputed(ctx, &plan, group)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *groupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state groupResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultGroupReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	group, err := r.client.GetGroup(ctx, state.ID.ValueString())
+	if err != nil {
+		if client.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error leyendo grupo",
+			"No se pudo leer el grupo ID "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	state.Name = types.StringValue(group.Name)
+	// description es opcional sin valor computado: "" en la API equivale a no configurarla
+	if group.Description != "" || !state.Description.IsNull() {
+		state.Description = types.StringValue(group.Description)
+	}
+	state.ParentCategory = types.StringValue(group.ParentCategory)
+	setGroupComputed(ctx, &state, group)
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *groupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state groupResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultGroupUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	// Preparar los valores a actualizar (solo los que cambiaron)
+	updateData := map[string]interface{}{}
+
+	if !plan.Name.Equal(state.Name) {
+		updateData["name"] = plan.Name.ValueString()
+	}
+	if !plan.Description.Equal(state.Description) {
+		updateData["description"] = plan.Description.ValueString()
+	}
+	if !plan.LinkedGroups.IsUnknown() && !plan.LinkedGroups.Equal(state.LinkedGroups) {
+		linkedGroups := listToStrings(ctx, plan.LinkedGroups, &resp.Diagnostics)
+		if linkedGroups == nil {
+			linkedGroups = []string{}
+		}
+		updateData["linked_groups"] = linkedGroups
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if len(updateData) > 0 {
+		err := r.client.UpdateGroup(ctx, plan.ID.ValueString(), updateData)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error actualizando grupo",
+				clientErrorDetail("No se pudo actualizar el grupo ID "+plan.ID.ValueString(), err),
+			)
+			return
+		}
+	}
+
+	group, err := r.client.GetGroup(ctx, plan.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error leyendo grupo actualizado",
+			"No se pudo leer el grupo actualizado: "+err.Error(),
+		)
+		return
+	}
+
+	setGroupComputed(ctx, &plan, group)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *groupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state groupResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultGroupDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	err := r.client.DeleteGroup(ctx, state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error eliminando grupo",
+			clientErrorDetail("No se pudo eliminar el grupo ID "+state.ID.ValueString(), err),
+		)
+		return
+	}
+}
+
+// ImportState importa un grupo existente a partir de su ID.
+func (r *groupResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// setGroupComputed rellena linked_groups y enrollment a partir del grupo leído de la API
+func setGroupComputed(ctx context.Context, model *groupResourceModel, group *client.Group) {
+	model.LinkedGroups = unorderedListValue(ctx, model.LinkedGroups, group.LinkedGroups)
+	model.Enrollment = enrollmentCodesValue(group.EnrollmentCodes())
+}
+
+// enrollmentCodesValue convierte los códigos de auto-registro por rol en un mapa de Terraform
+func enrollmentCodesValue(codes map[string]string) types.Map {
+	elems := make(map[string]attr.Value, len(codes))
+	for role, code := range codes {
+		elems[role] = types.StringValue(code)
+	}
+	return types.MapValueMust(types.StringType, elems)
+}
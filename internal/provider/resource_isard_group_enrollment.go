@@ -0,0 +1,378 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &groupEnrollmentResource{}
+	_ resource.ResourceWithConfigure   = &groupEnrollmentResource{}
+	_ resource.ResourceWithImportState = &groupEnrollmentResource{}
+	_ resource.ResourceWithModifyPlan  = &groupEnrollmentResource{}
+)
+
+// Timeouts por defecto de las operaciones sobre códigos de auto-registro
+const (
+	defaultGroupEnrollmentCreateTimeout = 5 * time.Minute
+	defaultGroupEnrollmentReadTimeout   = 5 * time.Minute
+	defaultGroupEnrollmentUpdateTimeout = 5 * time.Minute
+	defaultGroupEnrollmentDeleteTimeout = 5 * time.Minute
+)
+
+// NewGroupEnrollmentResource is a helper function to simplify the provider implementation.
+func NewGroupEnrollmentResource() resource.Resource {
+	return &groupEnrollmentResource{}
+}
+
+// groupEnrollmentResource is the resource implementation.
+type groupEnrollmentResource struct {
+	client *client.Client
+}
+
+// groupEnrollmentResourceModel maps the resource schema data.
+type groupEnrollmentResourceModel struct {
+	ID              types.String   `tfsdk:"id"`
+	GroupID         types.String   `tfsdk:"group_id"`
+	Manager         types.Bool     `tfsdk:"manager"`
+	Advanced        types.Bool     `tfsdk:"advanced"`
+	User            types.Bool     `tfsdk:"user"`
+	RotationVersion types.Int64    `tfsdk:"rotation_version"`
+	Codes           types.Map      `tfsdk:"codes"`
+	Timeouts        timeouts.Value `tfsdk:"timeouts"`
+}
+
+// Metadata returns the resource type name.
+func (r *groupEnrollmentResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_group_enrollment"
+}
+
+// Schema defines the schema for the resource.
+func (r *groupEnrollmentResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	roleAttribute := func(role string) schema.BoolAttribute {
+		return schema.BoolAttribute{
+			Optional:            true,
+			Computed:            true,
+			Default:             booldefault.StaticBool(false),
+			MarkdownDescription: "Si el grupo tiene código de auto-registro para el rol `" + role + "` (por defecto: false)",
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Gestiona los códigos de auto-registro de un grupo de Isard VDI por rol (requiere rol de administrador o manager).",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identificador del recurso (igual a `group_id`)",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"group_id": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "ID del grupo. Cambiarlo recrea el recurso.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"manager":  roleAttribute("manager"),
+			"advanced": roleAttribute("advanced"),
+			"user":     roleAttribute("user"),
+			"rotation_version": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Versión de los códigos. Cambiar este valor genera códigos nuevos para todos los roles activos e invalida los anteriores.",
+			},
+			"codes": schema.MapAttribute{
+				ElementType:         types.StringType,
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "Códigos de auto-registro activos, indexados por rol (`manager`, `advanced`, `user`)",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *groupEnrollmentResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *groupEnrollmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan groupEnrollmentResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultGroupEnrollmentCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	group, err := r.client.GetGroup(ctx, plan.GroupID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error leyendo grupo",
+			clientErrorDetail("No se pudo leer el grupo ID "+plan.GroupID.ValueString(), err),
+		)
+		return
+	}
+
+	// Los códigos que ya existían para los roles activos se conservan
+	r.applyEnrollment(ctx, &plan, group, false, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = plan.GroupID
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *groupEnrollmentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state groupEnrollmentResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultGroupEnrollmentReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	group, err := r.client.GetGroup(ctx, state.GroupID.ValueString())
+	if err != nil {
+		if client.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error leyendo códigos de auto-registro",
+			"No se pudo leer el grupo ID "+state.GroupID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	setEnrollmentCodes(&state, group.EnrollmentCodes())
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *groupEnrollmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state groupEnrollmentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultGroupEnrollmentUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	group, err := r.client.GetGroup(ctx, plan.GroupID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error leyendo grupo",
+			clientErrorDetail("No se pudo leer el grupo ID "+plan.GroupID.ValueString(), err),
+		)
+		return
+	}
+
+	rotate := !plan.RotationVersion.Equal(state.RotationVersion)
+	r.applyEnrollment(ctx, &plan, group, rotate, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *groupEnrollmentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state groupEnrollmentResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultGroupEnrollmentDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	group, err := r.client.GetGroup(ctx, state.GroupID.ValueString())
+	if err != nil {
+		// Un grupo que ya no existe tampoco tiene códigos
+		if client.IsNotFound(err) {
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error leyendo grupo",
+			clientErrorDetail("No se pudo leer el grupo ID "+state.GroupID.ValueString(), err),
+		)
+		return
+	}
+
+	// Eliminar el recurso desactiva todos los códigos del grupo
+	state.Manager = types.BoolValue(false)
+	state.Advanced = types.BoolValue(false)
+	state.User = types.BoolValue(false)
+	r.applyEnrollment(ctx, &state, group, false, &resp.Diagnostics)
+}
+
+// ModifyPlan marca codes como desconocido solo cuando cambian: al activar o desactivar un rol
+// o al cambiar rotation_version. En el resto de actualizaciones se conservan los del estado.
+func (r *groupEnrollmentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nada que hacer en la creación ni en la destrucción
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state groupEnrollmentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Manager.Equal(state.Manager) || !plan.Advanced.Equal(state.Advanced) || !plan.User.Equal(state.User) ||
+		!plan.RotationVersion.Equal(state.RotationVersion) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("codes"), types.MapUnknown(types.StringType))...)
+	}
+}
+
+// ImportState importa los códigos de auto-registro de un grupo a partir de su ID.
+func (r *groupEnrollmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("group_id"), req.ID)...)
+}
+
+// applyEnrollment genera los códigos de los roles activos que no tienen (o todos si rotate)
+// y elimina los de los roles inactivos. Después rellena codes con los códigos del grupo.
+func (r *groupEnrollmentResource) applyEnrollment(ctx context.Context, model *groupEnrollmentResourceModel, group *client.Group, rotate bool, diags *diag.Diagnostics) {
+	groupID := model.GroupID.ValueString()
+
+	codes := group.EnrollmentCodes()
+	wanted := map[string]bool{
+		"manager":  model.Manager.ValueBool(),
+		"advanced": model.Advanced.ValueBool(),
+		"user":     model.User.ValueBool(),
+	}
+
+	for _, role := range client.EnrollmentRoles {
+		_, enabled := codes[role]
+
+		switch {
+		case wanted[role] && (!enabled || rotate):
+			code, err := r.client.ResetGroupEnrollment(ctx, groupID, role)
+			if err != nil {
+				diags.AddError(
+					"Error generando código de auto-registro",
+					clientErrorDetail("No se pudo generar el código del rol "+role+" en el grupo ID "+groupID, err),
+				)
+				return
+			}
+			codes[role] = code
+		case !wanted[role] && enabled:
+			if err := r.client.DisableGroupEnrollment(ctx, groupID, role); err != nil {
+				diags.AddError(
+					"Error desactivando código de auto-registro",
+					clientErrorDetail("No se pudo desactivar el código del rol "+role+" en el grupo ID "+groupID, err),
+				)
+				return
+			}
+			delete(codes, role)
+		}
+	}
+
+	setEnrollmentCodes(model, codes)
+}
+
+// setEnrollmentCodes rellena codes y los indicadores de cada rol a partir de los códigos activos
+func setEnrollmentCodes(model *groupEnrollmentResourceModel, codes map[string]string) {
+	model.Codes = enrollmentCodesValue(codes)
+
+	_, manager := codes["manager"]
+	_, advanced := codes["advanced"]
+	_, user := codes["user"]
+	model.Manager = types.BoolValue(manager)
+	model.Advanced = types.BoolValue(advanced)
+	model.User = types.BoolValue(user)
+}
@@ -0,0 +1,237 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/tknika/terraform-provider-isardvdi/internal/testserver"
+)
+
+func TestAccGroupEnrollmentResource(t *testing.T) {
+	srv := testAccServer(t)
+	var userCode string
+
+	// saveCode guarda el código de user; sameCode comprueba si sigue siendo el mismo
+	saveCode := tfresource.TestCheckResourceAttrWith("isardvdi_group_enrollment.test", "codes.user", func(value string) error {
+		userCode = value
+		return nil
+	})
+	sameCode := func(want bool) tfresource.TestCheckFunc {
+		return tfresource.TestCheckResourceAttrWith("isardvdi_group_enrollment.test", "codes.user", func(value string) error {
+			if (value == userCode) != want {
+				return fmt.Errorf("codes.user = %q, código anterior %q (se esperaba igual: %t)", value, userCode, want)
+			}
+			return nil
+		})
+	}
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		// Eliminar el recurso desactiva todos los códigos del grupo
+		CheckDestroy: testAccCheckEnrollmentCodes(srv),
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + testAccGroupEnrollmentConfig(`
+  user = true
+`),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("isardvdi_group_enrollment.test", "id", testserver.DefaultGroupID),
+					tfresource.TestCheckResourceAttr("isardvdi_group_enrollment.test", "user", "true"),
+					tfresource.TestCheckResourceAttr("isardvdi_group_enrollment.test", "manager", "false"),
+					tfresource.TestCheckResourceAttr("isardvdi_group_enrollment.test", "codes.%", "1"),
+					saveCode,
+					testAccCheckEnrollmentCodes(srv, "user"),
+				),
+			},
+			{
+				// Activar otro rol conserva los códigos existentes
+				Config: testAccProviderConfig(srv) + testAccGroupEnrollmentConfig(`
+  user     = true
+  advanced = true
+`),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("isardvdi_group_enrollment.test", "codes.%", "2"),
+					tfresource.TestCheckResourceAttrSet("isardvdi_group_enrollment.test", "codes.advanced"),
+					sameCode(true),
+					testAccCheckEnrollmentCodes(srv, "advanced", "user"),
+				),
+			},
+			{
+				// Cambiar rotation_version genera códigos nuevos
+				Config: testAccProviderConfig(srv) + testAccGroupEnrollmentConfig(`
+  user             = true
+  advanced         = true
+  rotation_version = 1
+`),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("isardvdi_group_enrollment.test", "codes.%", "2"),
+					sameCode(false),
+					testAccCheckEnrollmentCodes(srv, "advanced", "user"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(srv) + testAccGroupEnrollmentConfig(`
+  advanced         = true
+  rotation_version = 1
+`),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("isardvdi_group_enrollment.test", "user", "false"),
+					tfresource.TestCheckResourceAttr("isardvdi_group_enrollment.test", "codes.%", "1"),
+					tfresource.TestCheckNoResourceAttr("isardvdi_group_enrollment.test", "codes.user"),
+					testAccCheckEnrollmentCodes(srv, "advanced"),
+				),
+			},
+			{
+				ResourceName:            "isardvdi_group_enrollment.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"rotation_version"},
+			},
+		},
+	})
+}
+
+func TestGroupEnrollmentApplyEnrollment(t *testing.T) {
+	c, _ := newTestClient(t)
+	ctx := context.Background()
+	r := &groupEnrollmentResource{client: c}
+
+	groupID, err := c.CreateGroup(ctx, "grupo", "", testserver.DefaultCategoryID, nil)
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	if _, err := c.ResetGroupEnrollment(ctx, groupID, "manager"); err != nil {
+		t.Fatalf("ResetGroupEnrollment: %v", err)
+	}
+
+	apply := func(manager, user, rotate bool) map[string]string {
+		t.Helper()
+
+		group, err := c.GetGroup(ctx, groupID)
+		if err != nil {
+			t.Fatalf("GetGroup: %v", err)
+		}
+		model := groupEnrollmentResourceModel{
+			GroupID:  types.StringValue(groupID),
+			Manager:  types.BoolValue(manager),
+			Advanced: types.BoolValue(false),
+			User:     types.BoolValue(user),
+		}
+		var diags diag.Diagnostics
+		r.applyEnrollment(ctx, &model, group, rotate, &diags)
+		if diags.HasError() {
+			t.Fatalf("applyEnrollment: %v", diags)
+		}
+
+		codes := map[string]string{}
+		model.Codes.ElementsAs(ctx, &codes, false)
+
+		// codes debe coincidir con los códigos guardados en el grupo
+		group, err = c.GetGroup(ctx, groupID)
+		if err != nil {
+			t.Fatalf("GetGroup: %v", err)
+		}
+		if fmt.Sprint(codes) != fmt.Sprint(group.EnrollmentCodes()) {
+			t.Errorf("codes = %v, el grupo tiene %v", codes, group.EnrollmentCodes())
+		}
+		return codes
+	}
+
+	// Se genera el código de user y se desactiva el de manager
+	codes := apply(false, true, false)
+	if _, ok := codes["manager"]; ok || codes["user"] == "" || len(codes) != 1 {
+		t.Fatalf("codes = %v, se esperaba solo user", codes)
+	}
+	first := codes["user"]
+
+	// Sin rotación, el código existente se conserva
+	if codes := apply(false, true, false); codes["user"] != first {
+		t.Errorf("codes.user = %s, se esperaba conservar %s", codes["user"], first)
+	}
+
+	// Con rotación, se genera un código nuevo
+	if codes := apply(false, true, true); codes["user"] == "" || codes["user"] == first {
+		t.Errorf("codes.user = %s, se esperaba un código distinto de %s", codes["user"], first)
+	}
+}
+
+func TestGroupEnrollmentModifyPlan(t *testing.T) {
+	ctx := context.Background()
+	r := &groupEnrollmentResource{}
+
+	state := map[string]interface{}{
+		"id":       testserver.DefaultGroupID,
+		"group_id": testserver.DefaultGroupID,
+		"manager":  false,
+		"advanced": false,
+		"user":     true,
+		"codes":    map[string]string{"user": "codigo"},
+	}
+
+	tests := []struct {
+		name        string
+		extra       map[string]interface{}
+		wantUnknown bool
+	}{
+		{"sin cambios", nil, false},
+		{"rol activado", map[string]interface{}{"advanced": true}, true},
+		{"rol desactivado", map[string]interface{}{"user": false}, true},
+		{"rotación", map[string]interface{}{"rotation_version": int64(1)}, true},
+	}
+
+	for _, tt := range tests {
+		plan := newPlan(t, r, copyAttrs(state, tt.extra))
+		req := resource.ModifyPlanRequest{State: newState(t, r, state), Plan: plan}
+		resp := resource.ModifyPlanResponse{Plan: plan}
+
+		r.ModifyPlan(ctx, req, &resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("%s: ModifyPlan: %v", tt.name, resp.Diagnostics)
+		}
+		var codes types.Map
+		resp.Plan.GetAttribute(ctx, path.Root("codes"), &codes)
+		if codes.IsUnknown() != tt.wantUnknown {
+			t.Errorf("%s: codes = %s, se esperaba desconocido = %t", tt.name, codes, tt.wantUnknown)
+		}
+	}
+}
+
+// testAccCheckEnrollmentCodes comprueba que el grupo por defecto tiene código de auto-registro
+// exactamente para los roles indicados
+func testAccCheckEnrollmentCodes(srv *testserver.Server, roles ...string) tfresource.TestCheckFunc {
+	return func(*terraform.State) error {
+		group, ok := srv.Group(testserver.DefaultGroupID)
+		if !ok {
+			return fmt.Errorf("no existe el grupo %s", testserver.DefaultGroupID)
+		}
+		enrollment, _ := group["enrollment"].(map[string]interface{})
+
+		var got []string
+		for _, role := range []string{"advanced", "manager", "user"} {
+			if code, _ := enrollment[role].(string); code != "" {
+				got = append(got, role)
+			}
+		}
+		if fmt.Sprint(got) != fmt.Sprint(roles) {
+			return fmt.Errorf("roles con código en el servidor = %v, se esperaba %v", got, roles)
+		}
+		return nil
+	}
+}
+
+// testAccGroupEnrollmentConfig devuelve un isardvdi_group_enrollment del grupo por defecto
+// con los argumentos indicados
+func testAccGroupEnrollmentConfig(extra string) string {
+	return fmt.Sprintf(`
+resource "isardvdi_group_enrollment" "test" {
+  group_id = %q
+%s}
+`, testserver.DefaultGroupID, extra)
+}
@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+	"github.com/tknika/terraform-provider-isardvdi/internal/testserver"
+)
+
+func TestAccGroupResource(t *testing.T) {
+	srv := testAccServer(t)
+	var groupID string
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed("isardvdi_group", srv.Group),
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + testAccGroupConfig("tf-acc-grupo", ""),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttrSet("isardvdi_group.test", "id"),
+					tfresource.TestCheckResourceAttr("isardvdi_group.test", "parent_category", testserver.DefaultCategoryID),
+					tfresource.TestCheckResourceAttr("isardvdi_group.test", "linked_groups.#", "0"),
+					// La API crea los grupos sin códigos de auto-registro
+					tfresource.TestCheckResourceAttr("isardvdi_group.test", "enrollment.%", "0"),
+					func(s *terraform.State) error {
+						groupID = s.RootModule().Resources["isardvdi_group.test"].Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				// Un código generado fuera de Terraform aparece en enrollment al refrescar
+				PreConfig: func() {
+					c, err := client.NewClient(srv.Endpoint(), testserver.DefaultToken, true)
+					if err != nil {
+						t.Fatalf("NewClient: %v", err)
+					}
+					if _, err := c.ResetGroupEnrollment(context.Background(), groupID, "user"); err != nil {
+						t.Fatalf("ResetGroupEnrollment: %v", err)
+					}
+				},
+				Config: testAccProviderConfig(srv) + testAccGroupConfig("tf-acc-grupo-renombrado", fmt.Sprintf(`
+  description   = "Alumnos de 1º"
+  linked_groups = [%q]
+`, testserver.DefaultGroupID)),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("isardvdi_group.test", "name", "tf-acc-grupo-renombrado"),
+					tfresource.TestCheckResourceAttr("isardvdi_group.test", "description", "Alumnos de 1º"),
+					tfresource.TestCheckResourceAttr("isardvdi_group.test", "linked_groups.0", testserver.DefaultGroupID),
+					tfresource.TestCheckResourceAttr("isardvdi_group.test", "enrollment.%", "1"),
+					tfresource.TestCheckResourceAttrSet("isardvdi_group.test", "enrollment.user"),
+					testAccCheckServerDoc("isardvdi_group.test", srv.Group, func(doc map[string]interface{}) error {
+						enrollment, _ := doc["enrollment"].(map[string]interface{})
+						if code, _ := enrollment["user"].(string); code == "" {
+							return fmt.Errorf("enrollment en el servidor = %v, se esperaba un código para user", enrollment)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				ResourceName:      "isardvdi_group.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestGroupReadEnrollment(t *testing.T) {
+	c, _ := newTestClient(t)
+	ctx := context.Background()
+	r := &groupResource{client: c}
+
+	groupID, err := c.CreateGroup(ctx, "grupo", "", testserver.DefaultCategoryID, nil)
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	code, err := c.ResetGroupEnrollment(ctx, groupID, "advanced")
+	if err != nil {
+		t.Fatalf("ResetGroupEnrollment: %v", err)
+	}
+
+	state := newState(t, r, map[string]interface{}{"id": groupID})
+	resp := resource.ReadResponse{State: state}
+	r.Read(ctx, resource.ReadRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", resp.Diagnostics)
+	}
+
+	// Los roles sin código (false en la API) no aparecen en el mapa
+	var model groupResourceModel
+	resp.State.Get(ctx, &model)
+	want := types.MapValueMust(types.StringType, map[string]attr.Value{"advanced": types.StringValue(code)})
+	if !model.Enrollment.Equal(want) {
+		t.Errorf("enrollment = %s, se esperaba %s", model.Enrollment, want)
+	}
+}
+
+// testAccGroupConfig devuelve un isardvdi_group en la categoría por defecto con el nombre y
+// los argumentos adicionales indicados
+func testAccGroupConfig(name, extra string) string {
+	return fmt.Sprintf(`
+resource "isardvdi_group" "test" {
+  name            = %q
+  parent_category = %q
+%s}
+`, name, testserver.DefaultCategoryID, extra)
+}
@@ -3,7 +3,9 @@ package provider
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -19,6 +21,14 @@ var (
 	_ resource.ResourceWithImportState = &mediaResource{}
 )
 
+// Timeouts por defecto de las operaciones sobre medias
+const (
+	defaultMediaCreateTimeout = 30 * time.Minute
+	defaultMediaReadTimeout   = 5 * time.Minute
+	defaultMediaUpdateTimeout = 5 * time.Minute
+	defaultMediaDeleteTimeout = 5 * time.Minute
+)
+
 func NewMediaResource() resource.Resource {
 	return &mediaResource{}
 }
@@ -28,19 +38,20 @@ type mediaResource struct {
 }
 
 type mediaResourceModel struct {
-	ID          types.String `tfsdk:"id"`
-	Name        types.String `tfsdk:"name"`
-	Description types.String `tfsdk:"description"`
-	URL         types.String `tfsdk:"url"`
-	Kind        types.String `tfsdk:"kind"`
-	Allowed     types.Object `tfsdk:"allowed"`
+	ID          types.String   `tfsdk:"id"`
+	Name        types.String   `tfsdk:"name"`
+	Description types.String   `tfsdk:"description"`
+	URL         types.String   `tfsdk:"url"`
+	Kind        types.String   `tfsdk:"kind"`
+	Allowed     types.Object   `tfsdk:"allowed"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *mediaResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_media"
 }
 
-func (r *mediaResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *mediaResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Gestiona un media (ISO, disk image, etc.) en Isard VDI. Los medias son archivos que se descargan de URLs especificadas.",
 		Attributes: map[string]schema.Attribute{
@@ -103,6 +114,14 @@ func (r *mediaResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
 	}
 }
 
@@ -131,6 +150,15 @@ func (r *mediaResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultMediaCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	// Construir el mapa allowed si se especifica
 	var allowed map[string]interface{}
 	if !plan.Allowed.IsNull() {
@@ -184,6 +212,7 @@ func (r *mediaResource) Create(ctx context.Context, req resource.CreateRequest,
 
 	// Crear el media usando la API
 	mediaID, err := r.client.CreateMedia(
+		ctx,
 		plan.Name.ValueString(),
 		plan.Description.ValueString(),
 		plan.URL.ValueString(),
@@ -194,7 +223,7 @@ func (r *mediaResource) Create(ctx context.Context, req resource.CreateRequest,
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creando el media",
-			fmt.Sprintf("No se pudo crear el media: %s", err.Error()),
+			clientErrorDetail("No se pudo crear el media", err),
 		)
 		return
 	}
@@ -213,10 +242,26 @@ func (r *mediaResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	media, err := r.client.GetMedia(state.ID.ValueString())
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultMediaReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	media, err := r.client.GetMedia(ctx, state.ID.ValueString())
 	if err != nil {
 		// Si el media no se encuentra, eliminarlo del state
-		resp.State.RemoveResource(ctx)
+		if client.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error leyendo el media",
+			fmt.Sprintf("No se pudo leer el media (ID: %s): %s", state.ID.ValueString(), err.Error()),
+		)
 		return
 	}
 
@@ -242,6 +287,15 @@ func (r *mediaResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultMediaUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	// Los medias en Isard VDI no soportan actualización de los campos principales
 	// Solo se puede recrear, por lo que name, url y kind tienen RequiresReplace
 	// La descripción tampoco se puede actualizar directamente vía API
@@ -263,13 +317,29 @@ func (r *mediaResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultMediaDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	// Primero verificar el estado actual del media
-	media, err := r.client.GetMedia(state.ID.ValueString())
+	media, err := r.client.GetMedia(ctx, state.ID.ValueString())
 	if err != nil {
 		// Si el media no existe, no hay nada que eliminar
-		resp.Diagnostics.AddWarning(
-			"Media no encontrado",
-			fmt.Sprintf("El media (ID: %s) no se encontró, puede haber sido eliminado manualmente", state.ID.ValueString()),
+		if client.IsNotFound(err) {
+			resp.Diagnostics.AddWarning(
+				"Media no encontrado",
+				fmt.Sprintf("El media (ID: %s) no se encontró, puede haber sido eliminado manualmente", state.ID.ValueString()),
+			)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error leyendo el media",
+			fmt.Sprintf("No se pudo leer el media (ID: %s): %s", state.ID.ValueString(), err.Error()),
 		)
 		return
 	}
@@ -283,8 +353,8 @@ func (r *mediaResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	err = r.client.DeleteMedia(state.ID.ValueString())
-	if err != nil {
+	err = r.client.DeleteMedia(ctx, state.ID.ValueString())
+	if err != nil && !client.IsNotFound(err) {
 		resp.Diagnostics.AddError(
 			"Error eliminando el media",
 			fmt.Sprintf("No se pudo eliminar el media (ID: %s): %s", state.ID.ValueString(), err.Error()),
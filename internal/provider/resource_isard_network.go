@@ -3,7 +3,10 @@ package provider
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -14,8 +17,17 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &networkResource{}
-	_ resource.ResourceWithConfigure = &networkResource{}
+	_ resource.Resource                = &networkResource{}
+	_ resource.ResourceWithConfigure   = &networkResource{}
+	_ resource.ResourceWithImportState = &networkResource{}
+)
+
+// Timeouts por defecto de las operaciones sobre redes
+const (
+	defaultNetworkCreateTimeout = 5 * time.Minute
+	defaultNetworkReadTimeout   = 5 * time.Minute
+	defaultNetworkUpdateTimeout = 5 * time.Minute
+	defaultNetworkDeleteTimeout = 5 * time.Minute
 )
 
 // NewNetworkResource is a helper function to simplify the provider implementation.
@@ -30,12 +42,13 @@ type networkResource struct {
 
 // networkResourceModel maps the resource schema data.
 type networkResourceModel struct {
-	ID          types.String `tfsdk:"id"`
-	Name        types.String `tfsdk:"name"`
-	Description types.String `tfsdk:"description"`
-	Model       types.String `tfsdk:"model"`
-	QoSID       types.String `tfsdk:"qos_id"`
-	MetadataID  types.String `tfsdk:"metadata_id"`
+	ID          types.String   `tfsdk:"id"`
+	Name        types.String   `tfsdk:"name"`
+	Description types.String   `tfsdk:"description"`
+	Model       types.String   `tfsdk:"model"`
+	QoSID       types.String   `tfsdk:"qos_id"`
+	MetadataID  types.String   `tfsdk:"metadata_id"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
 }
 
 // Metadata returns the resource type name.
@@ -44,7 +57,7 @@ func (r *networkResource) Metadata(_ context.Context, req resource.MetadataReque
 }
 
 // Schema defines the schema for the resource.
-func (r *networkResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *networkResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Gestiona una red virtual de usuario en Isard VDI.",
 		Attributes: map[string]schema.Attribute{
@@ -84,6 +97,14 @@ func (r *networkResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 				Computed:    true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
 	}
 }
 
@@ -117,6 +138,15 @@ func (r *networkResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultNetworkCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	// Crear la red
 	model := plan.Model.ValueString()
 	qosID := plan.QoSID.ValueString()
@@ -130,6 +160,7 @@ func (r *networkResource) Create(ctx context.Context, req resource.CreateRequest
 	}
 
 	networkID, err := r.client.CreateNetwork(
+		ctx,
 		plan.Name.ValueString(),
 		plan.Description.ValueString(),
 		model,
@@ -139,13 +170,13 @@ func (r *networkResource) Create(ctx context.Context, req resource.CreateRequest
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creando red",
-			"No se pudo crear la red: "+err.Error(),
+			clientErrorDetail("No se pudo crear la red", err),
 		)
 		return
 	}
 
 	// Obtener la red creada para leer los valores computados
-	network, err := r.client.GetNetwork(networkID)
+	network, err := r.client.GetNetwork(ctx, networkID)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error leyendo red creada",
@@ -178,10 +209,19 @@ func (r *networkResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultNetworkReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
 	// Get refreshed network value from Isard
-	network, err := r.client.GetNetwork(state.ID.ValueString())
+	network, err := r.client.GetNetwork(ctx, state.ID.ValueString())
 	if err != nil {
-		if err.Error() == "network not found" {
+		if client.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -194,7 +234,10 @@ func (r *networkResource) Read(ctx context.Context, req resource.ReadRequest, re
 
 	// Overwrite items with refreshed state
 	state.Name = types.StringValue(network.Name)
-	state.Description = types.StringValue(network.Description)
+	// description es opcional sin valor computado: "" en la API equivale a no configurarla
+	if network.Description != "" || !state.Description.IsNull() {
+		state.Description = types.StringValue(network.Description)
+	}
 	state.Model = types.StringValue(network.Model)
 	state.QoSID = types.StringValue(network.QoSID)
 	state.MetadataID = types.StringValue(network.MetadataID)
@@ -217,6 +260,15 @@ func (r *networkResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultNetworkUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	// Get current state
 	var state networkResourceModel
 	diags = req.State.Get(ctx, &state)
@@ -245,6 +297,7 @@ func (r *networkResource) Update(ctx context.Context, req resource.UpdateRequest
 
 	// Actualizar la red
 	err := r.client.UpdateNetwork(
+		ctx,
 		plan.ID.ValueString(),
 		name,
 		description,
@@ -260,7 +313,7 @@ func (r *networkResource) Update(ctx context.Context, req resource.UpdateRequest
 	}
 
 	// Obtener la red actualizada
-	network, err := r.client.GetNetwork(plan.ID.ValueString())
+	network, err := r.client.GetNetwork(ctx, plan.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error leyendo red actualizada",
@@ -293,8 +346,17 @@ func (r *networkResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultNetworkDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	// Delete existing network
-	err := r.client.DeleteNetwork(state.ID.ValueString())
+	err := r.client.DeleteNetwork(ctx, state.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error eliminando red",
@@ -303,3 +365,8 @@ func (r *networkResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 }
+
+// ImportState importa un recurso existente a partir de su ID.
+func (r *networkResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
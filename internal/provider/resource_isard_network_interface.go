@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -14,8 +15,9 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &networkInterfaceResource{}
-	_ resource.ResourceWithConfigure = &networkInterfaceResource{}
+	_ resource.Resource                = &networkInterfaceResource{}
+	_ resource.ResourceWithConfigure   = &networkInterfaceResource{}
+	_ resource.ResourceWithImportState = &networkInterfaceResource{}
 )
 
 // NewNetworkInterfaceResource is a helper function to simplify the provider implementation.
@@ -251,6 +253,7 @@ func (r *networkInterfaceResource) Create(ctx context.Context, req resource.Crea
 
 	// Crear la interfaz de red
 	err := r.client.CreateNetworkInterface(
+		ctx,
 		plan.ID.ValueString(),
 		plan.Name.ValueString(),
 		plan.Description.ValueString(),
@@ -264,13 +267,13 @@ func (r *networkInterfaceResource) Create(ctx context.Context, req resource.Crea
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creando interfaz de red",
-			"No se pudo crear la interfaz de red: "+err.Error(),
+			clientErrorDetail("No se pudo crear la interfaz de red", err),
 		)
 		return
 	}
 
 	// Obtener la interfaz creada para leer los valores computados
-	iface, err := r.client.GetNetworkInterface(plan.ID.ValueString())
+	iface, err := r.client.GetNetworkInterface(ctx, plan.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error leyendo interfaz de red creada",
@@ -312,9 +315,9 @@ func (r *networkInterfaceResource) Read(ctx context.Context, req resource.ReadRe
 	}
 
 	// Get refreshed interface value from Isard
-	iface, err := r.client.GetNetworkInterface(state.ID.ValueString())
+	iface, err := r.client.GetNetworkInterface(ctx, state.ID.ValueString())
 	if err != nil {
-		if err.Error() == "network interface not found" {
+		if client.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -543,6 +546,7 @@ func (r *networkInterfaceResource) Update(ctx context.Context, req resource.Upda
 
 	// Actualizar la interfaz de red
 	err := r.client.UpdateNetworkInterface(
+		ctx,
 		plan.ID.ValueString(),
 		name,
 		description,
@@ -562,7 +566,7 @@ func (r *networkInterfaceResource) Update(ctx context.Context, req resource.Upda
 	}
 
 	// Obtener la interfaz actualizada
-	iface, err := r.client.GetNetworkInterface(plan.ID.ValueString())
+	iface, err := r.client.GetNetworkInterface(ctx, plan.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error leyendo interfaz de red actualizada",
@@ -659,7 +663,7 @@ func (r *networkInterfaceResource) Delete(ctx context.Context, req resource.Dele
 	}
 
 	// Delete existing interface
-	err := r.client.DeleteNetworkInterface(state.ID.ValueString())
+	err := r.client.DeleteNetworkInterface(ctx, state.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error eliminando interfaz de red",
@@ -668,3 +672,8 @@ func (r *networkInterfaceResource) Delete(ctx context.Context, req resource.Dele
 		return
 	}
 }
+
+// ImportState importa un recurso existente a partir de su ID.
+func (r *networkInterfaceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
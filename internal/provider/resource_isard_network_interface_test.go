@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"testing"
+
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/tknika/terraform-provider-isardvdi/internal/testserver"
+)
+
+func TestAccNetworkInterfaceResource(t *testing.T) {
+	srv := testAccServer(t)
+	iface := func(id string) (map[string]interface{}, bool) { return srv.TableItem("interfaces", id) }
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed("isardvdi_network_interface", iface),
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + testAccNetworkInterfaceConfig("Primera"),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("isardvdi_network_interface.test", "id", "tf-acc-bridge"),
+					tfresource.TestCheckResourceAttr("isardvdi_network_interface.test", "model", "virtio"),
+					tfresource.TestCheckResourceAttr("isardvdi_network_interface.test", "allowed.users.0", testserver.DefaultUserID),
+				),
+			},
+			{
+				Config: testAccProviderConfig(srv) + testAccNetworkInterfaceConfig("Segunda"),
+				Check:  tfresource.TestCheckResourceAttr("isardvdi_network_interface.test", "description", "Segunda"),
+			},
+			{
+				ResourceName:      "isardvdi_network_interface.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// testAccNetworkInterfaceConfig devuelve un isardvdi_network_interface con la descripción indicada
+func testAccNetworkInterfaceConfig(description string) string {
+	return `
+resource "isardvdi_network_interface" "test" {
+  id          = "tf-acc-bridge"
+  name        = "Bridge de pruebas"
+  description = "` + description + `"
+  net         = "br-100"
+  kind        = "bridge"
+
+  allowed {
+    users = ["` + testserver.DefaultUserID + `"]
+  }
+}
+`
+}
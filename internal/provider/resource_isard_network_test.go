@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"testing"
+
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccNetworkResource(t *testing.T) {
+	srv := testAccServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed("isardvdi_network", srv.Network),
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + `
+resource "isardvdi_network" "test" {
+  name = "tf-acc-red"
+}
+`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttrSet("isardvdi_network.test", "id"),
+					tfresource.TestCheckResourceAttr("isardvdi_network.test", "model", "virtio"),
+					tfresource.TestCheckResourceAttrSet("isardvdi_network.test", "metadata_id"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+resource "isardvdi_network" "test" {
+  name        = "tf-acc-red"
+  description = "Red de laboratorio"
+}
+`,
+				Check: tfresource.TestCheckResourceAttr("isardvdi_network.test", "description", "Red de laboratorio"),
+			},
+			{
+				ResourceName:      "isardvdi_network.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
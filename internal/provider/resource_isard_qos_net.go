@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -14,8 +15,9 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &qosNetResource{}
-	_ resource.ResourceWithConfigure = &qosNetResource{}
+	_ resource.Resource                = &qosNetResource{}
+	_ resource.ResourceWithConfigure   = &qosNetResource{}
+	_ resource.ResourceWithImportState = &qosNetResource{}
 )
 
 // NewQoSNetResource is a helper function to simplify the provider implementation.
@@ -148,6 +150,7 @@ func (r *qosNetResource) Create(ctx context.Context, req resource.CreateRequest,
 
 	// Crear el QoS de red
 	qosID, err := r.client.CreateQoSNet(
+		ctx,
 		plan.Name.ValueString(),
 		plan.Description.ValueString(),
 		bandwidth,
@@ -155,7 +158,7 @@ func (r *qosNetResource) Create(ctx context.Context, req resource.CreateRequest,
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creando QoS de red",
-			"No se pudo crear el QoS de red: "+err.Error(),
+			clientErrorDetail("No se pudo crear el QoS de red", err),
 		)
 		return
 	}
@@ -182,9 +185,9 @@ func (r *qosNetResource) Read(ctx context.Context, req resource.ReadRequest, res
 	}
 
 	// Get refreshed qos value from Isard
-	qos, err := r.client.GetQoSNet(state.ID.ValueString())
+	qos, err := r.client.GetQoSNet(ctx, state.ID.ValueString())
 	if err != nil {
-		if err.Error() == "qos_net not found" {
+		if client.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -294,6 +297,7 @@ func (r *qosNetResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 	// Actualizar el QoS de red
 	err := r.client.UpdateQoSNet(
+		ctx,
 		plan.ID.ValueString(),
 		name,
 		description,
@@ -326,7 +330,7 @@ func (r *qosNetResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	}
 
 	// Delete existing QoS de red
-	err := r.client.DeleteQoSNet(state.ID.ValueString())
+	err := r.client.DeleteQoSNet(ctx, state.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error eliminando QoS de red",
@@ -335,3 +339,8 @@ func (r *qosNetResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 }
+
+// ImportState importa un recurso existente a partir de su ID.
+func (r *qosNetResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
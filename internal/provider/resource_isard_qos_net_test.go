@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"testing"
+
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccQoSNetResource(t *testing.T) {
+	srv := testAccServer(t)
+	qosNet := func(id string) (map[string]interface{}, bool) { return srv.TableItem("qos_net", id) }
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed("isardvdi_qos_net", qosNet),
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + `
+resource "isardvdi_qos_net" "test" {
+  name             = "tf-acc-qos"
+  average_download = 1000
+  average_upload   = 500
+}
+`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttrSet("isardvdi_qos_net.test", "id"),
+					tfresource.TestCheckResourceAttr("isardvdi_qos_net.test", "average_download", "1000"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+resource "isardvdi_qos_net" "test" {
+  name             = "tf-acc-qos"
+  description      = "Limitado"
+  average_download = 2000
+  average_upload   = 500
+  peak_download    = 4000
+}
+`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("isardvdi_qos_net.test", "average_download", "2000"),
+					tfresource.TestCheckResourceAttr("isardvdi_qos_net.test", "peak_download", "4000"),
+				),
+			},
+			{
+				ResourceName:      "isardvdi_qos_net.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -0,0 +1,439 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &quotaResource{}
+	_ resource.ResourceWithConfigure      = &quotaResource{}
+	_ resource.ResourceWithImportState    = &quotaResource{}
+	_ resource.ResourceWithValidateConfig = &quotaResource{}
+)
+
+// Timeouts por defecto de las operaciones sobre cuotas
+const (
+	defaultQuotaCreateTimeout = 5 * time.Minute
+	defaultQuotaReadTimeout   = 5 * time.Minute
+	defaultQuotaUpdateTimeout = 5 * time.Minute
+	defaultQuotaDeleteTimeout = 5 * time.Minute
+)
+
+// quotaAttributeTypes son los tipos de los atributos de los objetos quota y limits
+var quotaAttributeTypes = map[string]attr.Type{
+	"desktops":           types.Int64Type,
+	"running":            types.Int64Type,
+	"vcpus":              types.Int64Type,
+	"memory":             types.Int64Type,
+	"templates":          types.Int64Type,
+	"isos":               types.Int64Type,
+	"desktops_disk_size": types.Int64Type,
+}
+
+// NewQuotaResource is a helper function to simplify the provider implementation.
+func NewQuotaResource() resource.Resource {
+	return &quotaResource{}
+}
+
+// quotaResource is the resource implementation.
+type quotaResource struct {
+	client *client.Client
+}
+
+// quotaResourceModel maps the resource schema data.
+type quotaResourceModel struct {
+	ID         types.String   `tfsdk:"id"`
+	TargetType types.String   `tfsdk:"target_type"`
+	TargetID   types.String   `tfsdk:"target_id"`
+	Quota      types.Object   `tfsdk:"quota"`
+	Limits     types.Object   `tfsdk:"limits"`
+	Propagate  types.Bool     `tfsdk:"propagate"`
+	Timeouts   timeouts.Value `tfsdk:"timeouts"`
+}
+
+// quotaModel maps the quota and limits nested objects.
+type quotaModel struct {
+	Desktops         types.Int64 `tfsdk:"desktops"`
+	Running          types.Int64 `tfsdk:"running"`
+	VCPUs            types.Int64 `tfsdk:"vcpus"`
+	Memory           types.Int64 `tfsdk:"memory"`
+	Templates        types.Int64 `tfsdk:"templates"`
+	ISOs             types.Int64 `tfsdk:"isos"`
+	DesktopsDiskSize types.Int64 `tfsdk:"desktops_disk_size"`
+}
+
+// Metadata returns the resource type name.
+func (r *quotaResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_quota"
+}
+
+// quotaSchemaAttribute define los objetos quota y limits, que comparten los mismos máximos
+func quotaSchemaAttribute(description string) schema.SingleNestedAttribute {
+	quotaInt := func(desc string) schema.Int64Attribute {
+		return schema.Int64Attribute{
+			Required:            true,
+			MarkdownDescription: desc,
+			Validators: []validator.Int64{
+				int64validator.AtLeast(0),
+			},
+		}
+	}
+
+	return schema.SingleNestedAttribute{
+		Optional:            true,
+		MarkdownDescription: description,
+		Attributes: map[string]schema.Attribute{
+			"desktops":           quotaInt("Número máximo de desktops creados"),
+			"running":            quotaInt("Número máximo de desktops arrancados a la vez"),
+			"vcpus":              quotaInt("Número máximo de vCPUs de los desktops arrancados"),
+			"memory":             quotaInt("Memoria máxima en GB de los desktops arrancados"),
+			"templates":          quotaInt("Número máximo de templates creados"),
+			"isos":               quotaInt("Número máximo de medios (ISOs y floppies) subidos"),
+			"desktops_disk_size": quotaInt("Tamaño máximo en GB del disco de cada desktop"),
+		},
+	}
+}
+
+// Schema defines the schema for the resource.
+func (r *quotaResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Gestiona la cuota y los límites de recursos de una categoría, grupo o usuario de Isard VDI (requiere rol de administrador).",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identificador del recurso con formato `<target_type>/<target_id>`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"target_type": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Tipo de entidad: `category`, `group` o `user`. Cambiarlo recrea el recurso.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(client.QuotaTargetCategory, client.QuotaTargetGroup, client.QuotaTargetUser),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"target_id": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "ID de la categoría, grupo o usuario. Cambiarlo recrea el recurso.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"quota":  quotaSchemaAttribute("Cuota que se aplica a cada usuario de la entidad. Si no se especifica, no hay cuota."),
+			"limits": quotaSchemaAttribute("Límites del conjunto de la entidad (suma de todos sus usuarios). Solo para `category` y `group`. Si no se especifica, no hay límites."),
+			"propagate": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Si es true, la API aplica también la cuota y los límites a los grupos y usuarios que ya existen dentro de la entidad (por defecto: false)",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// ValidateConfig comprueba que no se configuren límites para un usuario.
+func (r *quotaResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config quotaResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.TargetType.ValueString() == client.QuotaTargetUser && !config.Limits.IsNull() && !config.Limits.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("limits"),
+			"Límites no soportados",
+			"Los usuarios solo tienen cuota; limits solo se puede usar con target_type category o group.",
+		)
+	}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *quotaResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *quotaResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan quotaResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultQuotaCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	// El recurso gestiona la cuota y los límites completos: lo no configurado se elimina
+	r.applyQuota(ctx, plan, true, true, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = types.StringValue(plan.TargetType.ValueString() + "/" + plan.TargetID.ValueString())
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *quotaResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state quotaResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultQuotaReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	settings, err := r.client.GetQuota(ctx, state.TargetType.ValueString(), state.TargetID.ValueString())
+	if err != nil {
+		// Si la entidad ya no existe, tampoco su cuota
+		if client.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error leyendo cuota",
+			"No se pudo leer la cuota de "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	state.Quota = quotaFromAPI(settings.Quota)
+	state.Limits = types.ObjectNull(quotaAttributeTypes)
+	if state.TargetType.ValueString() != client.QuotaTargetUser {
+		state.Limits = quotaFromAPI(settings.Limits)
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *quotaResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state quotaResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultQuotaUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	// Al activar propagate se reenvía todo para aplicarlo a los miembros existentes
+	propagated := plan.Propagate.ValueBool() && !state.Propagate.ValueBool()
+	r.applyQuota(
+		ctx,
+		plan,
+		propagated || !plan.Quota.Equal(state.Quota),
+		propagated || !plan.Limits.Equal(state.Limits),
+		&resp.Diagnostics,
+	)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *quotaResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state quotaResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultQuotaDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	// Eliminar el recurso deja la entidad sin cuota ni límites
+	kind := state.TargetType.ValueString()
+	id := state.TargetID.ValueString()
+
+	err := r.client.UpdateQuota(ctx, kind, id, nil, state.Propagate.ValueBool())
+	if err == nil && kind != client.QuotaTargetUser {
+		err = r.client.UpdateLimits(ctx, kind, id, nil, state.Propagate.ValueBool())
+	}
+	if err != nil && !client.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error eliminando cuota",
+			clientErrorDetail("No se pudo eliminar la cuota de "+state.ID.ValueString(), err),
+		)
+		return
+	}
+}
+
+// ImportState importa la cuota de una entidad a partir de un ID `<target_type>/<target_id>`.
+func (r *quotaResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	kind, id, ok := strings.Cut(req.ID, "/")
+	if !ok || id == "" || (kind != client.QuotaTargetCategory && kind != client.QuotaTargetGroup && kind != client.QuotaTargetUser) {
+		resp.Diagnostics.AddError(
+			"ID de importación no válido",
+			"El ID debe tener el formato <target_type>/<target_id>, con target_type category, group o user. Recibido: "+req.ID,
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("target_type"), kind)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("target_id"), id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("propagate"), false)...)
+}
+
+// applyQuota envía la cuota y/o los límites del plan a la API
+func (r *quotaResource) applyQuota(ctx context.Context, plan quotaResourceModel, quota, limits bool, diags *diag.Diagnostics) {
+	kind := plan.TargetType.ValueString()
+	id := plan.TargetID.ValueString()
+	propagate := plan.Propagate.ValueBool()
+
+	if quota {
+		value := quotaToAPI(ctx, plan.Quota, diags)
+		if diags.HasError() {
+			return
+		}
+		if err := r.client.UpdateQuota(ctx, kind, id, value, propagate); err != nil {
+			diags.AddError(
+				"Error aplicando cuota",
+				clientErrorDetail("No se pudo aplicar la cuota a "+kind+" "+id, err),
+			)
+			return
+		}
+	}
+
+	if limits && kind != client.QuotaTargetUser {
+		value := quotaToAPI(ctx, plan.Limits, diags)
+		if diags.HasError() {
+			return
+		}
+		if err := r.client.UpdateLimits(ctx, kind, id, value, propagate); err != nil {
+			diags.AddError(
+				"Error aplicando límites",
+				clientErrorDetail("No se pudieron aplicar los límites a "+kind+" "+id, err),
+			)
+		}
+	}
+}
+
+// quotaToAPI convierte el objeto quota o limits en la cuota del cliente (nil si no se configura)
+func quotaToAPI(ctx context.Context, obj types.Object, diags *diag.Diagnostics) *client.Quota {
+	if obj.IsNull() || obj.IsUnknown() {
+		return nil
+	}
+
+	var model quotaModel
+	diags.Append(obj.As(ctx, &model, basetypes.ObjectAsOptions{})...)
+	return &client.Quota{
+		Desktops:         model.Desktops.ValueInt64(),
+		Running:          model.Running.ValueInt64(),
+		VCPUs:            model.VCPUs.ValueInt64(),
+		Memory:           model.Memory.ValueInt64(),
+		Templates:        model.Templates.ValueInt64(),
+		ISOs:             model.ISOs.ValueInt64(),
+		DesktopsDiskSize: model.DesktopsDiskSize.ValueInt64(),
+	}
+}
+
+// quotaFromAPI convierte la cuota del cliente en el objeto de Terraform (null si no hay)
+func quotaFromAPI(quota *client.Quota) types.Object {
+	if quota == nil {
+		return types.ObjectNull(quotaAttributeTypes)
+	}
+
+	return types.ObjectValueMust(quotaAttributeTypes, map[string]attr.Value{
+		"desktops":           types.Int64Value(quota.Desktops),
+		"running":            types.Int64Value(quota.Running),
+		"vcpus":              types.Int64Value(quota.VCPUs),
+		"memory":             types.Int64Value(quota.Memory),
+		"templates":          types.Int64Value(quota.Templates),
+		"isos":               types.Int64Value(quota.ISOs),
+		"desktops_disk_size": types.Int64Value(quota.DesktopsDiskSize),
+	})
+}
@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+	"github.com/tknika/terraform-provider-isardvdi/internal/testserver"
+)
+
+func TestAccQuotaResource(t *testing.T) {
+	srv := testAccServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		// Eliminar el recurso deja el grupo sin cuota ni límites
+		CheckDestroy: testAccCheckGroupQuota(srv, "quota", nil),
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + testAccQuotaConfig("group", testserver.DefaultGroupID,
+					testAccQuotaBlock("quota", 5)+testAccQuotaBlock("limits", 50)),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("isardvdi_quota.test", "id", "group/"+testserver.DefaultGroupID),
+					tfresource.TestCheckResourceAttr("isardvdi_quota.test", "quota.desktops", "5"),
+					tfresource.TestCheckResourceAttr("isardvdi_quota.test", "limits.desktops", "50"),
+					tfresource.TestCheckResourceAttr("isardvdi_quota.test", "propagate", "false"),
+					testAccCheckGroupQuota(srv, "quota", float64(5)),
+					testAccCheckGroupQuota(srv, "limits", float64(50)),
+				),
+			},
+			{
+				// Quitar limits elimina los límites del grupo
+				Config: testAccProviderConfig(srv) + testAccQuotaConfig("group", testserver.DefaultGroupID,
+					testAccQuotaBlock("quota", 0)),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("isardvdi_quota.test", "quota.desktops", "0"),
+					tfresource.TestCheckNoResourceAttr("isardvdi_quota.test", "limits"),
+					testAccCheckGroupQuota(srv, "quota", float64(0)),
+					testAccCheckGroupQuota(srv, "limits", nil),
+				),
+			},
+			{
+				ResourceName:      "isardvdi_quota.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccQuotaResourceValidation(t *testing.T) {
+	srv := testAccServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + testAccQuotaConfig("user", testserver.DefaultUserID,
+					testAccQuotaBlock("quota", 5)+testAccQuotaBlock("limits", 50)),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Límites no soportados`),
+			},
+			{
+				Config: testAccProviderConfig(srv) + testAccQuotaConfig("group", testserver.DefaultGroupID,
+					testAccQuotaBlock("quota", -1)),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`must be at least 0`),
+			},
+		},
+	})
+}
+
+func TestQuotaValidateConfig(t *testing.T) {
+	r := &quotaResource{}
+	limits := quotaFromAPI(&client.Quota{Desktops: 10})
+
+	tests := []struct {
+		targetType string
+		limits     types.Object
+		wantError  bool
+	}{
+		{client.QuotaTargetUser, limits, true},
+		{client.QuotaTargetUser, types.ObjectNull(quotaAttributeTypes), false},
+		{client.QuotaTargetUser, types.ObjectUnknown(quotaAttributeTypes), false},
+		{client.QuotaTargetGroup, limits, false},
+		{client.QuotaTargetCategory, limits, false},
+	}
+
+	for _, tt := range tests {
+		config := newConfig(t, r, map[string]interface{}{
+			"target_type": tt.targetType,
+			"target_id":   "id",
+			"limits":      tt.limits,
+		})
+		var resp resource.ValidateConfigResponse
+		r.ValidateConfig(context.Background(), resource.ValidateConfigRequest{Config: config}, &resp)
+		if got := resp.Diagnostics.HasError(); got != tt.wantError {
+			t.Errorf("ValidateConfig(%s, limits %s) error = %t, se esperaba %t", tt.targetType, tt.limits, got, tt.wantError)
+		}
+	}
+}
+
+// testAccCheckGroupQuota comprueba el campo desktops de la cuota o los límites del grupo por
+// defecto en el servidor (want nil si no debe haber)
+func testAccCheckGroupQuota(srv *testserver.Server, field string, want interface{}) tfresource.TestCheckFunc {
+	return func(*terraform.State) error {
+		group, ok := srv.Group(testserver.DefaultGroupID)
+		if !ok {
+			return fmt.Errorf("no existe el grupo %s", testserver.DefaultGroupID)
+		}
+		value, ok := group[field].(map[string]interface{})
+		if want == nil {
+			if ok {
+				return fmt.Errorf("%s en el servidor = %v, se esperaba ninguno", field, group[field])
+			}
+			return nil
+		}
+		if !ok || value["desktops"] != want {
+			return fmt.Errorf("%s en el servidor = %v, se esperaba desktops = %v", field, group[field], want)
+		}
+		return nil
+	}
+}
+
+// testAccQuotaBlock devuelve el objeto quota o limits con todos los máximos al valor indicado
+func testAccQuotaBlock(name string, value int) string {
+	return fmt.Sprintf(`
+  %[1]s = {
+    desktops           = %[2]d
+    running            = %[2]d
+    vcpus              = %[2]d
+    memory             = %[2]d
+    templates          = %[2]d
+    isos               = %[2]d
+    desktops_disk_size = %[2]d
+  }
+`, name, value)
+}
+
+// testAccQuotaConfig devuelve un isardvdi_quota para la entidad indicada
+func testAccQuotaConfig(targetType, targetID, extra string) string {
+	return fmt.Sprintf(`
+resource "isardvdi_quota" "test" {
+  target_type = %q
+  target_id   = %q
+%s}
+`, targetType, targetID, extra)
+}
@@ -0,0 +1,497 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/float64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &templateResource{}
+	_ resource.ResourceWithConfigure   = &templateResource{}
+	_ resource.ResourceWithImportState = &templateResource{}
+)
+
+// Timeouts por defecto de las operaciones sobre templates
+const (
+	defaultTemplateCreateTimeout = 30 * time.Minute
+	defaultTemplateReadTimeout   = 5 * time.Minute
+	defaultTemplateUpdateTimeout = 5 * time.Minute
+	defaultTemplateDeleteTimeout = 5 * time.Minute
+)
+
+// maxDerivativesInError es el número máximo de derivados que se listan en el error de borrado
+const maxDerivativesInError = 10
+
+// NewTemplateResource is a helper function to simplify the provider implementation.
+func NewTemplateResource() resource.Resource {
+	return &templateResource{}
+}
+
+// templateResource is the resource implementation.
+type templateResource struct {
+	client *client.Client
+}
+
+// templateResourceModel maps the resource schema data.
+type templateResourceModel struct {
+	ID                types.String   `tfsdk:"id"`
+	DesktopID         types.String   `tfsdk:"desktop_id"`
+	Name              types.String   `tfsdk:"name"`
+	Description       types.String   `tfsdk:"description"`
+	Enabled           types.Bool     `tfsdk:"enabled"`
+	Allowed           types.Object   `tfsdk:"allowed"`
+	VCPUs             types.Int64    `tfsdk:"vcpus"`
+	Memory            types.Float64  `tfsdk:"memory"`
+	NetworkInterfaces types.List     `tfsdk:"network_interfaces"`
+	ForceDestroy      types.Bool     `tfsdk:"force_destroy"`
+	Timeouts          timeouts.Value `tfsdk:"timeouts"`
+}
+
+// Metadata returns the resource type name.
+func (r *templateResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_template"
+}
+
+// Schema defines the schema for the resource.
+func (r *templateResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Gestiona un template de Isard VDI creado a partir de un desktop.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identificador único del template",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"desktop_id": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "ID del desktop a partir del que se crea el template. El desktop debe estar detenido. Cambiarlo recrea el template.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Nombre del template",
+			},
+			"description": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Descripción del template",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"enabled": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Si el template está habilitado para que los usuarios de `allowed` creen desktops a partir de él (por defecto: false)",
+			},
+			"allowed": allowedSchemaAttribute("Con quién se comparte el template. Si no se especifica, solo lo ve su propietario."),
+			"vcpus": schema.Int64Attribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Número de CPUs virtuales del template (por defecto usa el del desktop)",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"memory": schema.Float64Attribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Memoria RAM en GB del template (por defecto usa la del desktop)",
+				PlanModifiers: []planmodifier.Float64{
+					float64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"network_interfaces": schema.ListAttribute{
+				ElementType:         types.StringType,
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Lista de IDs de interfaces de red del template (por defecto usa las del desktop)",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"force_destroy": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Si es true, permite eliminar el template aunque tenga desktops derivados, que la API elimina junto con él. Si es false, la eliminación falla mientras existan derivados (por defecto: false)",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *templateResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Create creates a new resource.
+func (r *templateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan templateResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultTemplateCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	allowed := allowedToAPI(ctx, plan.Allowed, &resp.Diagnostics)
+	hardware := templateHardware(ctx, plan, nil, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Crear el template a partir del desktop
+	templateID, err := r.client.CreateTemplate(
+		ctx,
+		plan.DesktopID.ValueString(),
+		plan.Name.ValueString(),
+		plan.Description.ValueString(),
+		plan.Enabled.ValueBool(),
+		allowed,
+	)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creando el template",
+			clientErrorDetail(fmt.Sprintf("No se pudo crear el template a partir del desktop %s", plan.DesktopID.ValueString()), err),
+		)
+		return
+	}
+
+	// Guardar el ID antes de seguir para no perder el template si falla algún paso posterior
+	plan.ID = types.StringValue(templateID)
+	resp.Diagnostics.Append(setPartialState(ctx, &resp.State, plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// La API copia el disco del desktop en segundo plano
+	if err := r.client.WaitForTemplateCreated(ctx, templateID, createTimeout); err != nil {
+		resp.Diagnostics.AddError(
+			"Error creando el template",
+			fmt.Sprintf("El template (ID: %s) no terminó de crearse: %s", templateID, err.Error()),
+		)
+		return
+	}
+
+	// Aplicar el hardware personalizado sobre el copiado del desktop
+	if len(hardware) > 0 {
+		err := r.client.UpdateDesktop(ctx, templateID, map[string]interface{}{"hardware": hardware})
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error configurando el hardware del template",
+				clientErrorDetail(fmt.Sprintf("No se pudo configurar el hardware del template (ID: %s)", templateID), err),
+			)
+			return
+		}
+	}
+
+	// Completar los atributos no configurados con los valores copiados del desktop
+	template, err := r.client.GetTemplate(ctx, templateID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error leyendo el template creado",
+			fmt.Sprintf("No se pudo leer el template (ID: %s): %s", templateID, err.Error()),
+		)
+		return
+	}
+	fillUnknownTemplateAttributes(&plan, template)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *templateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state templateResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultTemplateReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	template, err := r.client.GetTemplate(ctx, state.ID.ValueString())
+	if err != nil {
+		// Si el template no existe (404), eliminarlo del estado
+		if client.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error leyendo el template",
+			fmt.Sprintf("No se pudo leer el template (ID: %s): %s", state.ID.ValueString(), err.Error()),
+		)
+		return
+	}
+
+	state.Name = types.StringValue(template.Name)
+	state.Description = types.StringValue(template.Description)
+	state.Enabled = types.BoolValue(template.Enabled)
+	state.Allowed = allowedFromAPI(state.Allowed, template.Allowed)
+	if template.DesktopID != "" {
+		state.DesktopID = types.StringValue(template.DesktopID)
+	}
+	if template.Hardware != nil {
+		state.VCPUs = types.Int64Value(template.Hardware.VCPUs)
+		state.Memory = types.Float64Value(template.Hardware.Memory)
+		state.NetworkInterfaces = stringListValue(template.Hardware.Interfaces)
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *templateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan templateResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultTemplateUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	var state templateResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	templateID := state.ID.ValueString()
+	plan.ID = state.ID
+
+	// Construir los datos de actualización solo con los campos que cambian
+	updateData := make(map[string]interface{})
+
+	if !plan.Name.Equal(state.Name) {
+		updateData["name"] = plan.Name.ValueString()
+	}
+
+	if !plan.Description.IsUnknown() && !plan.Description.Equal(state.Description) {
+		updateData["description"] = plan.Description.ValueString()
+	}
+
+	if !plan.Enabled.Equal(state.Enabled) {
+		updateData["enabled"] = plan.Enabled.ValueBool()
+	}
+
+	if !plan.Allowed.Equal(state.Allowed) {
+		updateData["allowed"] = allowedToAPI(ctx, plan.Allowed, &resp.Diagnostics)
+	}
+
+	hardware := templateHardware(ctx, plan, &state, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if len(updateData) > 0 {
+		if err := r.client.UpdateTemplate(ctx, templateID, updateData); err != nil {
+			resp.Diagnostics.AddError(
+				"Error actualizando el template",
+				clientErrorDetail(fmt.Sprintf("No se pudo actualizar el template (ID: %s)", templateID), err),
+			)
+			return
+		}
+	}
+
+	if len(hardware) > 0 {
+		err := r.client.UpdateDesktop(ctx, templateID, map[string]interface{}{"hardware": hardware})
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error actualizando el hardware del template",
+				clientErrorDetail(fmt.Sprintf("No se pudo actualizar el hardware del template (ID: %s)", templateID), err),
+			)
+			return
+		}
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *templateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state templateResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultTemplateDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	templateID := state.ID.ValueString()
+
+	// La API elimina también los desktops derivados: sin force_destroy, no borrar si existen
+	if !state.ForceDestroy.ValueBool() {
+		derivatives, err := r.client.GetTemplateDerivatives(ctx, templateID)
+		if err != nil && !client.IsNotFound(err) {
+			resp.Diagnostics.AddError(
+				"Error eliminando el template",
+				fmt.Sprintf("No se pudieron comprobar los derivados del template (ID: %s): %s", templateID, err.Error()),
+			)
+			return
+		}
+		if len(derivatives) > 0 {
+			resp.Diagnostics.AddError(
+				"El template tiene derivados",
+				fmt.Sprintf("El template (ID: %s) tiene %d desktops o templates derivados (%s) que se eliminarían con él. Elimínelos primero o configure force_destroy = true.",
+					templateID, len(derivatives), derivativeNames(derivatives)),
+			)
+			return
+		}
+	}
+
+	if err := r.client.DeleteTemplate(ctx, templateID); err != nil {
+		resp.Diagnostics.AddError(
+			"Error eliminando el template",
+			fmt.Sprintf("No se pudo eliminar el template (ID: %s): %s", templateID, err.Error()),
+		)
+		return
+	}
+}
+
+// ImportState importa un template existente a partir de su ID. force_destroy toma su
+// valor por defecto.
+func (r *templateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("force_destroy"), false)...)
+}
+
+// templateHardware construye el hardware a enviar a la API con los valores configurados.
+// Si state no es nil, solo incluye los que difieren del estado.
+func templateHardware(ctx context.Context, plan templateResourceModel, state *templateResourceModel, diags *diag.Diagnostics) map[string]interface{} {
+	hardware := make(map[string]interface{})
+
+	if !plan.VCPUs.IsUnknown() && !plan.VCPUs.IsNull() && (state == nil || !plan.VCPUs.Equal(state.VCPUs)) {
+		hardware["vcpus"] = plan.VCPUs.ValueInt64()
+	}
+
+	if !plan.Memory.IsUnknown() && !plan.Memory.IsNull() && (state == nil || !plan.Memory.Equal(state.Memory)) {
+		hardware["memory"] = plan.Memory.ValueFloat64()
+	}
+
+	// Una lista vacía también se envía: quita las interfaces copiadas del desktop
+	if !plan.NetworkInterfaces.IsUnknown() && !plan.NetworkInterfaces.IsNull() && (state == nil || !plan.NetworkInterfaces.Equal(state.NetworkInterfaces)) {
+		interfaces := listToStrings(ctx, plan.NetworkInterfaces, diags)
+		if interfaces == nil {
+			interfaces = []string{}
+		}
+		hardware["interfaces"] = interfaces
+	}
+
+	return hardware
+}
+
+// fillUnknownTemplateAttributes completa los atributos computados que siguen desconocidos
+// tras crear el template con los valores devueltos por la API
+func fillUnknownTemplateAttributes(model *templateResourceModel, template *client.TemplateDetail) {
+	hardware := template.Hardware
+	if hardware == nil {
+		hardware = &client.Hardware{}
+	}
+
+	if model.Description.IsUnknown() {
+		model.Description = types.StringValue(template.Description)
+	}
+	if model.VCPUs.IsUnknown() {
+		model.VCPUs = types.Int64Value(hardware.VCPUs)
+	}
+	if model.Memory.IsUnknown() {
+		model.Memory = types.Float64Value(hardware.Memory)
+	}
+	if model.NetworkInterfaces.IsUnknown() {
+		model.NetworkInterfaces = stringListValue(hardware.Interfaces)
+	}
+}
+
+// derivativeNames devuelve los nombres de los derivados para los mensajes de error
+func derivativeNames(derivatives []client.TemplateDerivative) string {
+	names := make([]string, 0, maxDerivativesInError)
+	for i, derivative := range derivatives {
+		if i == maxDerivativesInError {
+			names = append(names, "...")
+			break
+		}
+		names = append(names, derivative.Name)
+	}
+	return strings.Join(names, ", ")
+}
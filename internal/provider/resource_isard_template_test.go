@@ -0,0 +1,242 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+	"github.com/tknika/terraform-provider-isardvdi/internal/testserver"
+)
+
+func TestAccTemplateResource(t *testing.T) {
+	srv := testAccServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed("isardvdi_template", srv.Template),
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + testAccTemplateConfig("tf-acc-template", fmt.Sprintf(`
+  allowed = {
+    users = [%q]
+  }
+`, testserver.DefaultUserID)),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttrSet("isardvdi_template.test", "id"),
+					tfresource.TestCheckResourceAttrPair("isardvdi_template.test", "desktop_id", "isardvdi_vm.source", "id"),
+					tfresource.TestCheckResourceAttr("isardvdi_template.test", "enabled", "false"),
+					tfresource.TestCheckResourceAttr("isardvdi_template.test", "vcpus", "2"),
+					tfresource.TestCheckResourceAttr("isardvdi_template.test", "memory", "4"),
+					tfresource.TestCheckResourceAttr("isardvdi_template.test", "network_interfaces.#", "1"),
+					tfresource.TestCheckResourceAttr("isardvdi_template.test", "allowed.users.0", testserver.DefaultUserID),
+				),
+			},
+			{
+				// Una lista vacía quita las interfaces copiadas del desktop
+				Config: testAccProviderConfig(srv) + testAccTemplateConfig("tf-acc-template-renamed", `
+  description        = "Actualizado"
+  enabled            = true
+  vcpus              = 4
+  network_interfaces = []
+`),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("isardvdi_template.test", "name", "tf-acc-template-renamed"),
+					tfresource.TestCheckResourceAttr("isardvdi_template.test", "enabled", "true"),
+					tfresource.TestCheckResourceAttr("isardvdi_template.test", "vcpus", "4"),
+					tfresource.TestCheckResourceAttr("isardvdi_template.test", "network_interfaces.#", "0"),
+					tfresource.TestCheckNoResourceAttr("isardvdi_template.test", "allowed"),
+					testAccCheckServerDoc("isardvdi_template.test", srv.Template, func(doc map[string]interface{}) error {
+						hardware, _ := doc["hardware"].(map[string]interface{})
+						if interfaces, ok := hardware["interfaces"].([]interface{}); !ok || len(interfaces) != 0 {
+							return fmt.Errorf("interfaces en el servidor = %v, se esperaba ninguna", hardware["interfaces"])
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				ResourceName:      "isardvdi_template.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccTemplateResourceForceDestroy(t *testing.T) {
+	srv := testAccServer(t)
+	var derivativeID string
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed("isardvdi_template", srv.Template),
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + testAccTemplateConfig("tf-acc-template", ""),
+				// Un desktop creado fuera de Terraform a partir del template
+				Check: func(s *terraform.State) error {
+					c, err := client.NewClient(srv.Endpoint(), testserver.DefaultToken, true)
+					if err != nil {
+						return err
+					}
+					templateID := s.RootModule().Resources["isardvdi_template.test"].Primary.ID
+					derivativeID, err = c.CreatePersistentDesktop(context.Background(), "tf-acc-derivado", "", templateID, nil, nil, nil, nil, nil)
+					return err
+				},
+			},
+			{
+				// Sin force_destroy no se elimina un template con derivados
+				Config:      testAccProviderConfig(srv) + testAccTemplateSourceConfig,
+				ExpectError: regexp.MustCompile(`tiene derivados`),
+			},
+			{
+				Config: testAccProviderConfig(srv) + testAccTemplateConfig("tf-acc-template", `
+  force_destroy = true
+`),
+				Check: tfresource.TestCheckResourceAttr("isardvdi_template.test", "force_destroy", "true"),
+			},
+			{
+				Config: testAccProviderConfig(srv) + testAccTemplateSourceConfig,
+				Check: func(*terraform.State) error {
+					if _, ok := srv.Desktop(derivativeID); ok {
+						return fmt.Errorf("el desktop derivado %s sigue existiendo", derivativeID)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestTemplateCreateKeepsIDOnFailure(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+	r := &templateResource{client: c}
+
+	desktopID := testTemplateSourceDesktop(t, c)
+	srv.FailNext(http.MethodPut, "/api/v3/domain/", http.StatusBadRequest, 1)
+
+	req := resource.CreateRequest{Plan: newPlan(t, r, map[string]interface{}{
+		"desktop_id": desktopID,
+		"name":       "tf-template",
+		"vcpus":      int64(4),
+		"memory":     types.Float64Unknown(),
+	})}
+	resp := resource.CreateResponse{State: newState(t, r, nil)}
+
+	r.Create(ctx, req, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("se esperaba un error al configurar el hardware")
+	}
+
+	// El template ya existe: debe quedar en el estado para que Terraform lo marque como tainted
+	var id types.String
+	resp.State.GetAttribute(ctx, path.Root("id"), &id)
+	if _, ok := srv.Template(id.ValueString()); !ok {
+		t.Fatalf("id en el estado = %s, se esperaba el del template creado", id)
+	}
+}
+
+func TestTemplateDeleteWithDerivatives(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+	r := &templateResource{client: c}
+
+	templateID, err := c.CreateTemplate(ctx, testTemplateSourceDesktop(t, c), "tf-template", "", false, nil)
+	if err != nil {
+		t.Fatalf("CreateTemplate: %v", err)
+	}
+	if err := c.WaitForTemplateCreated(ctx, templateID, 10*time.Second); err != nil {
+		t.Fatalf("WaitForTemplateCreated: %v", err)
+	}
+	derivativeID, err := c.CreatePersistentDesktop(ctx, "derivado", "", templateID, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreatePersistentDesktop: %v", err)
+	}
+
+	deleteTemplate := func(forceDestroy bool) diag.Diagnostics {
+		state := newState(t, r, map[string]interface{}{"id": templateID, "force_destroy": forceDestroy})
+		resp := resource.DeleteResponse{State: state}
+		r.Delete(ctx, resource.DeleteRequest{State: state}, &resp)
+		return resp.Diagnostics
+	}
+
+	diags := deleteTemplate(false)
+	if !diags.HasError() || !regexp.MustCompile(`derivado`).MatchString(diags[0].Detail()) {
+		t.Fatalf("Delete sin force_destroy = %v, se esperaba un error con el derivado", diags)
+	}
+	if _, ok := srv.Template(templateID); !ok {
+		t.Fatal("el template no debe eliminarse mientras tenga derivados")
+	}
+
+	if diags := deleteTemplate(true); diags.HasError() {
+		t.Fatalf("Delete con force_destroy: %v", diags)
+	}
+	if _, ok := srv.Template(templateID); ok {
+		t.Error("el template sigue existiendo")
+	}
+	if _, ok := srv.Desktop(derivativeID); ok {
+		t.Error("la API elimina los derivados junto con el template")
+	}
+}
+
+func TestTemplateHardwareEmptyInterfaces(t *testing.T) {
+	var diags diag.Diagnostics
+	plan := templateResourceModel{
+		VCPUs:             types.Int64Null(),
+		Memory:            types.Float64Null(),
+		NetworkInterfaces: stringListValue([]string{}),
+	}
+
+	hardware := templateHardware(context.Background(), plan, nil, &diags)
+	if diags.HasError() {
+		t.Fatalf("templateHardware: %v", diags)
+	}
+	if interfaces, ok := hardware["interfaces"].([]string); !ok || len(interfaces) != 0 {
+		t.Errorf("interfaces = %#v, se esperaba una lista vacía", hardware["interfaces"])
+	}
+}
+
+// testTemplateSourceDesktop crea un desktop detenido a partir del que crear templates
+func testTemplateSourceDesktop(t *testing.T, c *client.Client) string {
+	t.Helper()
+	ctx := context.Background()
+
+	desktopID, err := c.CreatePersistentDesktop(ctx, "origen", "", testserver.DefaultTemplateID, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreatePersistentDesktop: %v", err)
+	}
+	if err := c.WaitForDesktopStopped(ctx, desktopID, 10*time.Second); err != nil {
+		t.Fatalf("WaitForDesktopStopped: %v", err)
+	}
+	return desktopID
+}
+
+// testAccTemplateSourceConfig es el desktop a partir del que se crean los templates de los tests
+var testAccTemplateSourceConfig = fmt.Sprintf(`
+resource "isardvdi_vm" "source" {
+  name        = "tf-acc-origen"
+  template_id = %q
+}
+`, testserver.DefaultTemplateID)
+
+// testAccTemplateConfig devuelve un isardvdi_template sobre el desktop de origen con los
+// argumentos adicionales indicados
+func testAccTemplateConfig(name, extra string) string {
+	return testAccTemplateSourceConfig + fmt.Sprintf(`
+resource "isardvdi_template" "test" {
+  desktop_id = isardvdi_vm.source.id
+  name       = %q
+%s}
+`, name, extra)
+}
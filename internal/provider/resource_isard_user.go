@@ -0,0 +1,468 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &userResource{}
+	_ resource.ResourceWithConfigure   = &userResource{}
+	_ resource.ResourceWithImportState = &userResource{}
+	_ resource.ResourceWithModifyPlan  = &userResource{}
+)
+
+// Timeouts por defecto de las operaciones sobre usuarios
+const (
+	defaultUserCreateTimeout = 5 * time.Minute
+	defaultUserReadTimeout   = 5 * time.Minute
+	defaultUserUpdateTimeout = 5 * time.Minute
+	defaultUserDeleteTimeout = 5 * time.Minute
+)
+
+// userRoles son los roles de usuario que admite Isard VDI
+var userRoles = []string{"admin", "manager", "advanced", "user"}
+
+// NewUserResource is a helper function to simplify the provider implementation.
+func NewUserResource() resource.Resource {
+	return &userResource{}
+}
+
+// userResource is the resource implementation.
+type userResource struct {
+	client *client.Client
+}
+
+// userResourceModel maps the resource schema data.
+type userResourceModel struct {
+	ID              types.String   `tfsdk:"id"`
+	Username        types.String   `tfsdk:"username"`
+	Name            types.String   `tfsdk:"name"`
+	Email           types.String   `tfsdk:"email"`
+	Role            types.String   `tfsdk:"role"`
+	Category        types.String   `tfsdk:"category"`
+	Group           types.String   `tfsdk:"group"`
+	SecondaryGroups types.List     `tfsdk:"secondary_groups"`
+	Active          types.Bool     `tfsdk:"active"`
+	Password        types.String   `tfsdk:"password"`
+	PasswordVersion types.Int64    `tfsdk:"password_version"`
+	Timeouts        timeouts.Value `tfsdk:"timeouts"`
+}
+
+// Metadata returns the resource type name.
+func (r *userResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user"
+}
+
+// Schema defines the schema for the resource.
+func (r *userResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Gestiona un usuario local de Isard VDI (requiere rol de administrador o manager).",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identificador único del usuario",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"username": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Nombre de usuario para iniciar sesión. Cambiarlo recrea el usuario.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Nombre completo del usuario",
+			},
+			"email": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Correo electrónico del usuario",
+			},
+			"role": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Rol del usuario: `admin`, `manager`, `advanced` o `user`",
+				Validators: []validator.String{
+					stringvalidator.OneOf(userRoles...),
+				},
+			},
+			"category": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "ID de la categoría del usuario. Cambiarlo recrea el usuario.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"group": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "ID del grupo principal del usuario. Debe pertenecer a `category`.",
+			},
+			"secondary_groups": schema.ListAttribute{
+				ElementType:         types.StringType,
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "IDs de los grupos secundarios del usuario (el orden no es significativo)",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"active": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+				MarkdownDescription: "Si el usuario puede iniciar sesión (por defecto: true)",
+			},
+			"password": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				WriteOnly:           true,
+				MarkdownDescription: "Contraseña del usuario. Es de solo escritura: no se guarda en el estado y solo se envía al crear el usuario o cuando cambia `password_version`. Obligatoria al crear. Requiere Terraform 1.11 o superior.",
+			},
+			"password_version": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Versión de la contraseña. Como `password` no se guarda en el estado, hay que cambiar este valor para que se envíe la nueva contraseña; al cambiarlo, `password` es obligatoria.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *userResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Create creates a new resource.
+func (r *userResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan userResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// password es de solo escritura: su valor solo está en la configuración
+	var password types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password"), &password)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if password.IsNull() || password.ValueString() == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("password"),
+			"Contraseña requerida",
+			"Es obligatorio indicar password al crear un usuario.",
+		)
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultUserCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	secondaryGroups := listToStrings(ctx, plan.SecondaryGroups, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	userID, err := r.client.CreateUser(ctx, client.CreateUserRequest{
+		Username:        plan.Username.ValueString(),
+		Name:            plan.Name.ValueString(),
+		Email:           plan.Email.ValueString(),
+		Password:        password.ValueString(),
+		Role:            plan.Role.ValueString(),
+		Category:        plan.Category.ValueString(),
+		Group:           plan.Group.ValueString(),
+		SecondaryGroups: secondaryGroups,
+		Active:          plan.Active.ValueBool(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creando usuario",
+			clientErrorDetail("No se pudo crear el usuario "+plan.Username.ValueString(), err),
+		)
+		return
+	}
+
+	// Guardar el ID antes de leer para no perder el usuario si la lectura falla
+	plan.ID = types.StringValue(userID)
+	plan.Password = types.StringNull()
+
+	user, err := r.client.GetUser(ctx, userID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error leyendo usuario creado",
+			"No se pudo leer el usuario recién creado: "+err.Error(),
+		)
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		return
+	}
+
+	plan.SecondaryGroups = unorderedListValue(ctx, plan.SecondaryGroups, user.SecondaryGroups)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *userResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state userResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultUserReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	user, err := r.client.GetUser(ctx, state.ID.ValueString())
+	if err != nil {
+		if client.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error leyendo usuario",
+			"No se pudo leer el usuario ID "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	state.Username = types.StringValue(user.Username)
+	state.Name = types.StringValue(user.Name)
+	// email es opcional sin valor computado: "" en la API equivale a no configurarlo
+	if user.Email != "" || !state.Email.IsNull() {
+		state.Email = types.StringValue(user.Email)
+	}
+	state.Role = types.StringValue(user.Role)
+	state.Category = types.StringValue(user.Category)
+	state.Group = types.StringValue(user.Group)
+	state.SecondaryGroups = unorderedListValue(ctx, state.SecondaryGroups, user.SecondaryGroups)
+	state.Active = types.BoolValue(user.Active)
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *userResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state userResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultUserUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	// Preparar los valores a actualizar (solo los que cambiaron)
+	updateData := map[string]interface{}{}
+
+	if !plan.Name.Equal(state.Name) {
+		updateData["name"] = plan.Name.ValueString()
+	}
+	if !plan.Email.Equal(state.Email) {
+		updateData["email"] = plan.Email.ValueString()
+	}
+	if !plan.Role.Equal(state.Role) {
+		updateData["role"] = plan.Role.ValueString()
+	}
+	if !plan.Group.Equal(state.Group) {
+		updateData["group"] = plan.Group.ValueString()
+	}
+	if !plan.SecondaryGroups.IsUnknown() && !plan.SecondaryGroups.Equal(state.SecondaryGroups) {
+		secondaryGroups := listToStrings(ctx, plan.SecondaryGroups, &resp.Diagnostics)
+		if secondaryGroups == nil {
+			secondaryGroups = []string{}
+		}
+		updateData["secondary_groups"] = secondaryGroups
+	}
+	if !plan.Active.Equal(state.Active) {
+		updateData["active"] = plan.Active.ValueBool()
+	}
+
+	// La contraseña solo se envía cuando cambia password_version. Sin password, guardar
+	// la nueva versión daría la rotación por hecha sin haber cambiado la contraseña.
+	if !plan.PasswordVersion.Equal(state.PasswordVersion) {
+		var password types.String
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password"), &password)...)
+		if !password.IsNull() && password.ValueString() != "" {
+			updateData["password"] = password.ValueString()
+		} else if !plan.PasswordVersion.IsNull() && !resp.Diagnostics.HasError() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("password"),
+				"Contraseña requerida",
+				"Es obligatorio indicar password al cambiar password_version.",
+			)
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if len(updateData) > 0 {
+		err := r.client.UpdateUser(ctx, plan.ID.ValueString(), updateData)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error actualizando usuario",
+				clientErrorDetail("No se pudo actualizar el usuario ID "+plan.ID.ValueString(), err),
+			)
+			return
+		}
+	}
+
+	user, err := r.client.GetUser(ctx, plan.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error leyendo usuario actualizado",
+			"No se pudo leer el usuario actualizado: "+err.Error(),
+		)
+		return
+	}
+
+	plan.Password = types.StringNull()
+	plan.SecondaryGroups = unorderedListValue(ctx, plan.SecondaryGroups, user.SecondaryGroups)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *userResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state userResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultUserDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	err := r.client.DeleteUser(ctx, state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error eliminando usuario",
+			clientErrorDetail("No se pudo eliminar el usuario ID "+state.ID.ValueString(), err),
+		)
+		return
+	}
+}
+
+// ModifyPlan comprueba al planificar que haya password al crear el usuario y al cambiar
+// password_version, para que el error no aparezca por primera vez en el apply. password es de
+// solo escritura y no está en el plan: se lee de la configuración.
+func (r *userResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nada que comprobar en la destrucción
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var password types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password"), &password)...)
+	if resp.Diagnostics.HasError() || password.IsUnknown() || password.ValueString() != "" {
+		return
+	}
+
+	if req.State.Raw.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("password"),
+			"Contraseña requerida",
+			"Es obligatorio indicar password al crear un usuario.",
+		)
+		return
+	}
+
+	var planVersion, stateVersion types.Int64
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("password_version"), &planVersion)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("password_version"), &stateVersion)...)
+	if resp.Diagnostics.HasError() || planVersion.IsNull() || planVersion.IsUnknown() {
+		return
+	}
+	if !planVersion.Equal(stateVersion) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("password"),
+			"Contraseña requerida",
+			"Es obligatorio indicar password al cambiar password_version.",
+		)
+	}
+}
+
+// ImportState importa un usuario existente a partir de su ID. La contraseña no se puede
+// leer de la API: para gestionarla, hay que fijar password_version tras la importación.
+func (r *userResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
@@ -0,0 +1,231 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/tknika/terraform-provider-isardvdi/internal/testserver"
+)
+
+func TestAccUserResource(t *testing.T) {
+	srv := testAccServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed("isardvdi_user", srv.User),
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + testAccUserConfig("Ana", `
+  password         = "secreto-1"
+  password_version = 1
+`),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttrSet("isardvdi_user.test", "id"),
+					tfresource.TestCheckResourceAttr("isardvdi_user.test", "active", "true"),
+					tfresource.TestCheckResourceAttr("isardvdi_user.test", "secondary_groups.#", "0"),
+					tfresource.TestCheckNoResourceAttr("isardvdi_user.test", "password"),
+					testAccCheckUserPasswordSent(srv, http.MethodPost, "/api/v3/admin/user", "secreto-1"),
+				),
+			},
+			{
+				// Cambiar password_version envía la nueva contraseña
+				Config: testAccProviderConfig(srv) + testAccUserConfig("Ana", `
+  password         = "secreto-2"
+  password_version = 2
+`),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("isardvdi_user.test", "password_version", "2"),
+					tfresource.TestCheckNoResourceAttr("isardvdi_user.test", "password"),
+					testAccCheckUserPasswordSent(srv, http.MethodPut, "/api/v3/admin/user/local-default-ana-ana", "secreto-2"),
+				),
+			},
+			{
+				// Sin cambiar password_version, la contraseña no se reenvía
+				Config: testAccProviderConfig(srv) + testAccUserConfig("Ana López", `
+  email            = "ana@example.com"
+  active           = false
+  password         = "secreto-3"
+  password_version = 2
+`),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("isardvdi_user.test", "name", "Ana López"),
+					tfresource.TestCheckResourceAttr("isardvdi_user.test", "active", "false"),
+					testAccCheckUserPasswordSent(srv, http.MethodPut, "/api/v3/admin/user/local-default-ana-ana", ""),
+				),
+			},
+			{
+				ResourceName:            "isardvdi_user.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password_version"},
+			},
+			{
+				Config: testAccProviderConfig(srv) + testAccUserConfig("Ana López", `
+  email            = "ana@example.com"
+  active           = false
+  password_version = 3
+`),
+				ExpectError: regexp.MustCompile(`Contraseña requerida`),
+			},
+		},
+	})
+}
+
+func TestUserUpdatePasswordVersion(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+	r := &userResource{client: c}
+
+	attrs := map[string]interface{}{
+		"username": "ana",
+		"name":     "Ana",
+		"role":     "user",
+		"category": testserver.DefaultCategoryID,
+		"group":    testserver.DefaultGroupID,
+		"active":   true,
+	}
+	createConfig := copyAttrs(attrs, map[string]interface{}{"password": "secreto-1", "password_version": int64(1)})
+	createResp := resource.CreateResponse{State: newState(t, r, nil)}
+	r.Create(ctx, resource.CreateRequest{
+		Plan:   newPlan(t, r, copyAttrs(createConfig, map[string]interface{}{"password": nil})),
+		Config: newConfig(t, r, createConfig),
+	}, &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("Create: %v", createResp.Diagnostics)
+	}
+	userID := "local-default-ana-ana"
+
+	update := func(config map[string]interface{}) resource.UpdateResponse {
+		config = copyAttrs(config, map[string]interface{}{"id": userID})
+		resp := resource.UpdateResponse{State: createResp.State}
+		r.Update(ctx, resource.UpdateRequest{
+			Plan:   newPlan(t, r, copyAttrs(config, map[string]interface{}{"password": nil})),
+			Config: newConfig(t, r, config),
+			State:  createResp.State,
+		}, &resp)
+		return resp
+	}
+
+	// Misma versión: la contraseña de la configuración no se envía
+	resp := update(copyAttrs(attrs, map[string]interface{}{"name": "Ana López", "password": "secreto-2", "password_version": int64(1)}))
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Update: %v", resp.Diagnostics)
+	}
+	req, _ := srv.LastRequest(http.MethodPut, "/api/v3/admin/user/"+userID)
+	if _, ok := req.JSON()["password"]; ok {
+		t.Error("no se debe enviar la contraseña si no cambia password_version")
+	}
+
+	// Nueva versión: se envía la contraseña
+	resp = update(copyAttrs(attrs, map[string]interface{}{"password": "secreto-2", "password_version": int64(2)}))
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Update: %v", resp.Diagnostics)
+	}
+	req, _ = srv.LastRequest(http.MethodPut, "/api/v3/admin/user/"+userID)
+	if req.JSON()["password"] != "secreto-2" {
+		t.Errorf("password enviada = %v, se esperaba secreto-2", req.JSON()["password"])
+	}
+
+	// Nueva versión sin contraseña: error y no se actualiza nada
+	requests := len(srv.Requests())
+	resp = update(copyAttrs(attrs, map[string]interface{}{"password_version": int64(3)}))
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("se esperaba un error por falta de contraseña")
+	}
+	if got := len(srv.Requests()); got != requests {
+		t.Errorf("se han enviado %d peticiones, se esperaba ninguna", got-requests)
+	}
+}
+
+func TestUserModifyPlanRequiresPassword(t *testing.T) {
+	ctx := context.Background()
+	r := &userResource{}
+
+	attrs := map[string]interface{}{
+		"username": "ana",
+		"name":     "Ana",
+		"role":     "user",
+		"category": testserver.DefaultCategoryID,
+		"group":    testserver.DefaultGroupID,
+		"active":   true,
+	}
+	state := newState(t, r, copyAttrs(attrs, map[string]interface{}{"id": "ana-id", "password_version": int64(1)}))
+
+	tests := []struct {
+		name      string
+		state     tfsdk.State
+		config    map[string]interface{}
+		wantError bool
+	}{
+		{"crear sin password", newState(t, r, nil), attrs, true},
+		{"crear con password", newState(t, r, nil), copyAttrs(attrs, map[string]interface{}{"password": "secreto"}), false},
+		{"crear con password desconocida", newState(t, r, nil), copyAttrs(attrs, map[string]interface{}{"password": types.StringUnknown()}), false},
+		{"actualizar sin cambiar la versión", state, copyAttrs(attrs, map[string]interface{}{"password_version": int64(1)}), false},
+		{"nueva versión sin password", state, copyAttrs(attrs, map[string]interface{}{"password_version": int64(2)}), true},
+		{"nueva versión con password", state, copyAttrs(attrs, map[string]interface{}{"password": "secreto", "password_version": int64(2)}), false},
+	}
+
+	for _, tt := range tests {
+		plan := newPlan(t, r, copyAttrs(tt.config, map[string]interface{}{"password": nil}))
+		resp := resource.ModifyPlanResponse{Plan: plan}
+		r.ModifyPlan(ctx, resource.ModifyPlanRequest{State: tt.state, Plan: plan, Config: newConfig(t, r, tt.config)}, &resp)
+		if got := resp.Diagnostics.HasError(); got != tt.wantError {
+			t.Errorf("%s: ModifyPlan error = %t, se esperaba %t (%v)", tt.name, got, tt.wantError, resp.Diagnostics)
+		}
+	}
+}
+
+// copyAttrs devuelve una copia de attrs con los valores de extra añadidos o sustituidos; un
+// valor nil quita el atributo
+func copyAttrs(attrs, extra map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(attrs)+len(extra))
+	for k, v := range attrs {
+		out[k] = v
+	}
+	for k, v := range extra {
+		if v == nil {
+			delete(out, k)
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// testAccCheckUserPasswordSent comprueba la contraseña enviada en la última petición al
+// endpoint indicado ("" si no se debe haber enviado)
+func testAccCheckUserPasswordSent(srv *testserver.Server, method, endpoint, want string) tfresource.TestCheckFunc {
+	return func(*terraform.State) error {
+		req, ok := srv.LastRequest(method, endpoint)
+		if !ok {
+			return fmt.Errorf("no se ha enviado %s %s", method, endpoint)
+		}
+		got, _ := req.JSON()["password"].(string)
+		if got != want {
+			return fmt.Errorf("password enviada = %q, se esperaba %q", got, want)
+		}
+		return nil
+	}
+}
+
+// testAccUserConfig devuelve un isardvdi_user "ana" en la categoría y el grupo por defecto
+// con el nombre y los argumentos adicionales indicados
+func testAccUserConfig(name, extra string) string {
+	return fmt.Sprintf(`
+resource "isardvdi_user" "test" {
+  username = "ana"
+  name     = %q
+  role     = "user"
+  category = %q
+  group    = %q
+%s}
+`, name, testserver.DefaultCategoryID, testserver.DefaultGroupID, extra)
+}
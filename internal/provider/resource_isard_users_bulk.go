@@ -0,0 +1,911 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &usersBulkResource{}
+	_ resource.ResourceWithConfigure      = &usersBulkResource{}
+	_ resource.ResourceWithModifyPlan     = &usersBulkResource{}
+	_ resource.ResourceWithValidateConfig = &usersBulkResource{}
+)
+
+// Timeouts por defecto de las operaciones sobre altas masivas de usuarios
+const (
+	defaultUsersBulkCreateTimeout = 30 * time.Minute
+	defaultUsersBulkReadTimeout   = 5 * time.Minute
+	defaultUsersBulkUpdateTimeout = 30 * time.Minute
+	defaultUsersBulkDeleteTimeout = 30 * time.Minute
+)
+
+// bulkUserBatchSize es el número máximo de usuarios por petición de alta masiva
+const bulkUserBatchSize = 100
+
+// bulkUserCSVColumns son las columnas admitidas en el CSV de usuarios
+var bulkUserCSVColumns = []string{"username", "name", "email", "role", "group", "password"}
+
+// NewUsersBulkResource is a helper function to simplify the provider implementation.
+func NewUsersBulkResource() resource.Resource {
+	return &usersBulkResource{}
+}
+
+// usersBulkResource is the resource implementation.
+type usersBulkResource struct {
+	client *client.Client
+}
+
+// usersBulkResourceModel maps the resource schema data.
+type usersBulkResourceModel struct {
+	ID                types.String   `tfsdk:"id"`
+	Category          types.String   `tfsdk:"category"`
+	Group             types.String   `tfsdk:"group"`
+	CSV               types.String   `tfsdk:"csv"`
+	CSVSHA256         types.String   `tfsdk:"csv_sha256"`
+	Users             types.List     `tfsdk:"users"`
+	DefaultPassword   types.String   `tfsdk:"default_password"`
+	PasswordVersion   types.Int64    `tfsdk:"password_version"`
+	DeactivateRemoved types.Bool     `tfsdk:"deactivate_removed"`
+	UserIDs           types.Map      `tfsdk:"user_ids"`
+	ManagedUsers      types.List     `tfsdk:"managed_users"`
+	Timeouts          timeouts.Value `tfsdk:"timeouts"`
+}
+
+// bulkUserModel maps each element of the users list.
+type bulkUserModel struct {
+	Username types.String `tfsdk:"username"`
+	Name     types.String `tfsdk:"name"`
+	Email    types.String `tfsdk:"email"`
+	Role     types.String `tfsdk:"role"`
+	Group    types.String `tfsdk:"group"`
+	Password types.String `tfsdk:"password"`
+}
+
+// bulkUserEntry es un usuario deseado, ya con los valores por defecto aplicados
+type bulkUserEntry struct {
+	Username string
+	Name     string
+	Email    string
+	Role     string
+	Group    string
+	Password string
+}
+
+// Metadata returns the resource type name.
+func (r *usersBulkResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_users_bulk"
+}
+
+// Schema defines the schema for the resource.
+func (r *usersBulkResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Da de alta y mantiene sincronizado un conjunto de usuarios locales de Isard VDI a partir de un CSV o de una lista (requiere rol de administrador o manager).",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identificador del recurso con formato `<category>/<group>`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"category": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "ID de la categoría de todos los usuarios. Cambiarlo recrea el recurso.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"group": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "ID del grupo por defecto de los usuarios que no indican uno",
+			},
+			"csv": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				WriteOnly:           true,
+				MarkdownDescription: "Documento CSV con cabecera. Columnas: `username` y `name` (obligatorias), `email`, `role`, `group` y `password`. Incompatible con `users`. Es de solo escritura porque puede contener contraseñas: en el estado solo se guarda `csv_sha256`. Requiere Terraform 1.11 o superior.",
+			},
+			"csv_sha256": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "SHA-256 de los usuarios del CSV sin la columna `password`. Detecta los cambios en `csv`, que no se guarda en el estado, y los usuarios modificados fuera de Terraform.",
+			},
+			"users": schema.ListNestedAttribute{
+				Optional:            true,
+				MarkdownDescription: "Lista de usuarios. Incompatible con `csv`.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"username": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "Nombre de usuario para iniciar sesión",
+						},
+						"name": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "Nombre completo del usuario",
+						},
+						"email": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "Correo electrónico del usuario",
+						},
+						"role": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "Rol del usuario (por defecto: `user`)",
+							Validators: []validator.String{
+								stringvalidator.OneOf(userRoles...),
+							},
+						},
+						"group": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "ID del grupo del usuario (por defecto: `group` del recurso)",
+						},
+						"password": schema.StringAttribute{
+							Optional:            true,
+							Sensitive:           true,
+							WriteOnly:           true,
+							MarkdownDescription: "Contraseña inicial (por defecto: `default_password`). Es de solo escritura: solo se envía al crear el usuario o cuando cambia `password_version`. Requiere Terraform 1.11 o superior.",
+						},
+					},
+				},
+			},
+			"default_password": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				WriteOnly:           true,
+				MarkdownDescription: "Contraseña inicial de los usuarios que no indican una. Es de solo escritura: solo se envía al crear los usuarios o cuando cambia `password_version`. Requiere Terraform 1.11 o superior.",
+			},
+			"password_version": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Versión de las contraseñas. Como las contraseñas no se guardan en el estado, hay que cambiar este valor para que se envíen de nuevo a todos los usuarios; al cambiarlo, cada usuario necesita `password` o `default_password`.",
+			},
+			"deactivate_removed": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Si es true, los usuarios que se quitan del CSV o de la lista, o todos al destruir el recurso, se desactivan. Si es false, se dejan de gestionar sin modificarlos (por defecto: false)",
+			},
+			"user_ids": schema.MapAttribute{
+				ElementType:         types.StringType,
+				Computed:            true,
+				MarkdownDescription: "IDs de los usuarios sincronizados, indexados por username",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"managed_users": schema.ListAttribute{
+				ElementType:         types.StringType,
+				Computed:            true,
+				MarkdownDescription: "Usernames de todos los usuarios gestionados por el recurso, estén sincronizados o no (ordenados)",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// ValidateConfig comprueba que se use exactamente uno de csv y users, que el CSV sea válido
+// y que no se repita ningún username.
+func (r *usersBulkResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config usersBulkResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.CSV.IsNull() && !config.Users.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("csv"),
+			"Configuración no válida",
+			"csv y users son incompatibles: usa solo uno de los dos.",
+		)
+		return
+	}
+	if config.CSV.IsNull() && config.Users.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("csv"),
+			"Configuración no válida",
+			"Es obligatorio indicar csv o users.",
+		)
+		return
+	}
+
+	if !config.CSV.IsUnknown() && !config.CSV.IsNull() {
+		if _, err := parseUsersCSV(config.CSV.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("csv"), "CSV de usuarios no válido", err.Error())
+		}
+	}
+
+	if !config.Users.IsUnknown() && !config.Users.IsNull() {
+		var users []bulkUserModel
+		resp.Diagnostics.Append(config.Users.ElementsAs(ctx, &users, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		// Los usernames que dependen de otros recursos se comprueban al planificar
+		seen := make(map[string]bool, len(users))
+		for i, user := range users {
+			if user.Username.IsUnknown() {
+				continue
+			}
+			if seen[user.Username.ValueString()] {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("users").AtListIndex(i).AtName("username"),
+					"Usuario duplicado",
+					"El username "+user.Username.ValueString()+" aparece más de una vez.",
+				)
+			}
+			seen[user.Username.ValueString()] = true
+		}
+	}
+}
+
+// ModifyPlan fija managed_users y csv_sha256 a partir de los usuarios deseados y marca
+// user_ids como desconocido cuando el conjunto de usuarios deseado no coincide con el
+// sincronizado (usuarios añadidos, quitados o que han cambiado en Isard VDI), para que el
+// plan muestre la reconciliación.
+func (r *usersBulkResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nada que hacer al crear (los atributos computados ya son desconocidos) ni al destruir
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// csv es de solo escritura: los usuarios deseados solo están en la configuración
+	var plan, config usersBulkResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	entries, known := plannedBulkUserEntries(ctx, config, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	managed := types.ListUnknown(types.StringType)
+	csvHash := types.StringUnknown()
+	if known {
+		managed = managedUsersValue(entries)
+		csvHash = csvSHA256Value(config, entries)
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("managed_users"), managed)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("csv_sha256"), csvHash)...)
+	if resp.Diagnostics.HasError() || plan.UserIDs.IsUnknown() {
+		return
+	}
+
+	synced := map[string]string{}
+	resp.Diagnostics.Append(plan.UserIDs.ElementsAs(ctx, &synced, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	inSync := known && len(entries) == len(synced)
+	for _, entry := range entries {
+		if _, ok := synced[entry.Username]; !ok {
+			inSync = false
+		}
+	}
+	if !inSync {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("user_ids"), types.MapUnknown(types.StringType))...)
+	}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *usersBulkResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *usersBulkResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan, config usersBulkResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultUsersBulkCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	ids, entries := r.reconcile(ctx, plan, config, nil, false, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = types.StringValue(plan.Category.ValueString() + "/" + plan.Group.ValueString())
+	plan.UserIDs = userIDsValue(ids)
+	plan.ManagedUsers = managedUsersValue(entries)
+	plan.CSVSHA256 = csvSHA256Value(config, entries)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *usersBulkResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state usersBulkResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultUsersBulkReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	existing, err := r.categoryUsers(ctx, state.Category.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error leyendo usuarios",
+			clientErrorDetail("No se pudieron listar los usuarios de la categoría "+state.Category.ValueString(), err),
+		)
+		return
+	}
+
+	// Solo se consideran sincronizados los usuarios que existen y coinciden con lo deseado;
+	// el resto se quita de user_ids para que ModifyPlan proponga reconciliarlos. managed_users
+	// no cambia: los usuarios siguen gestionados aunque no estén sincronizados.
+	ids := map[string]string{}
+	if state.Users.IsNull() {
+		// El CSV no está en el estado: csv_sha256 se recalcula con los usuarios gestionados
+		// tal como están en Isard VDI, y cambia si alguno se ha borrado, desactivado o modificado
+		managed := listToStrings(ctx, state.ManagedUsers, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		var current []bulkUserEntry
+		for _, username := range managed {
+			user, ok := existing[username]
+			if !ok || !user.Active {
+				continue
+			}
+			ids[username] = user.ID
+			current = append(current, bulkUserEntry{
+				Username: user.Username,
+				Name:     user.Name,
+				Email:    user.Email,
+				Role:     user.Role,
+				Group:    user.Group,
+			})
+		}
+		state.CSVSHA256 = types.StringValue(bulkUsersSHA256(current))
+	} else {
+		entries := bulkUserEntries(ctx, state, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		for _, entry := range entries {
+			user, ok := existing[entry.Username]
+			if ok && len(bulkUserChanges(user, entry)) == 0 {
+				ids[entry.Username] = user.ID
+			}
+		}
+	}
+	state.UserIDs = userIDsValue(ids)
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *usersBulkResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state, config usersBulkResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultUsersBulkUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	previous := listToStrings(ctx, state.ManagedUsers, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Las contraseñas solo se reenvían cuando cambia password_version
+	rotate := !plan.PasswordVersion.IsNull() && !plan.PasswordVersion.Equal(state.PasswordVersion)
+
+	ids, entries := r.reconcile(ctx, plan, config, previous, rotate, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = types.StringValue(plan.Category.ValueString() + "/" + plan.Group.ValueString())
+	plan.UserIDs = userIDsValue(ids)
+	plan.ManagedUsers = managedUsersValue(entries)
+	plan.CSVSHA256 = csvSHA256Value(config, entries)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *usersBulkResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state usersBulkResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Sin deactivate_removed, los usuarios se dejan de gestionar sin modificarlos
+	if !state.DeactivateRemoved.ValueBool() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultUsersBulkDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	// Se desactivan todos los usuarios gestionados, también los que no están sincronizados
+	managed := listToStrings(ctx, state.ManagedUsers, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	category := state.Category.ValueString()
+	existing, err := r.categoryUsers(ctx, category)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error leyendo usuarios",
+			clientErrorDetail("No se pudieron listar los usuarios de la categoría "+category, err),
+		)
+		return
+	}
+
+	r.deactivateUsers(ctx, existing, managed, &resp.Diagnostics)
+}
+
+// reconcile crea los usuarios que faltan, actualiza los que han cambiado y, con
+// deactivate_removed, desactiva los usernames de previous que ya no se desean. Con rotate
+// envía también la contraseña de los usuarios que ya existen. Los usuarios deseados y las
+// contraseñas se leen de config, porque son de solo escritura.
+// Devuelve los IDs de los usuarios deseados indexados por username y los usuarios deseados.
+func (r *usersBulkResource) reconcile(ctx context.Context, plan, config usersBulkResourceModel, previous []string, rotate bool, diags *diag.Diagnostics) (map[string]string, []bulkUserEntry) {
+	entries := bulkUserEntries(ctx, config, diags)
+	if diags.HasError() {
+		return nil, nil
+	}
+
+	category := plan.Category.ValueString()
+	existing, err := r.categoryUsers(ctx, category)
+	if err != nil {
+		diags.AddError(
+			"Error leyendo usuarios",
+			clientErrorDetail("No se pudieron listar los usuarios de la categoría "+category, err),
+		)
+		return nil, nil
+	}
+
+	// Comprobar las contraseñas antes de modificar nada
+	var toCreate []client.CreateUserRequest
+	passwords := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		password := entry.Password
+		if password == "" {
+			password = config.DefaultPassword.ValueString()
+		}
+		_, exists := existing[entry.Username]
+		if exists && !rotate {
+			continue
+		}
+		if password == "" {
+			detail := "El usuario " + entry.Username + " no existe y no tiene contraseña: indica password o default_password."
+			if exists {
+				detail = "El usuario " + entry.Username + " no tiene contraseña: indica password o default_password al cambiar password_version."
+			}
+			diags.AddError("Contraseña requerida", detail)
+			continue
+		}
+		if exists {
+			passwords[entry.Username] = password
+			continue
+		}
+		toCreate = append(toCreate, client.CreateUserRequest{
+			Username: entry.Username,
+			Name:     entry.Name,
+			Email:    entry.Email,
+			Password: password,
+			Role:     entry.Role,
+			Category: category,
+			Group:    entry.Group,
+			Active:   true,
+		})
+	}
+	if diags.HasError() {
+		return nil, nil
+	}
+
+	for _, entry := range entries {
+		user, ok := existing[entry.Username]
+		if !ok {
+			continue
+		}
+		changes := bulkUserChanges(user, entry)
+		if password, ok := passwords[entry.Username]; ok {
+			changes["password"] = password
+		}
+		if len(changes) > 0 {
+			if err := r.client.UpdateUser(ctx, user.ID, changes); err != nil {
+				diags.AddError(
+					"Error actualizando usuario",
+					clientErrorDetail("No se pudo actualizar el usuario "+entry.Username, err),
+				)
+				return nil, nil
+			}
+		}
+	}
+
+	for start := 0; start < len(toCreate); start += bulkUserBatchSize {
+		end := min(start+bulkUserBatchSize, len(toCreate))
+		if err := r.client.CreateUsers(ctx, toCreate[start:end]); err != nil {
+			diags.AddError(
+				"Error creando usuarios",
+				clientErrorDetail(fmt.Sprintf("No se pudieron crear %d usuarios", end-start), err),
+			)
+			return nil, nil
+		}
+	}
+
+	if plan.DeactivateRemoved.ValueBool() {
+		wanted := make(map[string]bool, len(entries))
+		for _, entry := range entries {
+			wanted[entry.Username] = true
+		}
+		var removed []string
+		for _, username := range previous {
+			if !wanted[username] {
+				removed = append(removed, username)
+			}
+		}
+		r.deactivateUsers(ctx, existing, removed, diags)
+		if diags.HasError() {
+			return nil, nil
+		}
+	}
+
+	// Releer para obtener los IDs de los usuarios creados
+	if len(toCreate) > 0 {
+		existing, err = r.categoryUsers(ctx, category)
+		if err != nil {
+			diags.AddError(
+				"Error leyendo usuarios creados",
+				clientErrorDetail("No se pudieron listar los usuarios de la categoría "+category, err),
+			)
+			return nil, nil
+		}
+	}
+
+	ids := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		user, ok := existing[entry.Username]
+		if !ok {
+			diags.AddError(
+				"Error leyendo usuarios creados",
+				"El usuario "+entry.Username+" no aparece en la categoría "+category+" tras crearlo.",
+			)
+			return nil, nil
+		}
+		ids[entry.Username] = user.ID
+	}
+
+	return ids, entries
+}
+
+// deactivateUsers desactiva los usuarios indicados por username que existen y siguen activos
+func (r *usersBulkResource) deactivateUsers(ctx context.Context, existing map[string]client.User, usernames []string, diags *diag.Diagnostics) {
+	for _, username := range usernames {
+		user, ok := existing[username]
+		if !ok || !user.Active {
+			continue
+		}
+		err := r.client.UpdateUser(ctx, user.ID, map[string]interface{}{"active": false})
+		if err != nil && !client.IsNotFound(err) {
+			diags.AddError(
+				"Error desactivando usuario",
+				clientErrorDetail("No se pudo desactivar el usuario "+username, err),
+			)
+			return
+		}
+	}
+}
+
+// categoryUsers devuelve los usuarios de una categoría indexados por username
+func (r *usersBulkResource) categoryUsers(ctx context.Context, category string) (map[string]client.User, error) {
+	users, err := r.client.GetUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]client.User{}
+	for _, user := range users {
+		if user.Category == category {
+			result[user.Username] = user
+		}
+	}
+	return result, nil
+}
+
+// bulkUserChanges devuelve los campos del usuario que difieren de lo deseado. Los usuarios
+// desactivados se reactivan.
+func bulkUserChanges(user client.User, entry bulkUserEntry) map[string]interface{} {
+	changes := map[string]interface{}{}
+	if user.Name != entry.Name {
+		changes["name"] = entry.Name
+	}
+	if user.Email != entry.Email {
+		changes["email"] = entry.Email
+	}
+	if user.Role != entry.Role {
+		changes["role"] = entry.Role
+	}
+	if user.Group != entry.Group {
+		changes["group"] = entry.Group
+	}
+	if !user.Active {
+		changes["active"] = true
+	}
+	return changes
+}
+
+// bulkUserEntries devuelve los usuarios deseados a partir de csv o users, con los valores
+// por defecto aplicados
+func bulkUserEntries(ctx context.Context, model usersBulkResourceModel, diags *diag.Diagnostics) []bulkUserEntry {
+	entries, _ := plannedBulkUserEntries(ctx, model, diags)
+	return entries
+}
+
+// plannedBulkUserEntries es como bulkUserEntries, pero indica además si todos los valores
+// son conocidos (en el plan pueden depender de otros recursos)
+func plannedBulkUserEntries(ctx context.Context, model usersBulkResourceModel, diags *diag.Diagnostics) ([]bulkUserEntry, bool) {
+	if model.CSV.IsUnknown() || model.Users.IsUnknown() || model.Group.IsUnknown() {
+		return nil, false
+	}
+
+	var entries []bulkUserEntry
+	known := true
+
+	if !model.CSV.IsNull() {
+		var err error
+		entries, err = parseUsersCSV(model.CSV.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("csv"), "CSV de usuarios no válido", err.Error())
+			return nil, false
+		}
+	} else if !model.Users.IsNull() {
+		var users []bulkUserModel
+		diags.Append(model.Users.ElementsAs(ctx, &users, false)...)
+		if diags.HasError() {
+			return nil, false
+		}
+		for _, user := range users {
+			if user.Username.IsUnknown() || user.Name.IsUnknown() || user.Email.IsUnknown() || user.Role.IsUnknown() || user.Group.IsUnknown() {
+				known = false
+			}
+			entries = append(entries, bulkUserEntry{
+				Username: user.Username.ValueString(),
+				Name:     user.Name.ValueString(),
+				Email:    user.Email.ValueString(),
+				Role:     user.Role.ValueString(),
+				Group:    user.Group.ValueString(),
+				Password: user.Password.ValueString(),
+			})
+		}
+	}
+
+	seen := make(map[string]bool, len(entries))
+	for i := range entries {
+		if entries[i].Role == "" {
+			entries[i].Role = "user"
+		}
+		if entries[i].Group == "" {
+			entries[i].Group = model.Group.ValueString()
+		}
+		if known && seen[entries[i].Username] {
+			diags.AddError(
+				"Usuario duplicado",
+				"El username "+entries[i].Username+" aparece más de una vez.",
+			)
+		}
+		seen[entries[i].Username] = true
+	}
+
+	return entries, known
+}
+
+// parseUsersCSV interpreta el CSV de usuarios. La primera fila es la cabecera.
+func parseUsersCSV(document string) ([]bulkUserEntry, error) {
+	reader := csv.NewReader(strings.NewReader(document))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("el CSV está vacío: falta la cabecera")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo la cabecera del CSV: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(bulkUserCSVColumns, name) {
+			return nil, fmt.Errorf("columna desconocida %q (admitidas: %s)", name, strings.Join(bulkUserCSVColumns, ", "))
+		}
+		columns[name] = i
+	}
+	for _, required := range []string{"username", "name"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("falta la columna obligatoria %q", required)
+		}
+	}
+
+	var entries []bulkUserEntry
+	seen := make(map[string]bool)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo el CSV: %w", err)
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		entry := bulkUserEntry{
+			Username: field("username"),
+			Name:     field("name"),
+			Email:    field("email"),
+			Role:     field("role"),
+			Group:    field("group"),
+			Password: field("password"),
+		}
+		if entry.Username == "" || entry.Name == "" {
+			return nil, fmt.Errorf("línea %d: username y name son obligatorios", line)
+		}
+		if entry.Role != "" && !slices.Contains(userRoles, entry.Role) {
+			return nil, fmt.Errorf("línea %d: rol %q no válido (admitidos: %s)", line, entry.Role, strings.Join(userRoles, ", "))
+		}
+		if seen[entry.Username] {
+			return nil, fmt.Errorf("línea %d: el username %s aparece más de una vez", line, entry.Username)
+		}
+		seen[entry.Username] = true
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// userIDsValue convierte el mapa de IDs por username en el atributo user_ids
+func userIDsValue(ids map[string]string) types.Map {
+	elems := make(map[string]attr.Value, len(ids))
+	for username, id := range ids {
+		elems[username] = types.StringValue(id)
+	}
+	return types.MapValueMust(types.StringType, elems)
+}
+
+// managedUsersValue devuelve los usernames de los usuarios deseados, ordenados, como el
+// atributo managed_users
+func managedUsersValue(entries []bulkUserEntry) types.List {
+	usernames := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		usernames = append(usernames, entry.Username)
+	}
+	slices.Sort(usernames)
+	return stringListValue(usernames)
+}
+
+// csvSHA256Value devuelve el atributo csv_sha256: el SHA-256 de los usuarios deseados si se
+// configura csv, o null si se usa users
+func csvSHA256Value(config usersBulkResourceModel, entries []bulkUserEntry) types.String {
+	if config.CSV.IsNull() {
+		return types.StringNull()
+	}
+	return types.StringValue(bulkUsersSHA256(entries))
+}
+
+// bulkUsersSHA256 devuelve el SHA-256 de los usuarios ordenados por username, sin las
+// contraseñas, de forma que solo cambia cuando cambia algún dato que se envía a Isard VDI
+func bulkUsersSHA256(entries []bulkUserEntry) string {
+	sorted := slices.Clone(entries)
+	slices.SortFunc(sorted, func(a, b bulkUserEntry) int {
+		return strings.Compare(a.Username, b.Username)
+	})
+
+	var document strings.Builder
+	writer := csv.NewWriter(&document)
+	for _, entry := range sorted {
+		_ = writer.Write([]string{entry.Username, entry.Name, entry.Email, entry.Role, entry.Group})
+	}
+	writer.Flush()
+
+	sum := sha256.Sum256([]byte(document.String()))
+	return hex.EncodeToString(sum[:])
+}
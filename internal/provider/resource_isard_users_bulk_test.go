@@ -0,0 +1,452 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tknika/terraform-provider-isardvdi/internal/client"
+	"github.com/tknika/terraform-provider-isardvdi/internal/testserver"
+)
+
+func TestParseUsersCSV(t *testing.T) {
+	entries, err := parseUsersCSV("Username, name,email,role\nana, Ana López,ana@example.com,advanced\nbeto,Beto,,\n")
+	if err != nil {
+		t.Fatalf("parseUsersCSV: %v", err)
+	}
+
+	want := []bulkUserEntry{
+		{Username: "ana", Name: "Ana López", Email: "ana@example.com", Role: "advanced"},
+		{Username: "beto", Name: "Beto"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("entries = %+v, se esperaba %+v", entries, want)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entries[%d] = %+v, se esperaba %+v", i, entries[i], want[i])
+		}
+	}
+}
+
+func TestParseUsersCSVErrors(t *testing.T) {
+	tests := []struct {
+		document string
+		want     string
+	}{
+		{"", "falta la cabecera"},
+		{"username,name,edad\n", "columna desconocida"},
+		{"username,email\n", `falta la columna obligatoria "name"`},
+		{"username,name\nana,\n", "línea 2"},
+		{"username,name,role\nana,Ana,root\n", "rol \"root\" no válido"},
+		{"username,name\nana,Ana,extra\n", "error leyendo el CSV"},
+		{"username,name\nana,Ana\nana,Ana Bis\n", "línea 3: el username ana aparece más de una vez"},
+	}
+
+	for _, tt := range tests {
+		_, err := parseUsersCSV(tt.document)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("parseUsersCSV(%q) = %v, se esperaba un error con %q", tt.document, err, tt.want)
+		}
+	}
+}
+
+func TestUsersBulkValidateDuplicateUsers(t *testing.T) {
+	ctx := context.Background()
+	r := &usersBulkResource{}
+
+	userType := resourceSchema(t, r).Schema.Attributes["users"].GetType().(types.ListType).ElemType.(types.ObjectType)
+	user := func(username types.String) attr.Value {
+		return types.ObjectValueMust(userType.AttrTypes, map[string]attr.Value{
+			"username": username,
+			"name":     types.StringValue("Nombre"),
+			"email":    types.StringNull(),
+			"role":     types.StringNull(),
+			"group":    types.StringNull(),
+			"password": types.StringNull(),
+		})
+	}
+
+	tests := []struct {
+		users     []attr.Value
+		wantError bool
+	}{
+		{[]attr.Value{user(types.StringValue("ana")), user(types.StringValue("beto"))}, false},
+		{[]attr.Value{user(types.StringValue("ana")), user(types.StringValue("ana"))}, true},
+		// Un username desconocido se comprueba al planificar
+		{[]attr.Value{user(types.StringValue("ana")), user(types.StringUnknown())}, false},
+	}
+
+	for i, tt := range tests {
+		config := newConfig(t, r, map[string]interface{}{
+			"category": testserver.DefaultCategoryID,
+			"group":    testserver.DefaultGroupID,
+			"users":    types.ListValueMust(userType, tt.users),
+		})
+		var resp resource.ValidateConfigResponse
+		r.ValidateConfig(ctx, resource.ValidateConfigRequest{Config: config}, &resp)
+		if got := resp.Diagnostics.HasError(); got != tt.wantError {
+			t.Errorf("caso %d: ValidateConfig error = %t, se esperaba %t (%v)", i, got, tt.wantError, resp.Diagnostics)
+		}
+	}
+}
+
+func TestUsersBulkCreate(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+	r := &usersBulkResource{client: c}
+
+	// ana ya existe, desactivada y con otro nombre: se actualiza y se reactiva
+	srv.AddUser(map[string]interface{}{
+		"id":       "ana-id",
+		"username": "ana",
+		"name":     "Ana",
+		"role":     "user",
+		"category": testserver.DefaultCategoryID,
+		"group":    testserver.DefaultGroupID,
+		"active":   false,
+	})
+
+	req := usersBulkCreateRequest(t, r, map[string]interface{}{
+		"category":         testserver.DefaultCategoryID,
+		"group":            testserver.DefaultGroupID,
+		"csv":              "username,name\nana,Ana López\nbeto,Beto\n",
+		"default_password": "secreto",
+	})
+	resp := resource.CreateResponse{State: newState(t, r, nil)}
+
+	r.Create(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Create: %v", resp.Diagnostics)
+	}
+
+	var ids map[string]string
+	resp.State.GetAttribute(ctx, path.Root("user_ids"), &ids)
+	if ids["ana"] != "ana-id" || ids["beto"] == "" || len(ids) != 2 {
+		t.Fatalf("user_ids = %v", ids)
+	}
+
+	ana, err := c.GetUser(ctx, "ana-id")
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if ana.Name != "Ana López" || !ana.Active {
+		t.Errorf("ana = %+v, se esperaba actualizada y activa", ana)
+	}
+
+	beto, err := c.GetUser(ctx, ids["beto"])
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if beto.Group != testserver.DefaultGroupID || beto.Role != "user" {
+		t.Errorf("beto = %+v, se esperaba el grupo y el rol por defecto", beto)
+	}
+}
+
+func TestUsersBulkCreateRequiresPassword(t *testing.T) {
+	c, srv := newTestClient(t)
+	r := &usersBulkResource{client: c}
+
+	req := usersBulkCreateRequest(t, r, map[string]interface{}{
+		"category": testserver.DefaultCategoryID,
+		"group":    testserver.DefaultGroupID,
+		"csv":      "username,name\nana,Ana\n",
+	})
+	resp := resource.CreateResponse{State: newState(t, r, nil)}
+
+	r.Create(context.Background(), req, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("se esperaba un error por falta de contraseña")
+	}
+	if _, ok := srv.LastRequest("POST", "/api/v3/admin/users/bulk"); ok {
+		t.Error("no se debe crear ningún usuario si falta alguna contraseña")
+	}
+}
+
+func TestUsersBulkUpdateDeactivatesRemoved(t *testing.T) {
+	c, _ := newTestClient(t)
+	ctx := context.Background()
+	r := &usersBulkResource{client: c}
+
+	attrs := map[string]interface{}{
+		"category":           testserver.DefaultCategoryID,
+		"group":              testserver.DefaultGroupID,
+		"csv":                "username,name\nana,Ana\nbeto,Beto\n",
+		"default_password":   "secreto",
+		"deactivate_removed": true,
+	}
+	createResp := resource.CreateResponse{State: newState(t, r, nil)}
+	r.Create(ctx, usersBulkCreateRequest(t, r, attrs), &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("Create: %v", createResp.Diagnostics)
+	}
+
+	var previous map[string]string
+	createResp.State.GetAttribute(ctx, path.Root("user_ids"), &previous)
+
+	// beto desaparece del CSV
+	attrs["id"] = testserver.DefaultCategoryID + "/" + testserver.DefaultGroupID
+	attrs["csv"] = "username,name\nana,Ana\n"
+	resp := resource.UpdateResponse{State: newState(t, r, nil)}
+	r.Update(ctx, usersBulkUpdateRequest(t, r, attrs, createResp.State), &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Update: %v", resp.Diagnostics)
+	}
+
+	beto, err := c.GetUser(ctx, previous["beto"])
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if beto.Active {
+		t.Error("beto se ha quitado del CSV y debería estar desactivado")
+	}
+
+	var ids map[string]string
+	resp.State.GetAttribute(ctx, path.Root("user_ids"), &ids)
+	if len(ids) != 1 || ids["ana"] != previous["ana"] {
+		t.Errorf("user_ids = %v, se esperaba solo ana", ids)
+	}
+}
+
+func TestBulkUserChanges(t *testing.T) {
+	user := client.User{Name: "Ana", Role: "user", Group: testserver.DefaultGroupID, Active: true}
+	entry := bulkUserEntry{Username: "ana", Name: "Ana", Role: "user", Group: testserver.DefaultGroupID}
+	if changes := bulkUserChanges(user, entry); len(changes) != 0 {
+		t.Errorf("changes = %v, no se esperaban cambios", changes)
+	}
+
+	user.Active = false
+	entry.Role = "advanced"
+	changes := bulkUserChanges(user, entry)
+	if changes["role"] != "advanced" || changes["active"] != true || len(changes) != 2 {
+		t.Errorf("changes = %v, se esperaba cambiar role y active", changes)
+	}
+}
+
+func TestUsersBulkPasswordVersion(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+	r := &usersBulkResource{client: c}
+
+	attrs := map[string]interface{}{
+		"category":         testserver.DefaultCategoryID,
+		"group":            testserver.DefaultGroupID,
+		"csv":              "username,name,password\nana,Ana,\nbeto,Beto,propia\n",
+		"default_password": "secreto",
+	}
+	createResp := resource.CreateResponse{State: newState(t, r, nil)}
+	r.Create(ctx, usersBulkCreateRequest(t, r, attrs), &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("Create: %v", createResp.Diagnostics)
+	}
+
+	var ids map[string]string
+	createResp.State.GetAttribute(ctx, path.Root("user_ids"), &ids)
+	attrs["id"] = testserver.DefaultCategoryID + "/" + testserver.DefaultGroupID
+
+	update := func(attrs map[string]interface{}) resource.UpdateResponse {
+		t.Helper()
+		resp := resource.UpdateResponse{State: newState(t, r, nil)}
+		r.Update(ctx, usersBulkUpdateRequest(t, r, attrs, createResp.State), &resp)
+		return resp
+	}
+	sentPassword := func(username string) interface{} {
+		req, _ := srv.LastRequest(http.MethodPut, "/api/v3/admin/user/"+ids[username])
+		return req.JSON()["password"]
+	}
+
+	// Sin cambiar password_version las contraseñas no se envían
+	if resp := update(copyAttrs(attrs, map[string]interface{}{"default_password": "nuevo"})); resp.Diagnostics.HasError() {
+		t.Fatalf("Update: %v", resp.Diagnostics)
+	}
+	if password := sentPassword("ana"); password != nil {
+		t.Errorf("contraseña enviada sin cambiar password_version = %v", password)
+	}
+
+	resp := update(copyAttrs(attrs, map[string]interface{}{"default_password": "nuevo", "password_version": int64(1)}))
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Update: %v", resp.Diagnostics)
+	}
+	if password := sentPassword("ana"); password != "nuevo" {
+		t.Errorf("contraseña de ana = %v, se esperaba default_password", password)
+	}
+	if password := sentPassword("beto"); password != "propia" {
+		t.Errorf("contraseña de beto = %v, se esperaba la de su columna password", password)
+	}
+
+	// Al rotar, todos los usuarios necesitan contraseña
+	requests := len(srv.Requests())
+	resp = update(copyAttrs(attrs, map[string]interface{}{"default_password": nil, "password_version": int64(2)}))
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Summary() != "Contraseña requerida" {
+		t.Fatalf("Update = %v, se esperaba un error por falta de contraseña", resp.Diagnostics)
+	}
+	for _, req := range srv.Requests()[requests:] {
+		if req.Method != http.MethodGet {
+			t.Errorf("se ha enviado %s %s sin contraseñas para todos los usuarios", req.Method, req.Path)
+		}
+	}
+}
+
+func TestBulkUsersSHA256(t *testing.T) {
+	entries := []bulkUserEntry{
+		{Username: "beto", Name: "Beto", Role: "user", Group: testserver.DefaultGroupID, Password: "propia"},
+		{Username: "ana", Name: "Ana", Role: "user", Group: testserver.DefaultGroupID},
+	}
+	hash := bulkUsersSHA256(entries)
+
+	// Ni el orden ni las contraseñas cambian el hash
+	reordered := []bulkUserEntry{entries[1], entries[0]}
+	reordered[1].Password = "otra"
+	if got := bulkUsersSHA256(reordered); got != hash {
+		t.Errorf("hash = %s, se esperaba %s", got, hash)
+	}
+
+	entries[0].Name = "Alberto"
+	if got := bulkUsersSHA256(entries); got == hash {
+		t.Error("el hash no cambia al cambiar el nombre de un usuario")
+	}
+}
+
+func TestUsersBulkDeactivatesDriftedUsers(t *testing.T) {
+	c, _ := newTestClient(t)
+	ctx := context.Background()
+	r := &usersBulkResource{client: c}
+
+	attrs := map[string]interface{}{
+		"category":           testserver.DefaultCategoryID,
+		"group":              testserver.DefaultGroupID,
+		"csv":                "username,name\nana,Ana\nbeto,Beto\n",
+		"default_password":   "secreto",
+		"deactivate_removed": true,
+	}
+	createResp := resource.CreateResponse{State: newState(t, r, nil)}
+	r.Create(ctx, usersBulkCreateRequest(t, r, attrs), &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("Create: %v", createResp.Diagnostics)
+	}
+
+	var created map[string]string
+	createResp.State.GetAttribute(ctx, path.Root("user_ids"), &created)
+
+	// beto se modifica fuera de Terraform: deja de estar sincronizado, pero sigue gestionado
+	if err := c.UpdateUser(ctx, created["beto"], map[string]interface{}{"name": "Otro"}); err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	readResp := resource.ReadResponse{State: createResp.State}
+	r.Read(ctx, resource.ReadRequest{State: createResp.State}, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", readResp.Diagnostics)
+	}
+
+	// El CSV no está en el estado: el cambio se refleja en csv_sha256
+	var createdHash, hash string
+	var managed []string
+	createResp.State.GetAttribute(ctx, path.Root("csv_sha256"), &createdHash)
+	readResp.State.GetAttribute(ctx, path.Root("csv_sha256"), &hash)
+	readResp.State.GetAttribute(ctx, path.Root("managed_users"), &managed)
+	if hash == createdHash {
+		t.Errorf("csv_sha256 = %s, se esperaba un valor distinto tras modificar beto", hash)
+	}
+	if strings.Join(managed, ",") != "ana,beto" {
+		t.Errorf("managed_users = %v, se esperaba ana y beto", managed)
+	}
+
+	// beto desaparece del CSV sin haberse reconciliado: también se desactiva
+	attrs["id"] = testserver.DefaultCategoryID + "/" + testserver.DefaultGroupID
+	attrs["csv"] = "username,name\nana,Ana\n"
+	resp := resource.UpdateResponse{State: newState(t, r, nil)}
+	r.Update(ctx, usersBulkUpdateRequest(t, r, attrs, readResp.State), &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Update: %v", resp.Diagnostics)
+	}
+
+	beto, err := c.GetUser(ctx, created["beto"])
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if beto.Active {
+		t.Error("beto se ha quitado del CSV y debería estar desactivado")
+	}
+	resp.State.GetAttribute(ctx, path.Root("managed_users"), &managed)
+	if strings.Join(managed, ",") != "ana" {
+		t.Errorf("managed_users = %v, se esperaba solo ana", managed)
+	}
+}
+
+func TestUsersBulkDeleteDeactivatesDriftedUsers(t *testing.T) {
+	c, _ := newTestClient(t)
+	ctx := context.Background()
+	r := &usersBulkResource{client: c}
+
+	createResp := resource.CreateResponse{State: newState(t, r, nil)}
+	r.Create(ctx, usersBulkCreateRequest(t, r, map[string]interface{}{
+		"category":           testserver.DefaultCategoryID,
+		"group":              testserver.DefaultGroupID,
+		"csv":                "username,name\nana,Ana\nbeto,Beto\n",
+		"default_password":   "secreto",
+		"deactivate_removed": true,
+	}), &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("Create: %v", createResp.Diagnostics)
+	}
+
+	var ids map[string]string
+	createResp.State.GetAttribute(ctx, path.Root("user_ids"), &ids)
+	if err := c.UpdateUser(ctx, ids["ana"], map[string]interface{}{"role": "advanced"}); err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	readResp := resource.ReadResponse{State: createResp.State}
+	r.Read(ctx, resource.ReadRequest{State: createResp.State}, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", readResp.Diagnostics)
+	}
+
+	resp := resource.DeleteResponse{State: readResp.State}
+	r.Delete(ctx, resource.DeleteRequest{State: readResp.State}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Delete: %v", resp.Diagnostics)
+	}
+
+	for username, id := range ids {
+		user, err := c.GetUser(ctx, id)
+		if err != nil {
+			t.Fatalf("GetUser: %v", err)
+		}
+		if user.Active {
+			t.Errorf("%s sigue activo tras destruir el recurso", username)
+		}
+	}
+}
+
+// usersBulkCreateRequest construye la petición de Create. csv y las contraseñas son de
+// solo escritura: Terraform solo las envía en la configuración, nunca en el plan.
+func usersBulkCreateRequest(t *testing.T, r *usersBulkResource, attrs map[string]interface{}) resource.CreateRequest {
+	t.Helper()
+
+	return resource.CreateRequest{
+		Plan:   newPlan(t, r, withoutWriteOnlyBulkAttrs(attrs)),
+		Config: newConfig(t, r, attrs),
+	}
+}
+
+// usersBulkUpdateRequest construye la petición de Update sobre el estado indicado
+func usersBulkUpdateRequest(t *testing.T, r *usersBulkResource, attrs map[string]interface{}, state tfsdk.State) resource.UpdateRequest {
+	t.Helper()
+
+	return resource.UpdateRequest{
+		Plan:   newPlan(t, r, withoutWriteOnlyBulkAttrs(attrs)),
+		Config: newConfig(t, r, attrs),
+		State:  state,
+	}
+}
+
+// withoutWriteOnlyBulkAttrs quita de attrs los atributos de solo escritura
+func withoutWriteOnlyBulkAttrs(attrs map[string]interface{}) map[string]interface{} {
+	return copyAttrs(attrs, map[string]interface{}{"csv": nil, "default_password": nil})
+}
@@ -3,20 +3,44 @@ package provider
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/float64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/tknika/terraform-provider-isardvdi/internal/client"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &vmResource{}
-	_ resource.ResourceWithConfigure = &vmResource{}
+	_ resource.Resource                = &vmResource{}
+	_ resource.ResourceWithConfigure   = &vmResource{}
+	_ resource.ResourceWithImportState = &vmResource{}
+	_ resource.ResourceWithModifyPlan  = &vmResource{}
+)
+
+// Timeouts por defecto de las operaciones sobre desktops
+const (
+	defaultVMCreateTimeout = 10 * time.Minute
+	defaultVMReadTimeout   = 5 * time.Minute
+	defaultVMUpdateTimeout = 10 * time.Minute
+	defaultVMDeleteTimeout = 5 * time.Minute
+)
+
+// Estados de energía que se pueden configurar en desired_state
+const (
+	desiredStateStarted = "started"
+	desiredStateStopped = "stopped"
 )
 
 // NewVMResource is a helper function to simplify the provider implementation.
@@ -31,17 +55,23 @@ type vmResource struct {
 
 // vmResourceModel maps the resource schema data.
 type vmResourceModel struct {
-	ID                 types.String  `tfsdk:"id"`
-	Name               types.String  `tfsdk:"name"`
-	Description        types.String  `tfsdk:"description"`
-	TemplateID         types.String  `tfsdk:"template_id"`
-	VCPUs              types.Int64   `tfsdk:"vcpus"`
-	Memory             types.Float64 `tfsdk:"memory"`
-	NetworkInterfaces  types.List    `tfsdk:"network_interfaces"`
-	ISOs               types.List    `tfsdk:"isos"`
-	Floppies           types.List    `tfsdk:"floppies"`
-	Viewers            types.List    `tfsdk:"viewers"`
-	ForceStopOnDestroy types.Bool    `tfsdk:"force_stop_on_destroy"`
+	ID                    types.String   `tfsdk:"id"`
+	Name                  types.String   `tfsdk:"name"`
+	Description           types.String   `tfsdk:"description"`
+	TemplateID            types.String   `tfsdk:"template_id"`
+	VCPUs                 types.Int64    `tfsdk:"vcpus"`
+	Memory                types.Float64  `tfsdk:"memory"`
+	NetworkInterfaces     types.List     `tfsdk:"network_interfaces"`
+	ISOs                  types.List     `tfsdk:"isos"`
+	Floppies              types.List     `tfsdk:"floppies"`
+	Viewers               types.List     `tfsdk:"viewers"`
+	BootOrder             types.List     `tfsdk:"boot_order"`
+	ForceStopOnDestroy    types.Bool     `tfsdk:"force_stop_on_destroy"`
+	StopForHardwareUpdate types.Bool     `tfsdk:"stop_for_hardware_update"`
+	DesiredState          types.String   `tfsdk:"desired_state"`
+	WaitForState          types.Bool     `tfsdk:"wait_for_state"`
+	Status                types.String   `tfsdk:"status"`
+	Timeouts              timeouts.Value `tfsdk:"timeouts"`
 }
 
 // Metadata returns the resource type name.
@@ -50,7 +80,7 @@ func (r *vmResource) Metadata(_ context.Context, req resource.MetadataRequest, r
 }
 
 // Schema defines the schema for the resource.
-func (r *vmResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *vmResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Gestiona un persistent desktop en Isard VDI.",
 		Attributes: map[string]schema.Attribute{
@@ -69,40 +99,76 @@ func (r *vmResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *r
 				Optional:            true,
 				Computed:            true,
 				MarkdownDescription: "Descripción del desktop (máximo 255 caracteres)",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"template_id": schema.StringAttribute{
 				Required:            true,
-				MarkdownDescription: "ID de la plantilla a utilizar para crear el desktop",
+				MarkdownDescription: "ID de la plantilla a utilizar para crear el desktop. Cambiarlo recrea el desktop.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"vcpus": schema.Int64Attribute{
 				Optional:            true,
 				Computed:            true,
 				MarkdownDescription: "N\u00famero de CPUs virtuales (por defecto usa el del template)",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
 			},
 			"memory": schema.Float64Attribute{
 				Optional:            true,
 				Computed:            true,
 				MarkdownDescription: "Memoria RAM en GB (por defecto usa la del template)",
+				PlanModifiers: []planmodifier.Float64{
+					float64planmodifier.UseStateForUnknown(),
+				},
 			},
 			"network_interfaces": schema.ListAttribute{
 				ElementType:         types.StringType,
 				Optional:            true,
+				Computed:            true,
 				MarkdownDescription: "Lista de IDs de interfaces de red a utilizar (por defecto usa las del template)",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"isos": schema.ListAttribute{
 				ElementType:         types.StringType,
 				Optional:            true,
+				Computed:            true,
 				MarkdownDescription: "Lista de IDs de medios ISO a adjuntar al desktop",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"floppies": schema.ListAttribute{
 				ElementType:         types.StringType,
 				Optional:            true,
+				Computed:            true,
 				MarkdownDescription: "Lista de IDs de medios floppy a adjuntar al desktop",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"viewers": schema.ListAttribute{
 				ElementType:         types.StringType,
 				Optional:            true,
+				Computed:            true,
 				MarkdownDescription: "Lista de viewers habilitados (ej: ['browser_vnc', 'file_spice', 'file_rdpgw', 'browser_rdp']). Si no se especifica, se usan los del template.",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"boot_order": schema.ListAttribute{
+				ElementType:         types.StringType,
+				Computed:            true,
+				MarkdownDescription: "Orden de arranque del desktop según la API (p. ej. ['disk'])",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"force_stop_on_destroy": schema.BoolAttribute{
 				Optional:            true,
@@ -110,6 +176,40 @@ func (r *vmResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *r
 				Default:             booldefault.StaticBool(false),
 				MarkdownDescription: "Si es true, detiene la máquina virtual antes de eliminarla (por defecto: false)",
 			},
+			"stop_for_hardware_update": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Si es true, detiene el desktop cuando un cambio de hardware (vcpus, memory, network_interfaces, isos, floppies) lo requiere y lo vuelve a arrancar después. Si es false, la actualización falla si el desktop está en marcha (por defecto: false)",
+			},
+			"desired_state": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Estado de energía deseado del desktop: `started` o `stopped`. Si no se especifica, Terraform no arranca ni detiene el desktop.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(desiredStateStarted, desiredStateStopped),
+				},
+			},
+			"wait_for_state": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+				MarkdownDescription: "Si es true, espera a que el desktop alcance `desired_state` (como máximo el timeout de la operación) (por defecto: true)",
+			},
+			"status": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Estado actual del desktop según la API (p. ej. `Started`, `Stopped`)",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
@@ -143,6 +243,15 @@ func (r *vmResource) Create(ctx context.Context, req resource.CreateRequest, res
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultVMCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	// Preparar hardware personalizado si se especifica
 	var vcpus *int64
 	var memory *float64
@@ -160,7 +269,9 @@ func (r *vmResource) Create(ctx context.Context, req resource.CreateRequest, res
 		memory = &m
 	}
 
+	// Una lista vacía se envía para quitar las interfaces del template
 	if !plan.NetworkInterfaces.IsNull() && !plan.NetworkInterfaces.IsUnknown() {
+		interfaces = []string{}
 		diags := plan.NetworkInterfaces.ElementsAs(ctx, &interfaces, false)
 		resp.Diagnostics.Append(diags...)
 		if resp.Diagnostics.HasError() {
@@ -169,6 +280,7 @@ func (r *vmResource) Create(ctx context.Context, req resource.CreateRequest, res
 	}
 
 	if !plan.ISOs.IsNull() && !plan.ISOs.IsUnknown() {
+		isos = []string{}
 		diags := plan.ISOs.ElementsAs(ctx, &isos, false)
 		resp.Diagnostics.Append(diags...)
 		if resp.Diagnostics.HasError() {
@@ -177,6 +289,7 @@ func (r *vmResource) Create(ctx context.Context, req resource.CreateRequest, res
 	}
 
 	if !plan.Floppies.IsNull() && !plan.Floppies.IsUnknown() {
+		floppies = []string{}
 		diags := plan.Floppies.ElementsAs(ctx, &floppies, false)
 		resp.Diagnostics.Append(diags...)
 		if resp.Diagnostics.HasError() {
@@ -184,17 +297,9 @@ func (r *vmResource) Create(ctx context.Context, req resource.CreateRequest, res
 		}
 	}
 
-	var viewers []string
-	if !plan.Viewers.IsNull() && !plan.Viewers.IsUnknown() {
-		diags := plan.Viewers.ElementsAs(ctx, &viewers, false)
-		resp.Diagnostics.Append(diags...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-	}
-
 	// Crear el persistent desktop usando la API
 	desktopID, err := r.client.CreatePersistentDesktop(
+		ctx,
 		plan.Name.ValueString(),
 		plan.Description.ValueString(),
 		plan.TemplateID.ValueString(),
@@ -207,16 +312,63 @@ func (r *vmResource) Create(ctx context.Context, req resource.CreateRequest, res
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creando el persistent desktop",
-			fmt.Sprintf("No se pudo crear el desktop: %s", err.Error()),
+			clientErrorDetail("No se pudo crear el desktop", err),
 		)
 		return
 	}
 
-	// Actualizar el plan con el ID devuelto por la API
+	// Guardar el ID antes de seguir para no perder el desktop si falla algún paso posterior
 	plan.ID = types.StringValue(desktopID)
+	resp.Diagnostics.Append(setPartialState(ctx, &resp.State, plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	// No leer valores de hardware - la API devuelve valores del template
-	// Mantener los valores del plan de Terraform
+	// El endpoint de creación no admite viewers: se aplican editando el desktop. Una lista
+	// vacía también se envía para quitar los del template.
+	if !plan.Viewers.IsNull() && !plan.Viewers.IsUnknown() {
+		viewers := listToStrings(ctx, plan.Viewers, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		err := r.client.UpdateDesktop(ctx, desktopID, map[string]interface{}{
+			"guest_properties": map[string]interface{}{
+				"viewers": viewersMap(viewers),
+			},
+		})
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error configurando los viewers del desktop",
+				clientErrorDetail(fmt.Sprintf("No se pudieron configurar los viewers del desktop (ID: %s)", desktopID), err),
+			)
+			return
+		}
+	}
+
+	// Llevar el desktop al estado de energía deseado
+	if !plan.DesiredState.IsNull() {
+		err := r.applyDesiredState(ctx, desktopID, plan.DesiredState.ValueString(), plan.WaitForState.ValueBool(), createTimeout)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error aplicando el estado del desktop",
+				clientErrorDetail(fmt.Sprintf("No se pudo llevar el desktop (ID: %s) al estado %q", desktopID, plan.DesiredState.ValueString()), err),
+			)
+			return
+		}
+	}
+
+	// Completar los atributos no configurados con el hardware asignado por la API
+	// (el del template); los configurados se mantienen tal como están en el plan
+	desktop, err := r.client.GetDesktop(ctx, desktopID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error leyendo el desktop creado",
+			fmt.Sprintf("No se pudo leer el desktop (ID: %s): %s", desktopID, err.Error()),
+		)
+		return
+	}
+	fillUnknownDesktopAttributes(&plan, desktop)
+	plan.Status = types.StringValue(desktop.Status)
 
 	// Escribir el estado
 	diags = resp.State.Set(ctx, plan)
@@ -232,11 +384,20 @@ func (r *vmResource) Read(ctx context.Context, req resource.ReadRequest, resp *r
 		return
 	}
 
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultVMReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
 	// Obtener el desktop de la API
-	desktop, err := r.client.GetDesktop(state.ID.ValueString())
+	desktop, err := r.client.GetDesktop(ctx, state.ID.ValueString())
 	if err != nil {
 		// Si el desktop no existe (404), eliminarlo del estado
-		if err.Error() == "desktop not found" {
+		if client.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -252,8 +413,15 @@ func (r *vmResource) Read(ctx context.Context, req resource.ReadRequest, resp *r
 	state.Description = types.StringValue(desktop.Description)
 	state.TemplateID = types.StringValue(desktop.TemplateID)
 
-	// No actualizar hardware - la API devuelve valores del template, no los configurados
-	// Mantener los valores del estado de Terraform
+	// Reflejar el hardware real para detectar cambios hechos fuera de Terraform
+	state.VCPUs = types.Int64Value(desktop.VCPUs)
+	state.Memory = types.Float64Value(desktop.Memory)
+	state.NetworkInterfaces = stringListValue(desktop.Interfaces)
+	state.ISOs = unorderedListValue(ctx, state.ISOs, desktop.ISOs)
+	state.Floppies = unorderedListValue(ctx, state.Floppies, desktop.Floppies)
+	state.Viewers = unorderedListValue(ctx, state.Viewers, desktop.Viewers)
+	state.BootOrder = stringListValue(desktop.BootOrder)
+	state.Status = types.StringValue(desktop.Status)
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -268,9 +436,176 @@ func (r *vmResource) Update(ctx context.Context, req resource.UpdateRequest, res
 		return
 	}
 
-	// 1. Usar r.client para actualizar el recurso
-	// err := r.client.UpdateSomething(plan.ID.ValueString(), plan.Name.ValueString())
-	// ...
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultVMUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	var state vmResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	desktopID := state.ID.ValueString()
+	plan.ID = state.ID
+
+	// Construir los datos de actualización solo con los campos que cambian
+	updateData := make(map[string]interface{})
+
+	if !plan.Name.Equal(state.Name) {
+		updateData["name"] = plan.Name.ValueString()
+	}
+
+	if !plan.Description.IsUnknown() && !plan.Description.Equal(state.Description) {
+		updateData["description"] = plan.Description.ValueString()
+	}
+
+	// Las listas vacías también se envían: quitan todos los viewers o interfaces
+	if !plan.Viewers.IsUnknown() && !plan.Viewers.Equal(state.Viewers) {
+		updateData["guest_properties"] = map[string]interface{}{
+			"viewers": viewersMap(listToStrings(ctx, plan.Viewers, &resp.Diagnostics)),
+		}
+	}
+
+	// Cambios de hardware: requieren el desktop detenido
+	hardware := make(map[string]interface{})
+
+	if !plan.VCPUs.IsUnknown() && !plan.VCPUs.IsNull() && !plan.VCPUs.Equal(state.VCPUs) {
+		hardware["vcpus"] = plan.VCPUs.ValueInt64()
+	}
+
+	if !plan.Memory.IsUnknown() && !plan.Memory.IsNull() && !plan.Memory.Equal(state.Memory) {
+		hardware["memory"] = plan.Memory.ValueFloat64()
+	}
+
+	if !plan.NetworkInterfaces.IsUnknown() && !plan.NetworkInterfaces.Equal(state.NetworkInterfaces) {
+		interfaces := listToStrings(ctx, plan.NetworkInterfaces, &resp.Diagnostics)
+		if interfaces == nil {
+			interfaces = []string{}
+		}
+		hardware["interfaces"] = interfaces
+	}
+
+	if !plan.ISOs.IsUnknown() && !plan.ISOs.Equal(state.ISOs) {
+		hardware["isos"] = mediaRefs(listToStrings(ctx, plan.ISOs, &resp.Diagnostics))
+	}
+
+	if !plan.Floppies.IsUnknown() && !plan.Floppies.Equal(state.Floppies) {
+		hardware["floppies"] = mediaRefs(listToStrings(ctx, plan.Floppies, &resp.Diagnostics))
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if len(hardware) > 0 {
+		updateData["hardware"] = hardware
+	}
+
+	// Detener el desktop antes de cambiar el hardware si el estado deseado es stopped
+	desiredState := plan.DesiredState.ValueString()
+	if desiredState == desiredStateStopped {
+		err := r.applyDesiredState(ctx, desktopID, desiredState, plan.WaitForState.ValueBool() || len(hardware) > 0, updateTimeout)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error aplicando el estado del desktop",
+				clientErrorDetail(fmt.Sprintf("No se pudo detener el desktop (ID: %s)", desktopID), err),
+			)
+			return
+		}
+	}
+
+	// Si cambia el hardware y el desktop está en marcha, detenerlo primero (opt-in)
+	restart := false
+	if len(hardware) > 0 {
+		status, err := r.client.GetDesktopStatus(ctx, desktopID)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error actualizando el desktop",
+				fmt.Sprintf("No se pudo obtener el estado del desktop (ID: %s): %s", desktopID, err.Error()),
+			)
+			return
+		}
+
+		if !client.IsStoppedStatus(status) {
+			if !plan.StopForHardwareUpdate.ValueBool() {
+				resp.Diagnostics.AddError(
+					"El desktop debe estar detenido",
+					fmt.Sprintf("El desktop (ID: %s) está en estado %q y los cambios de hardware requieren detenerlo. Deténgalo manualmente o configure stop_for_hardware_update = true.", desktopID, status),
+				)
+				return
+			}
+
+			if err := r.client.StopDesktop(ctx, desktopID); err != nil {
+				resp.Diagnostics.AddError(
+					"Error deteniendo el desktop",
+					fmt.Sprintf("No se pudo detener el desktop (ID: %s) antes de actualizar el hardware: %s", desktopID, err.Error()),
+				)
+				return
+			}
+
+			if err := r.client.WaitForDesktopStopped(ctx, desktopID, updateTimeout); err != nil {
+				resp.Diagnostics.AddError(
+					"Error deteniendo el desktop",
+					fmt.Sprintf("El desktop (ID: %s) no se detuvo a tiempo: %s", desktopID, err.Error()),
+				)
+				return
+			}
+
+			restart = true
+		}
+	}
+
+	// Actualizar el desktop usando la API
+	if len(updateData) > 0 {
+		err := r.client.UpdateDesktop(ctx, desktopID, updateData)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error actualizando el desktop",
+				clientErrorDetail(fmt.Sprintf("No se pudo actualizar el desktop (ID: %s)", desktopID), err),
+			)
+			return
+		}
+	}
+
+	// Arrancar el desktop si el estado deseado es started o, si no se gestiona el
+	// estado de energía, volver a arrancarlo si lo habíamos detenido nosotros
+	if desiredState == desiredStateStarted {
+		err := r.applyDesiredState(ctx, desktopID, desiredState, plan.WaitForState.ValueBool(), updateTimeout)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error aplicando el estado del desktop",
+				clientErrorDetail(fmt.Sprintf("No se pudo arrancar el desktop (ID: %s)", desktopID), err),
+			)
+			return
+		}
+	} else if restart {
+		if err := r.client.StartDesktop(ctx, desktopID); err != nil {
+			resp.Diagnostics.AddWarning(
+				"Advertencia al arrancar el desktop",
+				fmt.Sprintf("El desktop (ID: %s) se actualizó pero no se pudo volver a arrancar: %s", desktopID, err.Error()),
+			)
+		}
+	}
+
+	// status solo se recalcula si el plan lo marcó como desconocido (cambio de estado de energía)
+	if plan.Status.IsUnknown() {
+		status, err := r.client.GetDesktopStatus(ctx, desktopID)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error leyendo el desktop",
+				fmt.Sprintf("No se pudo obtener el estado del desktop (ID: %s): %s", desktopID, err.Error()),
+			)
+			return
+		}
+		plan.Status = types.StringValue(status)
+	}
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -285,19 +620,30 @@ func (r *vmResource) Delete(ctx context.Context, req resource.DeleteRequest, res
 		return
 	}
 
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultVMDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	// Si force_stop_on_destroy es true, detener la VM primero usando force stop
 	if !state.ForceStopOnDestroy.IsNull() && state.ForceStopOnDestroy.ValueBool() {
 		// Usar force stop directamente (como hace deployment)
-		err := r.client.ForceStopDesktop(state.ID.ValueString())
+		err := r.client.ForceStopDesktop(ctx, state.ID.ValueString())
 		if err != nil {
 			resp.Diagnostics.AddWarning(
 				"Advertencia al forzar parada de la máquina virtual",
 				fmt.Sprintf("No se pudo forzar parada de la VM (ID: %s): %s. Se procederá con la eliminación.", state.ID.ValueString(), err.Error()),
 			)
 		} else {
-			// Esperar a que la VM se detenga completamente (máximo 10 segundos con force stop)
-			err = r.client.WaitForDesktopStopped(state.ID.ValueString(), 10)
-			if err != nil {
+			// Esperar a que la VM se detenga completamente como máximo la mitad del timeout
+			// de delete, para que quede tiempo para eliminarla aunque no llegue a detenerse.
+			// Si el desktop ya no existe no hay nada que esperar
+			err = r.client.WaitForDesktopStopped(ctx, state.ID.ValueString(), deleteTimeout/2)
+			if err != nil && !client.IsNotFound(err) {
 				resp.Diagnostics.AddWarning(
 					"Advertencia al esperar el stop de la VM",
 					fmt.Sprintf("Timeout esperando parada forzada de la VM (ID: %s): %s. Se procederá con la eliminación.", state.ID.ValueString(), err.Error()),
@@ -307,7 +653,7 @@ func (r *vmResource) Delete(ctx context.Context, req resource.DeleteRequest, res
 	}
 
 	// Eliminar el desktop usando la API
-	err := r.client.DeleteDesktop(state.ID.ValueString())
+	err := r.client.DeleteDesktop(ctx, state.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error eliminando la máquina virtual",
@@ -318,3 +664,118 @@ func (r *vmResource) Delete(ctx context.Context, req resource.DeleteRequest, res
 
 	// El estado se elimina automáticamente si la función termina sin errores
 }
+
+// ModifyPlan marca status como desconocido cuando el estado de energía actual no coincide
+// con desired_state, de forma que el plan muestre el cambio y Update arranque o detenga el desktop.
+func (r *vmResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nada que hacer en la creación ni en la destrucción
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var desiredState, status types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("desired_state"), &desiredState)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("status"), &status)...)
+	if resp.Diagnostics.HasError() || desiredState.IsNull() || desiredState.IsUnknown() {
+		return
+	}
+
+	if !desktopInState(status.ValueString(), desiredState.ValueString()) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("status"), types.StringUnknown())...)
+	}
+}
+
+// applyDesiredState arranca o detiene el desktop según desiredState. Si wait es true espera
+// a que alcance el estado (como máximo maxWait). Un desktop recién creado se espera siempre
+// a que termine de crearse antes de arrancarlo.
+func (r *vmResource) applyDesiredState(ctx context.Context, desktopID, desiredState string, wait bool, maxWait time.Duration) error {
+	status, err := r.client.GetDesktopStatus(ctx, desktopID)
+	if err != nil {
+		return err
+	}
+
+	switch desiredState {
+	case desiredStateStarted:
+		if client.IsStartedStatus(status) {
+			return nil
+		}
+		if status != "Starting" {
+			// Solo se puede arrancar un desktop detenido (p. ej. no mientras se crea o se detiene)
+			if !client.IsStoppedStatus(status) {
+				if err := r.client.WaitForDesktopStopped(ctx, desktopID, maxWait); err != nil {
+					return err
+				}
+			}
+			if err := r.client.StartDesktop(ctx, desktopID); err != nil {
+				return err
+			}
+		}
+		if wait {
+			return r.client.WaitForDesktopStarted(ctx, desktopID, maxWait)
+		}
+
+	case desiredStateStopped:
+		if client.IsStoppedStatus(status) {
+			return nil
+		}
+		if client.IsStartedStatus(status) || status == "Starting" {
+			if err := r.client.StopDesktop(ctx, desktopID); err != nil {
+				return err
+			}
+		}
+		if wait {
+			return r.client.WaitForDesktopStopped(ctx, desktopID, maxWait)
+		}
+	}
+
+	return nil
+}
+
+// desktopInState indica si el estado de la API corresponde al desired_state indicado
+func desktopInState(status, desiredState string) bool {
+	switch desiredState {
+	case desiredStateStarted:
+		return client.IsStartedStatus(status)
+	case desiredStateStopped:
+		return client.IsStoppedStatus(status)
+	}
+	return true
+}
+
+// fillUnknownDesktopAttributes completa los atributos computados que siguen desconocidos
+// tras crear el desktop con los valores devueltos por la API
+func fillUnknownDesktopAttributes(model *vmResourceModel, desktop *client.Desktop) {
+	if model.Description.IsUnknown() {
+		model.Description = types.StringValue(desktop.Description)
+	}
+	if model.VCPUs.IsUnknown() {
+		model.VCPUs = types.Int64Value(desktop.VCPUs)
+	}
+	if model.Memory.IsUnknown() {
+		model.Memory = types.Float64Value(desktop.Memory)
+	}
+	if model.NetworkInterfaces.IsUnknown() {
+		model.NetworkInterfaces = stringListValue(desktop.Interfaces)
+	}
+	if model.ISOs.IsUnknown() {
+		model.ISOs = stringListValue(desktop.ISOs)
+	}
+	if model.Floppies.IsUnknown() {
+		model.Floppies = stringListValue(desktop.Floppies)
+	}
+	if model.Viewers.IsUnknown() {
+		model.Viewers = stringListValue(desktop.Viewers)
+	}
+	if model.BootOrder.IsUnknown() {
+		model.BootOrder = stringListValue(desktop.BootOrder)
+	}
+}
+
+// ImportState importa un desktop existente a partir de su ID. Los atributos que solo
+// controlan el comportamiento del provider toman su valor por defecto.
+func (r *vmResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("force_stop_on_destroy"), false)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("stop_for_hardware_update"), false)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("wait_for_state"), true)...)
+}
@@ -0,0 +1,199 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/tknika/terraform-provider-isardvdi/internal/testserver"
+)
+
+func TestAccVMResource(t *testing.T) {
+	srv := testAccServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed("isardvdi_vm", srv.Desktop),
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + testAccVMConfig("tf-acc-vm", `
+  memory  = 1.5
+  viewers = ["browser_vnc", "file_spice"]
+`),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttrSet("isardvdi_vm.test", "id"),
+					tfresource.TestCheckResourceAttr("isardvdi_vm.test", "vcpus", "2"),
+					tfresource.TestCheckResourceAttr("isardvdi_vm.test", "memory", "1.5"),
+					tfresource.TestCheckResourceAttr("isardvdi_vm.test", "network_interfaces.#", "1"),
+					tfresource.TestCheckResourceAttr("isardvdi_vm.test", "network_interfaces.0", "default"),
+					tfresource.TestCheckResourceAttr("isardvdi_vm.test", "viewers.#", "2"),
+					tfresource.TestCheckResourceAttr("isardvdi_vm.test", "boot_order.0", "disk"),
+				),
+			},
+			{
+				// Las listas vacías quitan todos los viewers y las interfaces del template
+				Config: testAccProviderConfig(srv) + testAccVMConfig("tf-acc-vm-renamed", `
+  vcpus              = 4
+  memory             = 3
+  viewers            = []
+  network_interfaces = []
+`),
+				ConfigPlanChecks: tfresource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("isardvdi_vm.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("isardvdi_vm.test", "name", "tf-acc-vm-renamed"),
+					tfresource.TestCheckResourceAttr("isardvdi_vm.test", "vcpus", "4"),
+					tfresource.TestCheckResourceAttr("isardvdi_vm.test", "memory", "3"),
+					tfresource.TestCheckResourceAttr("isardvdi_vm.test", "viewers.#", "0"),
+					tfresource.TestCheckResourceAttr("isardvdi_vm.test", "network_interfaces.#", "0"),
+					testAccCheckServerDoc("isardvdi_vm.test", srv.Desktop, func(doc map[string]interface{}) error {
+						guestProps, _ := doc["guest_properties"].(map[string]interface{})
+						if viewers, _ := guestProps["viewers"].(map[string]interface{}); len(viewers) != 0 {
+							return fmt.Errorf("viewers en el servidor = %v, se esperaba ninguno", viewers)
+						}
+						hardware, _ := doc["hardware"].(map[string]interface{})
+						if interfaces, _ := hardware["interfaces"].([]interface{}); len(interfaces) != 0 {
+							return fmt.Errorf("interfaces en el servidor = %v, se esperaba ninguna", interfaces)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				ResourceName:      "isardvdi_vm.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccVMResourceEmptyListsOnCreate(t *testing.T) {
+	srv := testAccServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed("isardvdi_vm", srv.Desktop),
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + testAccVMConfig("tf-acc-vm", `
+  viewers            = []
+  network_interfaces = []
+`),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("isardvdi_vm.test", "viewers.#", "0"),
+					tfresource.TestCheckResourceAttr("isardvdi_vm.test", "network_interfaces.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestVMCreateKeepsIDWhenViewersFail(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+	r := &vmResource{client: c}
+
+	srv.FailNext(http.MethodPut, "/api/v3/domain/", http.StatusBadRequest, 1)
+
+	req := resource.CreateRequest{Plan: newPlan(t, r, map[string]interface{}{
+		"name":        "tf-vm",
+		"template_id": testserver.DefaultTemplateID,
+		"viewers":     []string{"browser_vnc"},
+		"status":      types.StringUnknown(),
+	})}
+	resp := resource.CreateResponse{State: newState(t, r, nil)}
+
+	r.Create(ctx, req, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("se esperaba un error al configurar los viewers")
+	}
+
+	// El desktop ya existe: debe quedar en el estado para que Terraform lo marque como tainted
+	var id, status types.String
+	resp.State.GetAttribute(ctx, path.Root("id"), &id)
+	resp.State.GetAttribute(ctx, path.Root("status"), &status)
+	if _, ok := srv.Desktop(id.ValueString()); !ok {
+		t.Fatalf("id en el estado = %s, se esperaba el del desktop creado", id)
+	}
+	if !status.IsNull() {
+		t.Errorf("status = %s, se esperaba null en el estado parcial", status)
+	}
+}
+
+func TestVMCreateKeepsIDWhenDesiredStateFails(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+	r := &vmResource{client: c}
+
+	srv.FailNext(http.MethodGet, "/api/v3/domain/info/", http.StatusBadRequest, 1)
+
+	req := resource.CreateRequest{Plan: newPlan(t, r, map[string]interface{}{
+		"name":          "tf-vm",
+		"template_id":   testserver.DefaultTemplateID,
+		"desired_state": desiredStateStarted,
+	})}
+	resp := resource.CreateResponse{State: newState(t, r, nil)}
+
+	r.Create(ctx, req, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("se esperaba un error al aplicar desired_state")
+	}
+
+	var id types.String
+	resp.State.GetAttribute(ctx, path.Root("id"), &id)
+	if _, ok := srv.Desktop(id.ValueString()); !ok {
+		t.Fatalf("id en el estado = %s, se esperaba el del desktop creado", id)
+	}
+}
+
+func TestVMDeleteWhenDesktopDoesNotStop(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+	r := &vmResource{client: c}
+
+	id, err := c.CreatePersistentDesktop(ctx, "tf-vm", "", testserver.DefaultTemplateID, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreatePersistentDesktop: %v", err)
+	}
+	srv.SetDesktopStatus(id, "Started")
+	srv.HoldDesktopStatus(id, 1000)
+
+	state := newState(t, r, map[string]interface{}{"id": id, "force_stop_on_destroy": true})
+	if diags := state.SetAttribute(ctx, path.Root("timeouts").AtName("delete"), "200ms"); diags.HasError() {
+		t.Fatalf("SetAttribute(timeouts): %v", diags)
+	}
+	resp := resource.DeleteResponse{State: state}
+
+	// La espera de la parada agota su parte del timeout, pero queda tiempo para eliminar
+	r.Delete(ctx, resource.DeleteRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Delete: %v", resp.Diagnostics)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("Delete = %v, se esperaba un aviso por el timeout de la parada", resp.Diagnostics)
+	}
+	if _, ok := srv.Desktop(id); ok {
+		t.Error("el desktop sigue existiendo")
+	}
+}
+
+// testAccVMConfig devuelve un isardvdi_vm sobre el template del servidor falso con los
+// argumentos adicionales indicados
+func testAccVMConfig(name, extra string) string {
+	return fmt.Sprintf(`
+resource "isardvdi_vm" "test" {
+  name        = %q
+  template_id = %q
+%s}
+`, name, testserver.DefaultTemplateID, extra)
+}
@@ -0,0 +1,36 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// setPartialState guarda en el estado el plan de un recurso recién creado, con los atributos
+// todavía desconocidos a null. Se llama justo después de crear el recurso en la API para que,
+// si falla algún paso posterior, Terraform lo marque como tainted en lugar de perderlo.
+func setPartialState(ctx context.Context, state *tfsdk.State, plan interface{}) diag.Diagnostics {
+	diags := state.Set(ctx, plan)
+	if diags.HasError() {
+		return diags
+	}
+
+	raw, err := tftypes.Transform(state.Raw, func(_ *tftypes.AttributePath, value tftypes.Value) (tftypes.Value, error) {
+		if !value.IsKnown() {
+			return tftypes.NewValue(value.Type(), nil), nil
+		}
+		return value, nil
+	})
+	if err != nil {
+		diags.AddError(
+			"Error guardando el estado parcial",
+			"No se pudo guardar el recurso recién creado en el estado: "+err.Error(),
+		)
+		return diags
+	}
+
+	state.Raw = raw
+	return diags
+}
@@ -0,0 +1,583 @@
+package testserver
+
+import (
+	"net/http"
+	"strings"
+)
+
+// registerAdminRoutes registra las tablas de administración, usuarios, grupos y templates
+func (s *Server) registerAdminRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /api/v3/admin/table/add/{table}", s.handleTableAdd)
+	mux.HandleFunc("POST /api/v3/admin/table/{table}", s.handleTableGet)
+	mux.HandleFunc("GET /api/v3/admin/table/{table}", s.handleTableList)
+	mux.HandleFunc("PUT /api/v3/admin/table/update/{table}", s.handleTableUpdate)
+	mux.HandleFunc("DELETE /api/v3/admin/table/{table}/{id}", s.handleTableDelete)
+
+	mux.HandleFunc("GET /api/v3/admin/users/management/users", s.handleListUsers)
+	mux.HandleFunc("POST /api/v3/admin/users/search", s.handleSearchUsers)
+	mux.HandleFunc("GET /api/v3/admin/user/{id}", s.handleGetUser)
+	mux.HandleFunc("POST /api/v3/admin/user", s.handleCreateUser)
+	mux.HandleFunc("POST /api/v3/admin/users/bulk", s.handleCreateUsersBulk)
+	mux.HandleFunc("PUT /api/v3/admin/user/{id}", s.handleUpdateUser)
+	mux.HandleFunc("DELETE /api/v3/admin/user/{id}", s.handleDeleteUser)
+	mux.HandleFunc("GET /api/v3/admin/groups", s.handleListGroups)
+	mux.HandleFunc("GET /api/v3/admin/group/{id}", s.handleGetGroup)
+	mux.HandleFunc("POST /api/v3/admin/group", s.handleCreateGroup)
+	mux.HandleFunc("PUT /api/v3/admin/group/{id}", s.handleUpdateGroup)
+	mux.HandleFunc("DELETE /api/v3/admin/group/{id}", s.handleDeleteGroup)
+	mux.HandleFunc("POST /api/v3/admin/group/{id}/enrollment", s.handleGroupEnrollment)
+	mux.HandleFunc("GET /api/v3/user/templates", s.handleListTemplates)
+}
+
+// AddUser siembra un usuario
+func (s *Server) AddUser(doc map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users[doc["id"].(string)] = clone(doc)
+}
+
+// User devuelve una copia del usuario almacenado
+func (s *Server) User(id string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.users[id]
+	if !ok {
+		return nil, false
+	}
+	return clone(doc), true
+}
+
+// AddGroup siembra un grupo
+func (s *Server) AddGroup(doc map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.groups[doc["id"].(string)] = clone(doc)
+}
+
+// Group devuelve una copia del grupo almacenado
+func (s *Server) Group(id string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.groups[id]
+	if !ok {
+		return nil, false
+	}
+	return clone(doc), true
+}
+
+// AddTableItem siembra un elemento en una tabla de administración (p. ej. "interfaces")
+func (s *Server) AddTableItem(table string, doc map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.tables[table] == nil {
+		s.tables[table] = map[string]map[string]interface{}{}
+	}
+	s.tables[table][doc["id"].(string)] = clone(doc)
+}
+
+// TableItem devuelve una copia de un elemento de una tabla de administración
+func (s *Server) TableItem(table, id string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.tables[table][id]
+	if !ok {
+		return nil, false
+	}
+	return clone(doc), true
+}
+
+// table devuelve la tabla indicada o responde 404. Debe llamarse con mu bloqueado.
+func (s *Server) table(w http.ResponseWriter, r *http.Request) (map[string]map[string]interface{}, bool) {
+	name := r.PathValue("table")
+	table, ok := s.tables[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Tabla no encontrada: "+name, "not_found")
+	}
+	return table, ok
+}
+
+func (s *Server) handleTableAdd(w http.ResponseWriter, r *http.Request) {
+	body, ok := readJSON(w, r)
+	if !ok {
+		return
+	}
+
+	name, _ := body["name"].(string)
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "name es obligatorio", "bad_request")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	table, ok := s.table(w, r)
+	if !ok {
+		return
+	}
+
+	id, _ := body["id"].(string)
+	if id == "" {
+		id = newID()
+	}
+	if _, exists := table[id]; exists || nameTaken(table, name) {
+		writeError(w, http.StatusConflict, "Ya existe un elemento con ese id o nombre", "item_exists")
+		return
+	}
+
+	doc := clone(body)
+	doc["id"] = id
+	table[id] = doc
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+// handleTableGet devuelve un elemento por id. Como la API, también lo encuentra
+// por nombre, que es como el cliente localiza un QoS recién creado.
+func (s *Server) handleTableGet(w http.ResponseWriter, r *http.Request) {
+	body, ok := readJSON(w, r)
+	if !ok {
+		return
+	}
+	id, _ := body["id"].(string)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	table, ok := s.table(w, r)
+	if !ok {
+		return
+	}
+
+	if doc, ok := table[id]; ok {
+		writeJSON(w, http.StatusOK, clone(doc))
+		return
+	}
+	for _, doc := range table {
+		if doc["name"] == id {
+			writeJSON(w, http.StatusOK, clone(doc))
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "Elemento no encontrado: "+id, "not_found")
+}
+
+func (s *Server) handleTableList(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	table, ok := s.table(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, list(table))
+}
+
+func (s *Server) handleTableUpdate(w http.ResponseWriter, r *http.Request) {
+	body, ok := readJSON(w, r)
+	if !ok {
+		return
+	}
+	id, _ := body["id"].(string)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	table, ok := s.table(w, r)
+	if !ok {
+		return
+	}
+
+	doc, ok := table[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Elemento no encontrado: "+id, "not_found")
+		return
+	}
+
+	merge(doc, body)
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+func (s *Server) handleTableDelete(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	table, ok := s.table(w, r)
+	if !ok {
+		return
+	}
+
+	id := r.PathValue("id")
+	if _, ok := table[id]; !ok {
+		writeError(w, http.StatusNotFound, "Elemento no encontrado: "+id, "not_found")
+		return
+	}
+
+	delete(table, id)
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+func (s *Server) handleListUsers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, list(s.users))
+}
+
+func (s *Server) handleSearchUsers(w http.ResponseWriter, r *http.Request) {
+	body, ok := readJSON(w, r)
+	if !ok {
+		return
+	}
+	term, _ := body["term"].(string)
+	term = strings.ToLower(term)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users := []map[string]interface{}{}
+	for _, doc := range s.users {
+		name, _ := doc["name"].(string)
+		username, _ := doc["username"].(string)
+		if strings.Contains(strings.ToLower(name), term) || strings.Contains(strings.ToLower(username), term) {
+			users = append(users, clone(doc))
+		}
+	}
+
+	writeJSON(w, http.StatusOK, users)
+}
+
+func (s *Server) handleGetUser(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	doc, ok := s.users[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Usuario no encontrado: "+id, "not_found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, clone(doc))
+}
+
+// userRoles son los roles que acepta la API al crear o modificar un usuario
+var userRoles = map[string]bool{"admin": true, "manager": true, "advanced": true, "user": true}
+
+// validUserFields comprueba el rol y los grupos de un usuario. Debe llamarse con mu bloqueado.
+func (s *Server) validUserFields(w http.ResponseWriter, body map[string]interface{}) bool {
+	if role, ok := body["role"].(string); ok && !userRoles[role] {
+		writeError(w, http.StatusBadRequest, "Rol no válido: "+role, "bad_request")
+		return false
+	}
+	if group, ok := body["group"].(string); ok {
+		if _, exists := s.groups[group]; !exists {
+			writeError(w, http.StatusNotFound, "Grupo no encontrado: "+group, "not_found")
+			return false
+		}
+	}
+	return true
+}
+
+// handleCreateUser crea un usuario local. Como la API, no devuelve nunca la contraseña.
+func (s *Server) handleCreateUser(w http.ResponseWriter, r *http.Request) {
+	body, ok := readJSON(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, ok := s.createUser(w, body)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"id": id})
+}
+
+// handleCreateUsersBulk crea varios usuarios. Se detiene en el primero que no es válido.
+func (s *Server) handleCreateUsersBulk(w http.ResponseWriter, r *http.Request) {
+	body, ok := readJSON(w, r)
+	if !ok {
+		return
+	}
+	users, ok := body["users"].([]interface{})
+	if !ok {
+		writeError(w, http.StatusBadRequest, "users es obligatorio", "bad_request")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, raw := range users {
+		user, _ := raw.(map[string]interface{})
+		if _, ok := s.createUser(w, user); !ok {
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+// createUser valida y guarda un usuario local, o responde con el error. Debe llamarse
+// con mu bloqueado.
+func (s *Server) createUser(w http.ResponseWriter, body map[string]interface{}) (string, bool) {
+	for _, field := range []string{"username", "name", "password", "role", "category", "group"} {
+		if v, _ := body[field].(string); v == "" {
+			writeError(w, http.StatusBadRequest, field+" es obligatorio", "bad_request")
+			return "", false
+		}
+	}
+	username := body["username"].(string)
+	category := body["category"].(string)
+
+	if !s.validUserFields(w, body) {
+		return "", false
+	}
+	for _, doc := range s.users {
+		if doc["username"] == username && doc["category"] == category {
+			writeError(w, http.StatusConflict, "Ya existe un usuario con ese username en la categoría", "user_exists")
+			return "", false
+		}
+	}
+
+	id := "local-" + category + "-" + username + "-" + username
+	doc := clone(body)
+	delete(doc, "password")
+	doc["id"] = id
+	doc["provider"] = "local"
+	doc["uid"] = username
+	s.users[id] = doc
+
+	return id, true
+}
+
+func (s *Server) handleUpdateUser(w http.ResponseWriter, r *http.Request) {
+	body, ok := readJSON(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	doc, ok := s.users[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Usuario no encontrado: "+id, "not_found")
+		return
+	}
+	if !s.validUserFields(w, body) {
+		return
+	}
+
+	update := clone(body)
+	delete(update, "password")
+	merge(doc, update)
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+func (s *Server) handleDeleteUser(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if _, ok := s.users[id]; !ok {
+		writeError(w, http.StatusNotFound, "Usuario no encontrado: "+id, "not_found")
+		return
+	}
+
+	delete(s.users, id)
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+func (s *Server) handleListGroups(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, list(s.groups))
+}
+
+func (s *Server) handleGetGroup(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	doc, ok := s.groups[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Grupo no encontrado: "+id, "not_found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, clone(doc))
+}
+
+// groupNameTaken indica si ya existe otro grupo con el mismo nombre en la categoría.
+// Debe llamarse con mu bloqueado.
+func (s *Server) groupNameTaken(category, name, exceptID string) bool {
+	for id, doc := range s.groups {
+		if id != exceptID && doc["parent_category"] == category && doc["name"] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// handleCreateGroup crea un grupo. Como la API, lo crea sin códigos de auto-registro.
+func (s *Server) handleCreateGroup(w http.ResponseWriter, r *http.Request) {
+	body, ok := readJSON(w, r)
+	if !ok {
+		return
+	}
+
+	name, _ := body["name"].(string)
+	category, _ := body["parent_category"].(string)
+	if name == "" || category == "" {
+		writeError(w, http.StatusBadRequest, "name y parent_category son obligatorios", "bad_request")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.categories[category]; !ok {
+		writeError(w, http.StatusNotFound, "Categoría no encontrada: "+category, "not_found")
+		return
+	}
+	if s.groupNameTaken(category, name, "") {
+		writeError(w, http.StatusConflict, "Ya existe un grupo con ese nombre en la categoría", "group_exists")
+		return
+	}
+
+	id := newID()
+	doc := clone(body)
+	doc["id"] = id
+	if _, ok := doc["linked_groups"]; !ok {
+		doc["linked_groups"] = []interface{}{}
+	}
+	doc["enrollment"] = map[string]interface{}{"manager": false, "advanced": false, "user": false}
+	s.groups[id] = doc
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"id": id})
+}
+
+func (s *Server) handleUpdateGroup(w http.ResponseWriter, r *http.Request) {
+	body, ok := readJSON(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	doc, ok := s.groups[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Grupo no encontrado: "+id, "not_found")
+		return
+	}
+	if name, ok := body["name"].(string); ok {
+		category, _ := doc["parent_category"].(string)
+		if s.groupNameTaken(category, name, id) {
+			writeError(w, http.StatusConflict, "Ya existe un grupo con ese nombre en la categoría", "group_exists")
+			return
+		}
+	}
+
+	update := clone(body)
+	delete(update, "parent_category")
+	merge(doc, update)
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+// handleDeleteGroup elimina el grupo y, como la API, también sus usuarios
+func (s *Server) handleDeleteGroup(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if _, ok := s.groups[id]; !ok {
+		writeError(w, http.StatusNotFound, "Grupo no encontrado: "+id, "not_found")
+		return
+	}
+
+	s.deleteGroup(id)
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+// handleGroupEnrollment genera (action "reset") o elimina (action "disable") el código
+// de auto-registro de un rol del grupo
+func (s *Server) handleGroupEnrollment(w http.ResponseWriter, r *http.Request) {
+	body, ok := readJSON(w, r)
+	if !ok {
+		return
+	}
+	role, _ := body["role"].(string)
+	action, _ := body["action"].(string)
+	if role != "manager" && role != "advanced" && role != "user" {
+		writeError(w, http.StatusBadRequest, "Rol no válido: "+role, "bad_request")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	doc, ok := s.groups[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Grupo no encontrado: "+id, "not_found")
+		return
+	}
+	enrollment, _ := doc["enrollment"].(map[string]interface{})
+	if enrollment == nil {
+		enrollment = map[string]interface{}{"manager": false, "advanced": false, "user": false}
+		doc["enrollment"] = enrollment
+	}
+
+	switch action {
+	case "reset":
+		code := strings.ReplaceAll(newID(), "-", "")[:10]
+		enrollment[role] = code
+		writeJSON(w, http.StatusOK, map[string]interface{}{"code": code})
+	case "disable":
+		enrollment[role] = false
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	default:
+		writeError(w, http.StatusBadRequest, "Acción no válida: "+action, "bad_request")
+	}
+}
+
+// deleteGroup elimina un grupo y sus usuarios. Debe llamarse con mu bloqueado.
+func (s *Server) deleteGroup(id string) {
+	for userID, user := range s.users {
+		if user["group"] == id {
+			delete(s.users, userID)
+		}
+	}
+	delete(s.groups, id)
+}
+
+func (s *Server) handleListTemplates(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	templates := make([]map[string]interface{}, 0, len(s.templates))
+	for _, doc := range s.templates {
+		t := clone(doc)
+		delete(t, "hardware")
+		delete(t, "guest_properties")
+		templates = append(templates, t)
+	}
+
+	writeJSON(w, http.StatusOK, templates)
+}
@@ -0,0 +1,133 @@
+package testserver
+
+import (
+	"net/http"
+)
+
+// registerCategoryRoutes registra los endpoints de administración de categorías
+func (s *Server) registerCategoryRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v3/admin/categories", s.handleListCategories)
+	mux.HandleFunc("GET /api/v3/admin/category/{id}", s.handleGetCategory)
+	mux.HandleFunc("POST /api/v3/admin/category", s.handleCreateCategory)
+	mux.HandleFunc("PUT /api/v3/admin/category/{id}", s.handleUpdateCategory)
+	mux.HandleFunc("DELETE /api/v3/admin/category/{id}", s.handleDeleteCategory)
+}
+
+// AddCategory siembra una categoría
+func (s *Server) AddCategory(doc map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.categories[doc["id"].(string)] = clone(doc)
+}
+
+// Category devuelve una copia de la categoría almacenada
+func (s *Server) Category(id string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.categories[id]
+	if !ok {
+		return nil, false
+	}
+	return clone(doc), true
+}
+
+func (s *Server) handleListCategories(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, list(s.categories))
+}
+
+func (s *Server) handleGetCategory(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	doc, ok := s.categories[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Categoría no encontrada: "+id, "not_found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, clone(doc))
+}
+
+func (s *Server) handleCreateCategory(w http.ResponseWriter, r *http.Request) {
+	body, ok := readJSON(w, r)
+	if !ok {
+		return
+	}
+
+	name, _ := body["name"].(string)
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "name es obligatorio", "bad_request")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if nameTaken(s.categories, name) {
+		writeError(w, http.StatusConflict, "Ya existe una categoría con ese nombre", "category_exists")
+		return
+	}
+
+	id := newID()
+	doc := clone(body)
+	doc["id"] = id
+	s.categories[id] = doc
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"id": id})
+}
+
+func (s *Server) handleUpdateCategory(w http.ResponseWriter, r *http.Request) {
+	body, ok := readJSON(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	doc, ok := s.categories[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Categoría no encontrada: "+id, "not_found")
+		return
+	}
+	if name, ok := body["name"].(string); ok && name != doc["name"] && nameTaken(s.categories, name) {
+		writeError(w, http.StatusConflict, "Ya existe una categoría con ese nombre", "category_exists")
+		return
+	}
+
+	merge(doc, body)
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+// handleDeleteCategory elimina la categoría y, como la API, también sus grupos y usuarios
+func (s *Server) handleDeleteCategory(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if _, ok := s.categories[id]; !ok {
+		writeError(w, http.StatusNotFound, "Categoría no encontrada: "+id, "not_found")
+		return
+	}
+
+	for groupID, group := range s.groups {
+		if group["parent_category"] == id {
+			s.deleteGroup(groupID)
+		}
+	}
+	for userID, user := range s.users {
+		if user["category"] == id {
+			delete(s.users, userID)
+		}
+	}
+	delete(s.categories, id)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
@@ -0,0 +1,360 @@
+package testserver
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// maxDeploymentMemoryGB es el límite de memoria que acepta el endpoint de deployments.
+// Un valor mayor suele indicar que se ha enviado en KiB en lugar de GB.
+const maxDeploymentMemoryGB = 512
+
+// registerDeploymentRoutes registra los endpoints de deployments y templates
+func (s *Server) registerDeploymentRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /api/v3/deployments", s.handleCreateDeployment)
+	mux.HandleFunc("GET /api/v3/deployment/{id}", s.handleGetDeployment)
+	mux.HandleFunc("GET /api/v3/deployment/info/{id}", s.handleGetDeploymentInfo)
+	mux.HandleFunc("PUT /api/v3/deployment/{id}", s.handleUpdateDeployment)
+	mux.HandleFunc("DELETE /api/v3/deployments/{id}/{permanent}", s.handleDeleteDeployment)
+	mux.HandleFunc("PUT /api/v3/deployments/start/{id}", s.handleDeploymentAction("Started"))
+	mux.HandleFunc("PUT /api/v3/deployments/stop/{id}", s.handleDeploymentAction("Stopping"))
+	mux.HandleFunc("GET /api/v3/template/{id}", s.handleGetTemplate)
+}
+
+// AddTemplate siembra un template. El hardware debe expresar la memoria en KiB.
+func (s *Server) AddTemplate(doc map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.templates[doc["id"].(string)] = clone(doc)
+}
+
+// Deployment devuelve una copia del deployment almacenado, incluido el payload recibido
+func (s *Server) Deployment(id string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.deployments[id]
+	if !ok {
+		return nil, false
+	}
+	return clone(doc), true
+}
+
+// DeploymentDesktopIDs devuelve los IDs de los desktops del deployment
+func (s *Server) DeploymentDesktopIDs(id string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ids []string
+	for desktopID, desktop := range s.desktops {
+		if desktop["tag"] == id {
+			ids = append(ids, desktopID)
+		}
+	}
+	return ids
+}
+
+// deploymentDesktops cuenta los desktops del deployment por estado
+func (s *Server) deploymentDesktops(deploymentID string) (total, started, creating int) {
+	for _, desktop := range s.desktops {
+		if desktop["tag"] != deploymentID {
+			continue
+		}
+		total++
+		switch desktop["status"] {
+		case "Started", "Starting", "Stopping":
+			started++
+		case "Creating":
+			creating++
+		}
+	}
+	return total, started, creating
+}
+
+func (s *Server) handleCreateDeployment(w http.ResponseWriter, r *http.Request) {
+	body, ok := readJSON(w, r)
+	if !ok {
+		return
+	}
+
+	name, _ := body["name"].(string)
+	templateID, _ := body["template_id"].(string)
+	desktopName, _ := body["desktop_name"].(string)
+	hardware, _ := body["hardware"].(map[string]interface{})
+	if name == "" || templateID == "" || desktopName == "" || hardware == nil {
+		writeError(w, http.StatusBadRequest, "name, template_id, desktop_name y hardware son obligatorios", "bad_request")
+		return
+	}
+	for _, field := range []string{"vcpus", "memory", "videos", "interfaces"} {
+		if _, ok := hardware[field]; !ok {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("hardware.%s es obligatorio", field), "bad_request")
+			return
+		}
+	}
+	if memory, _ := hardware["memory"].(float64); memory <= 0 || memory > maxDeploymentMemoryGB {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("hardware.memory fuera de rango (GB): %v", hardware["memory"]), "invalid_memory")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	template, ok := s.templates[templateID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Template no encontrado: "+templateID, "not_found")
+		return
+	}
+	if nameTaken(s.deployments, name) {
+		writeError(w, http.StatusConflict, "Ya existe un deployment con ese nombre", "deployment_exists")
+		return
+	}
+
+	id := newID()
+	doc := clone(body)
+	doc["id"] = id
+	doc["template"] = templateID
+	s.deployments[id] = doc
+
+	// Un desktop por cada usuario permitido (o uno para el propietario)
+	owners := allowedUsers(body["allowed"])
+	if len(owners) == 0 {
+		owners = []string{DefaultUserID}
+	}
+	desktopHardware := clone(hardware)
+	if gb, ok := desktopHardware["memory"].(float64); ok {
+		desktopHardware["memory"] = gb * 1024 * 1024
+	}
+	for _, owner := range owners {
+		desktopID := newID()
+		desktop := map[string]interface{}{
+			"id":          desktopID,
+			"name":        desktopName,
+			"description": body["description"],
+			"kind":        "desktop",
+			"status":      "Creating",
+			"user":        owner,
+			"tag":         id,
+			"create_dict": map[string]interface{}{"origin": template["id"]},
+			"hardware":    desktopHardware,
+		}
+		// El desktop hereda la categoría y el grupo de su propietario
+		if user, ok := s.users[owner]; ok {
+			desktop["username"] = user["username"]
+			desktop["category"] = user["category"]
+			desktop["group"] = user["group"]
+		}
+		s.desktops[desktopID] = desktop
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"id": id})
+}
+
+// allowedUsers extrae la lista de usuarios del campo allowed
+func allowedUsers(allowed interface{}) []string {
+	m, ok := allowed.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	list, ok := m["users"].([]interface{})
+	if !ok {
+		return nil
+	}
+	users := make([]string, 0, len(list))
+	for _, u := range list {
+		if id, ok := u.(string); ok {
+			users = append(users, id)
+		}
+	}
+	return users
+}
+
+func (s *Server) handleGetDeployment(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	doc, ok := s.deployments[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Deployment no encontrado: "+id, "not_found")
+		return
+	}
+
+	for _, desktop := range s.desktops {
+		if desktop["tag"] == id {
+			s.advanceDesktop(desktop)
+		}
+	}
+	total, started, creating := s.deploymentDesktops(id)
+
+	desktops := []interface{}{}
+	for _, desktop := range s.desktops {
+		if desktop["tag"] != id {
+			continue
+		}
+		desktops = append(desktops, s.deploymentDesktop(doc, desktop))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":               id,
+		"name":             doc["name"],
+		"description":      doc["description"],
+		"desktop_name":     doc["desktop_name"],
+		"visible":          doc["visible"],
+		"template":         doc["template"],
+		"allowed":          doc["allowed"],
+		"totalDesktops":    total,
+		"visibleDesktops":  visibleCount(doc, total),
+		"startedDesktops":  started,
+		"creatingDesktops": creating,
+		"desktops":         desktops,
+	})
+}
+
+// deploymentDesktop construye la entrada de un desktop en la respuesta de GET /deployment/{id}
+func (s *Server) deploymentDesktop(deployment, desktop map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{
+		"id":           desktop["id"],
+		"name":         desktop["name"],
+		"user":         desktop["user"],
+		"userName":     desktop["username"],
+		"categoryName": "",
+		"groupName":    "",
+		"status":       desktop["status"],
+		"ip":           "",
+		"viewers":      []interface{}{},
+		"visible":      visibleCount(deployment, 1) == 1,
+	}
+	if category, ok := s.categories[fmt.Sprint(desktop["category"])]; ok {
+		out["categoryName"] = category["name"]
+	}
+	if group, ok := s.groups[fmt.Sprint(desktop["group"])]; ok {
+		out["groupName"] = group["name"]
+	}
+	if viewer, ok := desktop["viewer"].(map[string]interface{}); ok {
+		out["ip"] = viewer["guest_ip"]
+	}
+	if props, ok := deployment["guest_properties"].(map[string]interface{}); ok {
+		if viewers, ok := props["viewers"].(map[string]interface{}); ok {
+			names := make([]interface{}, 0, len(viewers))
+			for name := range viewers {
+				names = append(names, name)
+			}
+			out["viewers"] = names
+		}
+	}
+	return out
+}
+
+// visibleCount devuelve cuántos desktops ven los usuarios según la visibilidad del deployment
+func visibleCount(doc map[string]interface{}, total int) int {
+	if visible, _ := doc["visible"].(bool); visible {
+		return total
+	}
+	return 0
+}
+
+func (s *Server) handleGetDeploymentInfo(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	doc, ok := s.deployments[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Deployment no encontrado: "+id, "not_found")
+		return
+	}
+
+	// Los endpoints de edición reciben la memoria en GB, pero deployment/info la devuelve en KiB
+	info := clone(doc)
+	if hardware, ok := info["hardware"].(map[string]interface{}); ok {
+		if gb, ok := hardware["memory"].(float64); ok {
+			hardware["memory"] = gb * 1024 * 1024
+		}
+	}
+	writeJSON(w, http.StatusOK, info)
+}
+
+func (s *Server) handleUpdateDeployment(w http.ResponseWriter, r *http.Request) {
+	body, ok := readJSON(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	doc, ok := s.deployments[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Deployment no encontrado: "+id, "not_found")
+		return
+	}
+
+	merge(doc, body)
+	writeJSON(w, http.StatusOK, map[string]interface{}{"id": id})
+}
+
+func (s *Server) handleDeleteDeployment(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if _, ok := s.deployments[id]; !ok {
+		writeError(w, http.StatusNotFound, "Deployment no encontrado: "+id, "not_found")
+		return
+	}
+
+	// La API exige detener los desktops antes de eliminar el deployment
+	if _, started, _ := s.deploymentDesktops(id); started > 0 {
+		writeError(w, http.StatusPreconditionRequired, "Los desktops del deployment deben estar detenidos", "desktops_not_stopped")
+		return
+	}
+
+	for desktopID, desktop := range s.desktops {
+		if desktop["tag"] == id {
+			delete(s.desktops, desktopID)
+		}
+	}
+	delete(s.deployments, id)
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+// handleDeploymentAction cambia el estado de todos los desktops del deployment
+func (s *Server) handleDeploymentAction(status string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		id := r.PathValue("id")
+		if _, ok := s.deployments[id]; !ok {
+			writeError(w, http.StatusNotFound, "Deployment no encontrado: "+id, "not_found")
+			return
+		}
+
+		for _, desktop := range s.desktops {
+			if desktop["tag"] != id {
+				continue
+			}
+			if status == "Stopping" && desktop["status"] == "Stopped" {
+				continue
+			}
+			desktop["status"] = status
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"id": id})
+	}
+}
+
+func (s *Server) handleGetTemplate(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	doc, ok := s.templates[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Template no encontrado: "+id, "not_found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, clone(doc))
+}
@@ -0,0 +1,410 @@
+package testserver
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// registerDesktopRoutes registra los endpoints de desktops persistentes
+func (s *Server) registerDesktopRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /api/v3/persistent_desktop", s.handleCreateDesktop)
+	mux.HandleFunc("GET /api/v3/domain/info/{id}", s.handleGetDomain)
+	mux.HandleFunc("GET /api/v3/admin/domains", s.handleListDomains)
+	mux.HandleFunc("DELETE /api/v3/desktop/{id}/{permanent}", s.handleDeleteDesktop)
+	mux.HandleFunc("PUT /api/v3/domain/{id}", s.handleUpdateDesktop)
+	mux.HandleFunc("GET /api/v3/desktop/start/{id}", s.handleStartDesktop)
+	mux.HandleFunc("GET /api/v3/desktop/stop/{id}", s.handleStopDesktop)
+	mux.HandleFunc("POST /api/v3/admin/multiple_actions", s.handleMultipleActions)
+	mux.HandleFunc("GET /api/v3/desktop/{id}/viewer/{viewer}", s.handleDesktopViewer)
+	mux.HandleFunc("GET /api/v3/desktop/jumperurl/{id}", s.handleGetJumperURL)
+	mux.HandleFunc("PUT /api/v3/desktop/jumperurl_reset/{id}", s.handleResetJumperURL)
+}
+
+// Desktop devuelve una copia del desktop almacenado
+func (s *Server) Desktop(id string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.desktops[id]
+	if !ok {
+		return nil, false
+	}
+	return clone(doc), true
+}
+
+// SetDesktopStatus fuerza el estado de un desktop (p. ej. "Started")
+func (s *Server) SetDesktopStatus(id, status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if doc, ok := s.desktops[id]; ok {
+		doc["status"] = status
+	}
+}
+
+// HoldDesktopStatus congela el estado del desktop durante las próximas `reads` lecturas,
+// p. ej. para simular un desktop que no termina de detenerse
+func (s *Server) HoldDesktopStatus(id string, reads int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.holds[id] = reads
+}
+
+// advanceDesktop avanza el estado del desktop salvo que esté congelado con HoldDesktopStatus
+func (s *Server) advanceDesktop(doc map[string]interface{}) {
+	id, _ := doc["id"].(string)
+	if s.holds[id] > 0 {
+		s.holds[id]--
+		return
+	}
+	advanceStatus(doc)
+}
+
+// SetDesktopGuestIP simula la IP que el invitado informa al viewer
+func (s *Server) SetDesktopGuestIP(id, ip string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if doc, ok := s.desktops[id]; ok {
+		doc["viewer"] = map[string]interface{}{"guest_ip": ip}
+	}
+}
+
+func (s *Server) handleCreateDesktop(w http.ResponseWriter, r *http.Request) {
+	body, ok := readJSON(w, r)
+	if !ok {
+		return
+	}
+
+	name, _ := body["name"].(string)
+	templateID, _ := body["template_id"].(string)
+	if name == "" || templateID == "" {
+		writeError(w, http.StatusBadRequest, "name y template_id son obligatorios", "bad_request")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	template, ok := s.templates[templateID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Template no encontrado: "+templateID, "not_found")
+		return
+	}
+	if nameTaken(s.desktops, name) {
+		writeError(w, http.StatusConflict, "Ya existe un desktop con ese nombre", "desktop_exists")
+		return
+	}
+
+	hardware := clone(template)["hardware"].(map[string]interface{})
+	if custom, ok := body["hardware"].(map[string]interface{}); ok {
+		for k, v := range custom {
+			if k == "memory" {
+				// El endpoint recibe GB pero domain/info devuelve KiB
+				if gb, ok := v.(float64); ok {
+					v = gb * 1024 * 1024
+				}
+			}
+			hardware[k] = v
+		}
+	}
+
+	id := newID()
+	s.desktops[id] = map[string]interface{}{
+		"id":          id,
+		"name":        name,
+		"description": body["description"],
+		"kind":        "desktop",
+		"status":      "Creating",
+		"user":        DefaultUserID,
+		"username":    DefaultUsername,
+		"category":    DefaultCategoryID,
+		"group":       DefaultGroupID,
+		"create_dict": map[string]interface{}{
+			"origin": templateID,
+		},
+		"hardware":         hardware,
+		"guest_properties": clone(template)["guest_properties"],
+		"image":            clone(template)["image"],
+		"accessed":         float64(time.Now().Unix()),
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"id": id})
+}
+
+func (s *Server) handleGetDomain(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if doc, ok := s.desktops[id]; ok {
+		s.advanceDesktop(doc)
+		writeJSON(w, http.StatusOK, clone(doc))
+		return
+	}
+	if doc, ok := s.templates[id]; ok {
+		advanceStatus(doc)
+		writeJSON(w, http.StatusOK, clone(doc))
+		return
+	}
+
+	writeError(w, http.StatusNotFound, "Domain no encontrado: "+id, "not_found")
+}
+
+func (s *Server) handleListDomains(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch kind := r.URL.Query().Get("kind"); kind {
+	case "desktop":
+		for _, doc := range s.desktops {
+			s.advanceDesktop(doc)
+		}
+		writeJSON(w, http.StatusOK, list(s.desktops))
+	case "template":
+		writeJSON(w, http.StatusOK, list(s.templates))
+	default:
+		writeError(w, http.StatusBadRequest, "Tipo de dominio no soportado: "+kind, "bad_request")
+	}
+}
+
+func (s *Server) handleDeleteDesktop(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if _, ok := s.desktops[id]; !ok {
+		writeError(w, http.StatusNotFound, "Desktop no encontrado: "+id, "not_found")
+		return
+	}
+
+	delete(s.desktops, id)
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+func (s *Server) handleUpdateDesktop(w http.ResponseWriter, r *http.Request) {
+	body, ok := readJSON(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// domain/{id} edita tanto desktops como templates
+	id := r.PathValue("id")
+	doc, ok := s.desktops[id]
+	if !ok {
+		doc, ok = s.templates[id]
+	}
+	if !ok {
+		writeError(w, http.StatusNotFound, "Desktop no encontrado: "+id, "not_found")
+		return
+	}
+
+	if name, ok := body["name"].(string); ok && name != doc["name"] && nameTaken(s.desktops, name) {
+		writeError(w, http.StatusConflict, "Ya existe un desktop con ese nombre", "desktop_exists")
+		return
+	}
+
+	if hardware, ok := body["hardware"].(map[string]interface{}); ok {
+		// Los cambios de hardware solo se aceptan con el desktop detenido
+		if doc["status"] != "Stopped" && doc["status"] != "Failed" {
+			writeError(w, http.StatusPreconditionRequired, "El desktop debe estar detenido para cambiar el hardware", "desktop_not_stopped")
+			return
+		}
+		current, _ := doc["hardware"].(map[string]interface{})
+		if current == nil {
+			current = map[string]interface{}{}
+			doc["hardware"] = current
+		}
+		for k, v := range hardware {
+			if k == "memory" {
+				if gb, ok := v.(float64); ok {
+					v = gb * 1024 * 1024
+				}
+			}
+			current[k] = v
+		}
+	}
+
+	if guestProps, ok := body["guest_properties"].(map[string]interface{}); ok {
+		current, _ := doc["guest_properties"].(map[string]interface{})
+		if current == nil {
+			current = map[string]interface{}{}
+			doc["guest_properties"] = current
+		}
+		merge(current, guestProps)
+	}
+
+	for _, field := range []string{"name", "description"} {
+		if v, ok := body[field]; ok {
+			doc[field] = v
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"id": id})
+}
+
+func (s *Server) handleStartDesktop(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	doc, ok := s.desktops[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Desktop no encontrado: "+id, "not_found")
+		return
+	}
+
+	if doc["status"] == "Stopped" || doc["status"] == "Failed" {
+		doc["status"] = "Starting"
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"id": id})
+}
+
+func (s *Server) handleStopDesktop(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	doc, ok := s.desktops[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Desktop no encontrado: "+id, "not_found")
+		return
+	}
+
+	if doc["status"] == "Started" || doc["status"] == "Starting" {
+		doc["status"] = "Stopping"
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"id": id})
+}
+
+func (s *Server) handleMultipleActions(w http.ResponseWriter, r *http.Request) {
+	body, ok := readJSON(w, r)
+	if !ok {
+		return
+	}
+
+	ids, _ := body["ids"].([]interface{})
+	action, _ := body["action"].(string)
+	if action != "stopping" {
+		writeError(w, http.StatusBadRequest, "Acción no soportada: "+action, "bad_request")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, raw := range ids {
+		id, _ := raw.(string)
+		if doc, ok := s.desktops[id]; ok && doc["status"] != "Stopped" {
+			doc["status"] = "Stopping"
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+func (s *Server) handleDesktopViewer(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	doc, ok := s.desktops[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Desktop no encontrado: "+id, "not_found")
+		return
+	}
+
+	// La ruta usa guiones (browser-vnc) y guest_properties guiones bajos (browser_vnc)
+	viewer := strings.ReplaceAll(r.PathValue("viewer"), "-", "_")
+	props, _ := doc["guest_properties"].(map[string]interface{})
+	enabled, _ := props["viewers"].(map[string]interface{})
+	if _, ok := enabled[viewer]; !ok {
+		writeError(w, http.StatusBadRequest, "Viewer no habilitado en el desktop: "+viewer, "viewer_not_enabled")
+		return
+	}
+	if doc["status"] != "Started" {
+		writeError(w, http.StatusPreconditionRequired, "El desktop debe estar arrancado para conectarse", "desktop_not_started")
+		return
+	}
+
+	host := strings.TrimPrefix(strings.TrimPrefix(s.URL, "https://"), "http://")
+	token := newID()
+	switch viewer {
+	case "browser_vnc", "browser_rdp":
+		protocol := strings.TrimPrefix(viewer, "browser_")
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"kind":     "browser",
+			"protocol": protocol,
+			"viewer":   fmt.Sprintf("%s/viewer/%s/?token=%s", s.URL, protocol, token),
+			"cookie":   token,
+		})
+	case "file_spice":
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"kind":     "file",
+			"protocol": "spice",
+			"name":     "isard-spice",
+			"ext":      "vv",
+			"mime":     "application/x-virt-viewer",
+			"content":  fmt.Sprintf("[virt-viewer]\ntype=spice\nhost=%s\npassword=%s\n", host, token),
+		})
+	default:
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"kind":     "file",
+			"protocol": "rdp",
+			"name":     "isard-" + strings.TrimPrefix(viewer, "file_"),
+			"ext":      "rdp",
+			"mime":     "application/x-rdp",
+			"content":  fmt.Sprintf("full address:s:%s\ngatewayhostname:s:%s\ngatewayaccesstoken:s:%s\n", doc["id"], host, token),
+		})
+	}
+}
+
+func (s *Server) handleGetJumperURL(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	doc, ok := s.desktops[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Desktop no encontrado: "+id, "not_found")
+		return
+	}
+
+	token, ok := doc["jumperurl"].(string)
+	if !ok {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"jumperurl": false})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"jumperurl": token})
+}
+
+func (s *Server) handleResetJumperURL(w http.ResponseWriter, r *http.Request) {
+	body, ok := readJSON(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	doc, ok := s.desktops[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Desktop no encontrado: "+id, "not_found")
+		return
+	}
+
+	if disabled, _ := body["disabled"].(bool); disabled {
+		delete(doc, "jumperurl")
+		writeJSON(w, http.StatusOK, map[string]interface{}{"jumperurl": false})
+		return
+	}
+	token := strings.ReplaceAll(newID(), "-", "")
+	doc["jumperurl"] = token
+	writeJSON(w, http.StatusOK, map[string]interface{}{"jumperurl": token})
+}
@@ -0,0 +1,44 @@
+package testserver
+
+import (
+	"net/http"
+	"sort"
+)
+
+// DefaultHypervisorID es el hipervisor sembrado por defecto
+const DefaultHypervisorID = "isard-hypervisor"
+
+// roles son los roles fijos de Isard VDI
+var roles = []map[string]interface{}{
+	{"id": "admin", "name": "Administrator", "description": "Gestión completa de la plataforma", "sortorder": 4},
+	{"id": "manager", "name": "Manager", "description": "Gestión de su categoría", "sortorder": 3},
+	{"id": "advanced", "name": "Advanced", "description": "Creación de templates y deployments", "sortorder": 2},
+	{"id": "user", "name": "User", "description": "Uso de desktops", "sortorder": 1},
+}
+
+// registerHypervisorRoutes registra los endpoints de roles e hipervisores
+func (s *Server) registerHypervisorRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v3/admin/roles", s.handleListRoles)
+	mux.HandleFunc("GET /api/v3/hypervisors", s.handleListHypervisors)
+}
+
+// AddHypervisor siembra un hipervisor
+func (s *Server) AddHypervisor(doc map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.hypervisors[doc["id"].(string)] = clone(doc)
+}
+
+func (s *Server) handleListRoles(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, roles)
+}
+
+func (s *Server) handleListHypervisors(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := list(s.hypervisors)
+	sort.Slice(out, func(i, j int) bool { return out[i]["id"].(string) < out[j]["id"].(string) })
+	writeJSON(w, http.StatusOK, out)
+}
@@ -0,0 +1,85 @@
+package testserver
+
+import (
+	"net/http"
+	"strings"
+)
+
+// registerMediaRoutes registra los endpoints de medios
+func (s *Server) registerMediaRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /api/v3/media", s.handleCreateMedia)
+	mux.HandleFunc("GET /api/v3/media", s.handleListMedia)
+	mux.HandleFunc("DELETE /api/v3/media/{id}", s.handleDeleteMedia)
+}
+
+func (s *Server) handleCreateMedia(w http.ResponseWriter, r *http.Request) {
+	body, ok := readJSON(w, r)
+	if !ok {
+		return
+	}
+
+	name, _ := body["name"].(string)
+	mediaURL, _ := body["url"].(string)
+	kind, _ := body["kind"].(string)
+	if name == "" || mediaURL == "" {
+		writeError(w, http.StatusBadRequest, "name y url son obligatorios", "bad_request")
+		return
+	}
+	if !strings.HasPrefix(mediaURL, "http://") && !strings.HasPrefix(mediaURL, "https://") {
+		writeError(w, http.StatusBadRequest, "La URL debe ser http o https", "invalid_url")
+		return
+	}
+	if kind != "iso" && kind != "floppy" {
+		writeError(w, http.StatusBadRequest, "kind debe ser iso o floppy", "bad_request")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := newID()
+	s.medias[id] = map[string]interface{}{
+		"id":          id,
+		"name":        name,
+		"description": body["description"],
+		"url-web":     mediaURL,
+		"kind":        kind,
+		"status":      "Downloading",
+		"user":        DefaultUserID,
+		"category":    DefaultCategoryID,
+		"group":       DefaultGroupID,
+		"allowed":     body["allowed"],
+		"icon":        "fa-circle-o",
+		"progress":    map[string]interface{}{"received_percent": 0},
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+func (s *Server) handleListMedia(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, doc := range s.medias {
+		advanceStatus(doc)
+		if doc["status"] == "Downloaded" {
+			doc["progress"] = map[string]interface{}{"received_percent": 100}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, list(s.medias))
+}
+
+func (s *Server) handleDeleteMedia(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if _, ok := s.medias[id]; !ok {
+		writeError(w, http.StatusNotFound, "Media no encontrado: "+id, "not_found")
+		return
+	}
+
+	delete(s.medias, id)
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
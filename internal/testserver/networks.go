@@ -0,0 +1,137 @@
+package testserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// firstMetadataID es el primer metadata_id asignado a las redes de usuario. La API
+// devuelve enteros de 64 bits que no caben en un float64 sin perder precisión.
+const firstMetadataID = 4611686018427387904
+
+// registerNetworkRoutes registra los endpoints de redes de usuario
+func (s *Server) registerNetworkRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v3/user/networks", s.handleListNetworks)
+	mux.HandleFunc("POST /api/v3/user/networks", s.handleCreateNetwork)
+	mux.HandleFunc("GET /api/v3/user/networks/{id}", s.handleGetNetwork)
+	mux.HandleFunc("PUT /api/v3/user/networks/{id}", s.handleUpdateNetwork)
+	mux.HandleFunc("DELETE /api/v3/user/networks/{id}", s.handleDeleteNetwork)
+}
+
+// Network devuelve una copia de la red de usuario almacenada
+func (s *Server) Network(id string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.networks[id]
+	if !ok {
+		return nil, false
+	}
+	return clone(doc), true
+}
+
+func (s *Server) handleCreateNetwork(w http.ResponseWriter, r *http.Request) {
+	body, ok := readJSON(w, r)
+	if !ok {
+		return
+	}
+
+	name, _ := body["name"].(string)
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "name es obligatorio", "bad_request")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if nameTaken(s.networks, name) {
+		writeError(w, http.StatusConflict, "Ya existe una red con ese nombre", "network_exists")
+		return
+	}
+
+	model, _ := body["model"].(string)
+	if model == "" {
+		model = "virtio"
+	}
+
+	id := newID()
+	s.networks[id] = map[string]interface{}{
+		"id":          id,
+		"name":        name,
+		"description": body["description"],
+		"model":       model,
+		"qos_id":      body["qos_id"],
+		"allowed":     body["allowed"],
+		"user":        DefaultUserID,
+		"group":       DefaultGroupID,
+		"category":    DefaultCategoryID,
+		"metadata_id": json.Number(strconv.FormatUint(firstMetadataID+uint64(len(s.networks)), 10)),
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"id": id})
+}
+
+func (s *Server) handleListNetworks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Sin list(): la copia por JSON perdería la precisión de metadata_id
+	out := make([]map[string]interface{}, 0, len(s.networks))
+	for _, doc := range s.networks {
+		out = append(out, doc)
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleGetNetwork(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	doc, ok := s.networks[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Red no encontrada: "+id, "not_found")
+		return
+	}
+
+	// Sin clone: la copia por JSON perdería la precisión de metadata_id
+	writeJSON(w, http.StatusOK, doc)
+}
+
+func (s *Server) handleUpdateNetwork(w http.ResponseWriter, r *http.Request) {
+	body, ok := readJSON(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	doc, ok := s.networks[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Red no encontrada: "+id, "not_found")
+		return
+	}
+
+	delete(body, "id")
+	delete(body, "metadata_id")
+	merge(doc, body)
+	writeJSON(w, http.StatusOK, map[string]interface{}{"id": id})
+}
+
+func (s *Server) handleDeleteNetwork(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if _, ok := s.networks[id]; !ok {
+		writeError(w, http.StatusNotFound, "Red no encontrada: "+id, "not_found")
+		return
+	}
+
+	delete(s.networks, id)
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
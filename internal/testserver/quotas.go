@@ -0,0 +1,131 @@
+package testserver
+
+import (
+	"net/http"
+)
+
+// registerQuotaRoutes registra los endpoints de cuotas y límites de categorías, grupos y usuarios
+func (s *Server) registerQuotaRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v3/admin/quota/{kind}/{id}", s.handleGetQuota)
+	mux.HandleFunc("PUT /api/v3/admin/quota/{kind}/{id}", s.handleUpdateQuota)
+	mux.HandleFunc("PUT /api/v3/admin/limits/{kind}/{id}", s.handleUpdateLimits)
+}
+
+// quotaTarget devuelve el documento de la entidad indicada en la ruta o responde 404.
+// Debe llamarse con mu bloqueado.
+func (s *Server) quotaTarget(w http.ResponseWriter, r *http.Request) (map[string]interface{}, bool) {
+	var collection map[string]map[string]interface{}
+	switch r.PathValue("kind") {
+	case "category":
+		collection = s.categories
+	case "group":
+		collection = s.groups
+	case "user":
+		collection = s.users
+	default:
+		writeError(w, http.StatusBadRequest, "Tipo no válido: "+r.PathValue("kind"), "bad_request")
+		return nil, false
+	}
+
+	id := r.PathValue("id")
+	doc, ok := collection[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Elemento no encontrado: "+id, "not_found")
+	}
+	return doc, ok
+}
+
+// quotaMembers devuelve los usuarios (y, para una categoría, los grupos) a los que se
+// propaga la cuota de la entidad. Debe llamarse con mu bloqueado.
+func (s *Server) quotaMembers(kind, id string) []map[string]interface{} {
+	var members []map[string]interface{}
+	switch kind {
+	case "category":
+		for _, group := range s.groups {
+			if group["parent_category"] == id {
+				members = append(members, group)
+			}
+		}
+		for _, user := range s.users {
+			if user["category"] == id {
+				members = append(members, user)
+			}
+		}
+	case "group":
+		for _, user := range s.users {
+			if user["group"] == id {
+				members = append(members, user)
+			}
+		}
+	}
+	return members
+}
+
+// quotaValue devuelve el valor almacenado de cuota o límites (false si no hay)
+func quotaValue(doc map[string]interface{}, field string) interface{} {
+	if v, ok := doc[field]; ok && v != nil {
+		return v
+	}
+	return false
+}
+
+func (s *Server) handleGetQuota(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.quotaTarget(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"quota":  quotaValue(doc, "quota"),
+		"limits": quotaValue(doc, "limits"),
+	})
+}
+
+func (s *Server) handleUpdateQuota(w http.ResponseWriter, r *http.Request) {
+	s.updateQuotaField(w, r, "quota")
+}
+
+func (s *Server) handleUpdateLimits(w http.ResponseWriter, r *http.Request) {
+	if r.PathValue("kind") == "user" {
+		writeError(w, http.StatusBadRequest, "Los usuarios no tienen límites", "bad_request")
+		return
+	}
+	s.updateQuotaField(w, r, "limits")
+}
+
+// updateQuotaField guarda la cuota o los límites enviados y, con propagate, los copia
+// a los miembros de la entidad
+func (s *Server) updateQuotaField(w http.ResponseWriter, r *http.Request, field string) {
+	body, ok := readJSON(w, r)
+	if !ok {
+		return
+	}
+	value, ok := body[field]
+	if !ok {
+		writeError(w, http.StatusBadRequest, field+" es obligatorio", "bad_request")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.quotaTarget(w, r)
+	if !ok {
+		return
+	}
+
+	doc[field] = value
+	if propagate, _ := body["propagate"].(bool); propagate {
+		for _, member := range s.quotaMembers(r.PathValue("kind"), r.PathValue("id")) {
+			if field == "limits" && member["role"] != nil {
+				continue // los usuarios no tienen límites
+			}
+			member[field] = value
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
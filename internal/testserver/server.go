@@ -0,0 +1,441 @@
+// Package testserver implementa un servidor falso de la API v3 de Isard VDI sobre
+// net/http/httptest. Mantiene el estado en memoria y emula las transiciones de estado
+// más habituales (Creating → Stopped, Stopping → Stopped, Downloading → Downloaded...)
+// para poder probar el cliente y el provider sin una instalación real.
+package testserver
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Credenciales y valores sembrados por defecto en un servidor nuevo
+const (
+	DefaultUsername   = "admin"
+	DefaultPassword   = "IsardVDI"
+	DefaultCategoryID = "default"
+	DefaultToken      = "testserver-static-token"
+	DefaultTemplateID = "_template_ubuntu"
+	DefaultGroupID    = "default-default"
+	DefaultUserID     = "local-default-admin-admin"
+)
+
+// Request es una petición recibida por el servidor, para inspeccionar el payload enviado
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// JSON decodifica el cuerpo de la petición como objeto JSON
+func (r Request) JSON() map[string]interface{} {
+	var out map[string]interface{}
+	_ = json.Unmarshal(r.Body, &out)
+	return out
+}
+
+// fault es un error inyectado que se devuelve en lugar de procesar la petición
+type fault struct {
+	method string
+	path   string
+	status int
+	times  int
+}
+
+// Server es un servidor falso de la API de Isard VDI
+type Server struct {
+	*httptest.Server
+
+	// Username y Password son las credenciales aceptadas por el login por formulario
+	Username string
+	Password string
+	// TokenTTL es la validez de los tokens emitidos por el login
+	TokenTTL time.Duration
+
+	mu       sync.Mutex
+	tokens   map[string]time.Time
+	requests []Request
+	faults   []*fault
+	seq      int
+
+	desktops    map[string]map[string]interface{}
+	deployments map[string]map[string]interface{}
+	templates   map[string]map[string]interface{}
+	medias      map[string]map[string]interface{}
+	networks    map[string]map[string]interface{}
+	tables      map[string]map[string]map[string]interface{}
+	users       map[string]map[string]interface{}
+	groups      map[string]map[string]interface{}
+	categories  map[string]map[string]interface{}
+	hypervisors map[string]map[string]interface{}
+	holds       map[string]int
+}
+
+// New arranca un servidor HTTP falso con un template, un grupo y un usuario administrador
+func New() *Server {
+	s := newServer()
+	s.Server = httptest.NewServer(s.handler())
+	return s
+}
+
+// NewTLS arranca un servidor HTTPS falso con un certificado autofirmado
+func NewTLS() *Server {
+	s := newServer()
+	s.Server = httptest.NewTLSServer(s.handler())
+	return s
+}
+
+func newServer() *Server {
+	s := &Server{
+		Username:    DefaultUsername,
+		Password:    DefaultPassword,
+		TokenTTL:    time.Hour,
+		tokens:      map[string]time.Time{DefaultToken: {}},
+		desktops:    map[string]map[string]interface{}{},
+		deployments: map[string]map[string]interface{}{},
+		templates:   map[string]map[string]interface{}{},
+		medias:      map[string]map[string]interface{}{},
+		networks:    map[string]map[string]interface{}{},
+		tables: map[string]map[string]map[string]interface{}{
+			"interfaces": {},
+			"qos_net":    {},
+		},
+		users:       map[string]map[string]interface{}{},
+		groups:      map[string]map[string]interface{}{},
+		categories:  map[string]map[string]interface{}{},
+		hypervisors: map[string]map[string]interface{}{},
+		holds:       map[string]int{},
+	}
+
+	s.AddCategory(map[string]interface{}{
+		"id":          DefaultCategoryID,
+		"name":        "Default",
+		"description": "Categoría por defecto",
+		"frontend":    false,
+	})
+
+	s.AddGroup(map[string]interface{}{
+		"id":              DefaultGroupID,
+		"name":            "Default",
+		"description":     "Grupo por defecto",
+		"parent_category": DefaultCategoryID,
+		"linked_groups":   []interface{}{},
+	})
+	s.AddUser(map[string]interface{}{
+		"id":       DefaultUserID,
+		"name":     "Administrator",
+		"username": DefaultUsername,
+		"uid":      DefaultUsername,
+		"email":    "admin@isardvdi.local",
+		"active":   true,
+		"role":     "admin",
+		"category": DefaultCategoryID,
+		"group":    DefaultGroupID,
+		"provider": "local",
+	})
+	s.AddHypervisor(map[string]interface{}{
+		"id":           DefaultHypervisorID,
+		"hostname":     "isard-hypervisor",
+		"description":  "Hipervisor por defecto",
+		"status":       "Online",
+		"enabled":      true,
+		"only_forced":  false,
+		"gpu_only":     false,
+		"capabilities": map[string]interface{}{"hypervisor": true, "disk_operations": true},
+	})
+	s.AddTemplate(map[string]interface{}{
+		"id":          DefaultTemplateID,
+		"name":        "Ubuntu 22.04",
+		"description": "Template de pruebas",
+		"category":    DefaultCategoryID,
+		"group":       DefaultGroupID,
+		"user_id":     DefaultUserID,
+		"enabled":     true,
+		"status":      "Stopped",
+		"hardware": map[string]interface{}{
+			"vcpus":      2,
+			"memory":     4 * 1024 * 1024, // KiB, como en la API real
+			"boot_order": []interface{}{"disk"},
+			"disk_bus":   "virtio",
+			"videos":     []interface{}{"default"},
+			"interfaces": []interface{}{"default"},
+			"isos":       []interface{}{},
+			"floppies":   []interface{}{},
+		},
+		"guest_properties": map[string]interface{}{
+			"credentials": map[string]interface{}{"username": "isard", "password": "pirineus"},
+			"fullscreen":  false,
+			"viewers":     map[string]interface{}{"browser_vnc": map[string]interface{}{"options": nil}},
+		},
+		"image": map[string]interface{}{"type": "user"},
+	})
+
+	return s
+}
+
+// Endpoint devuelve la URL base del servidor, válida como `endpoint` del provider
+func (s *Server) Endpoint() string {
+	return s.URL
+}
+
+// Requests devuelve una copia de las peticiones recibidas hasta el momento
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]Request, len(s.requests))
+	copy(out, s.requests)
+	return out
+}
+
+// LastRequest devuelve la última petición recibida con el método y la ruta indicados
+func (s *Server) LastRequest(method, path string) (Request, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := len(s.requests) - 1; i >= 0; i-- {
+		if s.requests[i].Method == method && s.requests[i].Path == path {
+			return s.requests[i], true
+		}
+	}
+	return Request{}, false
+}
+
+// FailNext hace que las próximas `times` peticiones cuyo método coincida y cuya ruta
+// empiece por pathPrefix respondan con el código de estado indicado
+func (s *Server) FailNext(method, pathPrefix string, status, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &fault{method: method, path: pathPrefix, status: status, times: times})
+}
+
+// ExpireTokens invalida los tokens emitidos por el login (el token estático sigue siendo válido)
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for token := range s.tokens {
+		if token != DefaultToken {
+			delete(s.tokens, token)
+		}
+	}
+}
+
+// handler construye el router de la API falsa
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /authentication/login", s.handleLogin)
+
+	s.registerDesktopRoutes(mux)
+	s.registerDeploymentRoutes(mux)
+	s.registerTemplateRoutes(mux)
+	s.registerMediaRoutes(mux)
+	s.registerNetworkRoutes(mux)
+	s.registerAdminRoutes(mux)
+	s.registerCategoryRoutes(mux)
+	s.registerQuotaRoutes(mux)
+	s.registerHypervisorRoutes(mux)
+
+	return s.middleware(mux)
+}
+
+// middleware registra la petición, aplica los fallos inyectados y comprueba la autenticación
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		s.mu.Lock()
+		s.seq++
+		requestID := fmt.Sprintf("req-%06d", s.seq)
+		s.requests = append(s.requests, Request{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  r.URL.Query(),
+			Header: r.Header.Clone(),
+			Body:   body,
+		})
+		status := s.takeFault(r)
+		s.mu.Unlock()
+
+		w.Header().Set("X-Request-Id", requestID)
+
+		if status != 0 {
+			writeError(w, status, http.StatusText(status), "injected_fault")
+			return
+		}
+
+		if r.URL.Path != "/authentication/login" && !s.authorized(r) {
+			writeError(w, http.StatusUnauthorized, "Token inválido o caducado", "unauthorized")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// takeFault consume un fallo inyectado que coincida con la petición. Debe llamarse con mu bloqueado.
+func (s *Server) takeFault(r *http.Request) int {
+	for i, f := range s.faults {
+		if f.method != r.Method || !strings.HasPrefix(r.URL.Path, f.path) {
+			continue
+		}
+		f.times--
+		if f.times <= 0 {
+			s.faults = append(s.faults[:i], s.faults[i+1:]...)
+		}
+		return f.status
+	}
+	return 0
+}
+
+// authorized comprueba la cabecera Authorization contra los tokens vigentes
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exp, ok := s.tokens[token]
+	return ok && (exp.IsZero() || time.Now().Before(exp))
+}
+
+// handleLogin emula el login por formulario: devuelve un JWT en texto plano
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("provider") != "form" {
+		writeError(w, http.StatusBadRequest, "Proveedor de autenticación no soportado", "bad_request")
+		return
+	}
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, "Formulario inválido", "bad_request")
+		return
+	}
+	if r.FormValue("username") != s.Username || r.FormValue("password") != s.Password {
+		writeError(w, http.StatusUnauthorized, "Usuario o contraseña incorrectos", "invalid_credentials")
+		return
+	}
+
+	s.mu.Lock()
+	exp := time.Now().Add(s.TokenTTL)
+	token := newJWT(exp, newID())
+	s.tokens[token] = exp
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain")
+	_, _ = w.Write([]byte(token))
+}
+
+// newJWT genera un token con formato JWT (sin firma real) con el claim exp indicado
+func newJWT(exp time.Time, subject string) string {
+	enc := base64.RawURLEncoding
+	header := enc.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	claims, _ := json.Marshal(map[string]interface{}{
+		"exp": exp.Unix(),
+		"sub": subject,
+	})
+	return header + "." + enc.EncodeToString(claims) + "." + enc.EncodeToString([]byte("testserver"))
+}
+
+// newID genera un identificador con formato UUID
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	h := hex.EncodeToString(b)
+	return fmt.Sprintf("%s-%s-%s-%s-%s", h[0:8], h[8:12], h[12:16], h[16:20], h[20:32])
+}
+
+// writeJSON serializa v como respuesta JSON
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError responde con el formato de error de la API de Isard VDI
+func writeError(w http.ResponseWriter, status int, msg, descriptionCode string) {
+	code := strings.ToLower(strings.ReplaceAll(http.StatusText(status), " ", "_"))
+	writeJSON(w, status, map[string]interface{}{
+		"error":            code,
+		"msg":              msg,
+		"description_code": descriptionCode,
+		"request_id":       w.Header().Get("X-Request-Id"),
+	})
+}
+
+// readJSON decodifica el cuerpo JSON de la petición
+func readJSON(w http.ResponseWriter, r *http.Request) (map[string]interface{}, bool) {
+	var body map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "JSON inválido: "+err.Error(), "bad_request")
+		return nil, false
+	}
+	return body, true
+}
+
+// advanceStatus aplica la siguiente transición de estado pendiente del documento.
+// Cada lectura avanza un paso, de forma que los bucles de espera del cliente convergen.
+func advanceStatus(doc map[string]interface{}) {
+	next := map[string]string{
+		"Creating":    "Stopped",
+		"Starting":    "Started",
+		"Stopping":    "Stopped",
+		"Downloading": "Downloaded",
+	}
+	if status, ok := doc["status"].(string); ok {
+		if n, ok := next[status]; ok {
+			doc["status"] = n
+		}
+	}
+}
+
+// clone devuelve una copia profunda de un documento JSON
+func clone(doc map[string]interface{}) map[string]interface{} {
+	data, _ := json.Marshal(doc)
+	var out map[string]interface{}
+	_ = json.Unmarshal(data, &out)
+	return out
+}
+
+// merge copia en dst los campos de src
+func merge(dst, src map[string]interface{}) {
+	for k, v := range src {
+		dst[k] = v
+	}
+}
+
+// nameTaken indica si ya existe un documento con el mismo nombre en la colección
+func nameTaken(collection map[string]map[string]interface{}, name string) bool {
+	for _, doc := range collection {
+		if doc["name"] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// list devuelve los documentos de una colección como lista
+func list(collection map[string]map[string]interface{}) []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(collection))
+	for _, doc := range collection {
+		out = append(out, clone(doc))
+	}
+	return out
+}
@@ -0,0 +1,163 @@
+package testserver
+
+import (
+	"net/http"
+	"sort"
+)
+
+// registerTemplateRoutes registra los endpoints de creación y gestión de templates
+func (s *Server) registerTemplateRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /api/v3/template", s.handleCreateTemplate)
+	mux.HandleFunc("PUT /api/v3/template/update", s.handleUpdateTemplate)
+	mux.HandleFunc("GET /api/v3/template/derivatives/{id}", s.handleTemplateDerivatives)
+	mux.HandleFunc("DELETE /api/v3/template/{id}", s.handleDeleteTemplate)
+}
+
+// Template devuelve una copia del template almacenado
+func (s *Server) Template(id string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.templates[id]
+	if !ok {
+		return nil, false
+	}
+	return clone(doc), true
+}
+
+func (s *Server) handleCreateTemplate(w http.ResponseWriter, r *http.Request) {
+	body, ok := readJSON(w, r)
+	if !ok {
+		return
+	}
+
+	name, _ := body["name"].(string)
+	desktopID, _ := body["desktop_id"].(string)
+	if name == "" || desktopID == "" {
+		writeError(w, http.StatusBadRequest, "name y desktop_id son obligatorios", "bad_request")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	desktop, ok := s.desktops[desktopID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Desktop no encontrado: "+desktopID, "not_found")
+		return
+	}
+	if desktop["status"] != "Stopped" && desktop["status"] != "Failed" {
+		writeError(w, http.StatusPreconditionRequired, "El desktop debe estar detenido para crear un template", "desktop_not_stopped")
+		return
+	}
+	if nameTaken(s.templates, name) {
+		writeError(w, http.StatusConflict, "Ya existe un template con ese nombre", "template_exists")
+		return
+	}
+
+	source := clone(desktop)
+	enabled, _ := body["enabled"].(bool)
+	id := newID()
+	s.templates[id] = map[string]interface{}{
+		"id":          id,
+		"name":        name,
+		"description": body["description"],
+		"kind":        "template",
+		"status":      "Creating",
+		"enabled":     enabled,
+		"allowed":     body["allowed"],
+		"category":    source["category"],
+		"group":       source["group"],
+		"user_id":     source["user"],
+		"create_dict": map[string]interface{}{
+			"origin": desktopID,
+		},
+		"hardware":         source["hardware"],
+		"guest_properties": source["guest_properties"],
+		"image":            source["image"],
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"id": id})
+}
+
+func (s *Server) handleUpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	body, ok := readJSON(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, _ := body["id"].(string)
+	doc, ok := s.templates[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Template no encontrado: "+id, "not_found")
+		return
+	}
+
+	if name, ok := body["name"].(string); ok && name != doc["name"] && nameTaken(s.templates, name) {
+		writeError(w, http.StatusConflict, "Ya existe un template con ese nombre", "template_exists")
+		return
+	}
+
+	for _, field := range []string{"name", "description", "enabled", "allowed"} {
+		if v, ok := body[field]; ok {
+			doc[field] = v
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"id": id})
+}
+
+func (s *Server) handleTemplateDerivatives(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if _, ok := s.templates[id]; !ok {
+		writeError(w, http.StatusNotFound, "Template no encontrado: "+id, "not_found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.templateDerivatives(id))
+}
+
+func (s *Server) handleDeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if _, ok := s.templates[id]; !ok {
+		writeError(w, http.StatusNotFound, "Template no encontrado: "+id, "not_found")
+		return
+	}
+
+	// Como la API real, eliminar un template elimina también los desktops derivados
+	for _, derivative := range s.templateDerivatives(id) {
+		delete(s.desktops, derivative["id"].(string))
+	}
+	delete(s.templates, id)
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+// templateDerivatives devuelve los desktops creados a partir del template, ordenados por ID
+func (s *Server) templateDerivatives(templateID string) []map[string]interface{} {
+	derivatives := []map[string]interface{}{}
+	for id, desktop := range s.desktops {
+		createDict, _ := desktop["create_dict"].(map[string]interface{})
+		if createDict["origin"] != templateID {
+			continue
+		}
+		derivatives = append(derivatives, map[string]interface{}{
+			"id":   id,
+			"name": desktop["name"],
+			"kind": desktop["kind"],
+			"user": desktop["user"],
+		})
+	}
+	sort.Slice(derivatives, func(i, j int) bool {
+		return derivatives[i]["id"].(string) < derivatives[j]["id"].(string)
+	})
+	return derivatives
+}